@@ -33,24 +33,25 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 	"github.com/dhowlett99/dmxlights/pkg/buttons"
+	"github.com/dhowlett99/dmxlights/pkg/clock"
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
 	"github.com/dhowlett99/dmxlights/pkg/dmx"
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
 	"github.com/dhowlett99/dmxlights/pkg/gui"
 	"github.com/dhowlett99/dmxlights/pkg/launchpad"
 	"github.com/dhowlett99/dmxlights/pkg/pattern"
 	"github.com/dhowlett99/dmxlights/pkg/presets"
+	"github.com/dhowlett99/dmxlights/pkg/project"
 	"github.com/dhowlett99/dmxlights/pkg/sequence"
 	"github.com/dhowlett99/dmxlights/pkg/sound"
 	"github.com/oliread/usbdmx/ft232"
 )
 
 const debug = false
-const NumberOfSequences = 5
-const NumberOfFixtures = 8
 const NumberOfSwitches = 8
 
-const DEFAULT_PROJECT = "Default.yaml"
+const DEFAULT_PROJECT = "Default"
 
 func main() {
 
@@ -74,21 +75,56 @@ func main() {
 		desk.SetSystemTrayMenu(menu)
 	}
 
+	// Find the project last opened or saved, everything saved for the show is kept in its directory.
+	fmt.Println("Open Project")
+	projectDir, err := project.Find(project.Last(DEFAULT_PROJECT))
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to open project: %s\n", err.Error())
+		os.Exit(1)
+	}
+	project.SetCurrent(projectDir)
+
+	// Read sequences config file
+	fmt.Println("Load Sequences Config File")
+	sequencesConfig, err := sequence.LoadSequences(project.File(project.SEQUENCES_FILE))
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to load sequences config: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	// Read the settings kept with the project.
+	settings, err := project.LoadSettings()
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to load settings: %s\n", err.Error())
+	}
+
+	// Make sure we can display all the sequences on the launchpad.
+	err = sequence.CheckSequencesFitLaunchpad(sequencesConfig)
+	if err != nil {
+		fmt.Printf("dmxlights: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	// The number of sequences is set by the sequences config file.
+	NumberOfSequences := len(sequencesConfig.Sequences)
+
 	// Setup the current state.
 	this := buttons.CurrentState{}
 	this.MyWindow = myWindow                                       // Pointer to main window.
 	this.Blackout = false                                          // Blackout starts in off.
+	this.BlackoutFadeTime = settings.BlackoutFadeTime              // Blackout button is a dead blackout until a fade time is set.
+	this.BlackoutReleaseTime = settings.BlackoutReleaseTime        // Blackout button comes straight back until a release time is set.
 	this.Flood = false                                             // Flood starts in off.
-	this.Running = make(map[int]bool, NumberOfSequences)           // Initialise storage for four sequences.
-	this.Strobe = make(map[int]bool, NumberOfSequences)            // Initialise storage for four sequences.
+	this.Running = make(map[int]bool, NumberOfSequences)           // Initialise storage for each sequence.
+	this.Strobe = make(map[int]bool, NumberOfSequences)            // Initialise storage for each sequence.
 	this.MasterBrightness = 255                                    // Affects all DMX fixtures and launchpad lamps.
 	this.SoundGain = 0                                             // Fine gain -0.09 -> 0.09
 	this.OffsetPan = common.SCANNER_MID_POINT                      // Start pan from the center
 	this.OffsetTilt = common.SCANNER_MID_POINT                     // Start tilt from the center.
 	this.RGBPatterns = pattern.MakePatterns()                      // Build the default set of Patterns.
-	this.SelectButtonPressed = make([]bool, NumberOfSequences)     // Initialise four select buttons.
-	this.SelectedMode = make([]int, NumberOfSequences)             // Initialise four mode variables.
-	this.LastMode = make([]int, NumberOfSequences)                 // Initialise four mode variables.
+	this.SelectButtonPressed = make([]bool, NumberOfSequences)     // Initialise a select button for each sequence.
+	this.SelectedMode = make([]int, NumberOfSequences)             // Initialise a mode variable for each sequence.
+	this.LastMode = make([]int, NumberOfSequences)                 // Initialise a mode variable for each sequence.
 	this.ShowRGBColorPicker = false                                // Remember when we are in editing sequence colors mode.
 	this.EditScannerColorsMode = false                             // Remember when we are in setting scanner color mode.
 	this.EditGoboSelectionMode = false                             // Remember when we are in selecting gobo mode.
@@ -97,17 +133,19 @@ func main() {
 	this.SequenceType = make([]string, NumberOfSequences)          // Remember sequence type.
 	this.EditPatternMode = false                                   // Remember when we are in editing pattern mode.
 	this.StaticButtons = makeStaticButtonsStorage()                // Make storgage for color editing button results.
-	this.PresetsStore = presets.LoadPresets()                      // Load the presets from their json files.
-	this.Speed = make(map[int]int, NumberOfSequences)              // Initialise storage for four sequences.
-	this.RGBSize = make(map[int]int, NumberOfSequences)            // Initialise storage for four sequences.
-	this.ScannerSize = make(map[int]int, NumberOfSequences)        // Initialise storage for four sequences.
-	this.RGBShift = make(map[int]int, NumberOfSequences)           // Initialise storage for four sequences.
-	this.ScannerShift = make(map[int]int, NumberOfSequences)       // Initialise storage for four sequences.
-	this.RGBFade = make(map[int]int, NumberOfSequences)            // Initialise storage for four sequences.
-	this.ScannerFade = make(map[int]int, NumberOfSequences)        // Initialise storage for four sequences.
-	this.StrobeSpeed = make(map[int]int, NumberOfSequences)        // Initialise storage for four sequences.
-	this.ClearPressed = make(map[int]bool, NumberOfSequences)      // Initialise storage for four sequences.
-	this.ScannerChaser = make(map[int]bool, NumberOfSequences)     // Initialise storage for four sequences.
+	this.Speed = make(map[int]int, NumberOfSequences)              // Initialise storage for each sequence.
+	this.RGBSize = make(map[int]int, NumberOfSequences)            // Initialise storage for each sequence.
+	this.ScannerSize = make(map[int]int, NumberOfSequences)        // Initialise storage for each sequence.
+	this.RGBShift = make(map[int]int, NumberOfSequences)           // Initialise storage for each sequence.
+	this.ScannerShift = make(map[int]int, NumberOfSequences)       // Initialise storage for each sequence.
+	this.RGBFade = make(map[int]int, NumberOfSequences)            // Initialise storage for each sequence.
+	this.ScannerFade = make(map[int]int, NumberOfSequences)        // Initialise storage for each sequence.
+	this.StrobeSpeed = make(map[int]int, NumberOfSequences)        // Initialise storage for each sequence.
+	this.StepOrder = make(map[int]int, NumberOfSequences)          // Initialise storage for each sequence.
+	this.Swing = make(map[int]int, NumberOfSequences)              // Initialise storage for each sequence.
+	this.ClearPressed = make(map[int]bool, NumberOfSequences)      // Initialise storage for each sequence.
+	this.ScannerChaser = make(map[int]bool, NumberOfSequences)     // Initialise storage for each sequence.
+	this.FixturePage = make(map[int]int, NumberOfSequences)        // Page of fixtures shown on the launchpad for each sequence.
 	this.ScannerCoordinates = make(map[int]int, NumberOfSequences) // Number of coordinates for scanner patterns is selected from 4 choices. 0=12, 1=16,2=24,3=32,4=64
 	this.LaunchPadConnected = true                                 // Assume launchpad is present, until tested.
 	this.DmxInterfacePresent = true                                // Assume DMX interface card is present, until tested.
@@ -115,6 +153,14 @@ func main() {
 	this.Functions = make(map[int][]common.Function)               // Array holding functions for each sequence.
 	this.SavedSequenceColors = make(map[int][]common.Color)        // Array holding saved sequence colors for each sequence. Used by the color picker.
 
+	// Load the presets saved in the project, a problem with them is shown once the window is up.
+	// Until the user says otherwise the presets aren't saved, so the file isn't overwritten.
+	this.PresetsStore, err = presets.LoadPresets()
+	presetsErr := err
+	if presetsErr != nil {
+		fmt.Printf("dmxlights: error failed to load presets: %s\n", presetsErr.Error())
+	}
+
 	// Now add channels to communicate with mini-sequencers on switch channels.
 	this.SwitchChannels = []common.SwitchChannel{}
 	for switchChannel := 0; switchChannel < 10; switchChannel++ {
@@ -126,12 +172,13 @@ func main() {
 		newSwitch.StopFadeDown = make(chan bool)
 		this.SwitchChannels = append(this.SwitchChannels, newSwitch)
 	}
-	// Initialize eight fixture states for the four sequences.
+	// Initialize a page of fixture states for each sequence.
+	// Sequences with more fixtures get the rest when the sequences are setup.
 	this.FixtureState = make([][]common.FixtureState, NumberOfSequences)
 	// Populate each sequence with fixtures.
 	for sequenceNumber := 0; sequenceNumber < NumberOfSequences; sequenceNumber++ {
-		this.FixtureState[sequenceNumber] = make([]common.FixtureState, NumberOfFixtures)
-		for fixtureNumber := 0; fixtureNumber < NumberOfFixtures; fixtureNumber++ {
+		this.FixtureState[sequenceNumber] = make([]common.FixtureState, common.FIXTURES_PER_PAGE)
+		for fixtureNumber := 0; fixtureNumber < common.FIXTURES_PER_PAGE; fixtureNumber++ {
 			newFixture := common.FixtureState{}
 			newFixture.Enabled = true
 			newFixture.RGBInverted = false
@@ -154,7 +201,10 @@ func main() {
 	go func() {
 		<-c
 		fmt.Println("Saving Presets")
-		presets.SavePresets(this.PresetsStore)
+		err := presets.SavePresets(this.PresetsStore)
+		if err != nil {
+			fmt.Printf("dmxlights: error failed to save presets: %s\n", err.Error())
+		}
 		os.Exit(1)
 	}()
 
@@ -202,23 +252,18 @@ func main() {
 		}
 	}
 
-	// Read sequences config file
-	fmt.Println("Load Sequences Config File")
-	sequencesConfig, err := sequence.LoadSequences()
-	if err != nil {
-		fmt.Printf("dmxlights: error failed to load sequences config: %s\n", err.Error())
-		os.Exit(1)
-	}
-
 	// Get a list of all the fixtures in the groups.
-	fixturesConfig, err := fixture.LoadFixtures(DEFAULT_PROJECT)
+	fixturesConfig, err := fixture.LoadFixtures(project.File(project.FIXTURES_FILE))
 	if err != nil {
 		fmt.Printf("dmxlights: error failed to load fixtures: %s\n", err.Error())
 		os.Exit(1)
 	}
 
+	// Tell the output stage which channels are dimmers and how switches merge with sequences.
+	fixture.RegisterMergeChannels(fixturesConfig)
+
 	// Load groups.
-	groupConfig, err := fixture.LoadFixtureGroups("groups.yaml")
+	groupConfig, err := fixture.LoadFixtureGroups(project.File(project.GROUPS_FILE))
 	if err != nil {
 		fmt.Printf("dmxlights: error failed to load groups: %s\n", err.Error())
 		os.Exit(1)
@@ -254,8 +299,9 @@ func main() {
 	startConfig := &fixture.Fixtures{}
 	startConfig.Fixtures = []fixture.Fixture{}
 	startConfig.Fixtures = append(startConfig.Fixtures, fixturesConfig.Fixtures...)
+	startConfig.CueLists = cues.CopyCueLists(fixturesConfig.CueLists)
 
-	myWindow.SetTitle("DMX Lights:" + DEFAULT_PROJECT)
+	myWindow.SetTitle("DMX Lights:" + project.Name(project.Current()))
 
 	// If you try to quit without saving your changed project. Uses startConfig as a ref to determine changes.
 	myWindow.SetCloseIntercept(func() {
@@ -281,14 +327,30 @@ func main() {
 		newSequence.Description = sequenceConf.Description
 		newSequence.Label = sequenceConf.Label
 		newSequence.Type = sequenceConf.Type
+		newSequence.PatternFit = sequenceConf.PatternFit
+		if newSequence.PatternFit == "" {
+			newSequence.PatternFit = common.PATTERN_FIT_SCALE
+		}
+
+		// Set this sequence's merge priority in the output stage.
+		dmx.SetSourcePriority(dmx.SequenceSource(sequenceNumber), sequenceConf.Priority)
 
 		sequences = append(sequences, &newSequence)
 
+		// Sequences with more than eight fixtures need more fixture states.
+		for fixtureNumber := len(this.FixtureState[sequenceNumber]); fixtureNumber < newSequence.NumberFixtures; fixtureNumber++ {
+			newFixture := common.FixtureState{}
+			newFixture.Enabled = true
+			this.FixtureState[sequenceNumber] = append(this.FixtureState[sequenceNumber], newFixture)
+		}
+
 		// Setup Default State.
 		this.Speed[sequenceNumber] = common.DEFAULT_SPEED                            // Selected speed for the sequence. Common to all types of sequence.
 		this.Running[sequenceNumber] = false                                         // Set this sequence to be in the not running state. Common to all types of sequence.
 		this.Strobe[sequenceNumber] = false                                          // Set strobe to be off for all sequences.
 		this.StrobeSpeed[sequenceNumber] = common.DEFAULT_STROBE_SPEED               // Set the strobe speed to be the fastest for this sequence.
+		this.StepOrder[sequenceNumber] = common.STEP_ORDER_FORWARD                   // Play the steps forward.
+		this.Swing[sequenceNumber] = 0                                               // No swing.
 		this.RGBShift[sequenceNumber] = common.DEFAULT_RGB_SHIFT                     // Default RGB shift size.
 		this.ScannerShift[sequenceNumber] = common.DEFAULT_SCANNER_SHIFT             // Default scanner shift size.
 		this.SequenceType[sequenceNumber] = newSequence.Type                         // Set the sequence type.
@@ -300,10 +362,12 @@ func main() {
 
 		if newSequence.Label == "switch" {
 			this.SwitchSequenceNumber = sequenceNumber
+			common.GlobalSwitchSequenceNumber = sequenceNumber
 		}
 
 		if newSequence.Label == "chaser" {
 			this.ChaserSequenceNumber = sequenceNumber
+			common.GlobalChaserSequenceNumber = sequenceNumber
 		}
 
 		if newSequence.Type == "scanner" {
@@ -357,12 +421,15 @@ func main() {
 		}
 	}
 
+	// Work out which launchpad rows the sequences are shown on.
+	common.SetSequenceRows(sequences)
+
 	// Create all the channels I need.
 	commandChannels := []chan common.Command{}
 	replyChannels := []chan common.Sequence{}
 	updateChannels := []chan common.Sequence{}
 
-	// Make four default channels & one for the scanner chaser for commands.
+	// Make a command channel for each sequence.
 	for range sequences {
 		commandChannel := make(chan common.Command)
 		commandChannels = append(commandChannels, commandChannel)
@@ -378,29 +445,16 @@ func main() {
 	NumberOfMusicTriggers := NumberOfSequences + NumberOfSwitches
 
 	// Setting trigger names.
+	// The first triggers belong to the sequences and use the sequence name from the sequences config file.
+	// The switches follow on, named switch1 to switch8.
 	for triggerNumber := 0; triggerNumber < NumberOfMusicTriggers; triggerNumber++ {
 		newChannel := make(chan common.Command)
 		var name string
 		var newTrigger common.Trigger
-		// First three triggers occupied by sequence 0=FOH, 1=Uplighter,2=Scanners, 3-10 switched 11=shutter chase
-		if triggerNumber == 0 {
-			name = fmt.Sprintf("sequence%d", triggerNumber) // FOH
-		}
-		if triggerNumber == 1 {
-			name = fmt.Sprintf("sequence%d", triggerNumber) // Uplighters
-		}
-		if triggerNumber == 2 {
-			name = fmt.Sprintf("sequence%d", triggerNumber) // Scanners
-		}
-		if triggerNumber == 3 {
-			name = fmt.Sprintf("sequence%d", triggerNumber) // Switches
-		}
-		if triggerNumber == 4 {
-			name = fmt.Sprintf("sequence%d", triggerNumber) // Shutter Chaser
-		}
-		// 5-12, eight switches
-		if triggerNumber > 4 {
-			name = fmt.Sprintf("switch%d", triggerNumber-4)
+		if triggerNumber < NumberOfSequences {
+			name = sequencesConfig.Sequences[triggerNumber].Name
+		} else {
+			name = fmt.Sprintf("switch%d", triggerNumber-NumberOfSequences+1)
 		}
 
 		newTrigger = common.Trigger{
@@ -413,6 +467,18 @@ func main() {
 		this.SoundTriggers = append(this.SoundTriggers, &newTrigger)
 	}
 
+	// Each switch has another trigger to count the beats when it moves on to its next state by itself.
+	// They go on the end so the sequence triggers still line up with the sequence numbers.
+	// The beats are buffered as they're only collected when the switch sequence goes round its loop.
+	for switchNumber := 1; switchNumber <= NumberOfSwitches; switchNumber++ {
+		this.SoundTriggers = append(this.SoundTriggers, &common.Trigger{
+			Name:    sound.AdvanceTriggerName(switchNumber),
+			State:   false,
+			Gain:    this.SoundGain,
+			Channel: make(chan common.Command, 10),
+		})
+	}
+
 	if debug {
 		for triggerNumber, trigger := range this.SoundTriggers {
 			fmt.Printf("%d: trigger %s installed, enabled %t\n", triggerNumber, trigger.Name, trigger.State)
@@ -426,6 +492,26 @@ func main() {
 	this.SequenceChannels.UpdateChannels = updateChannels
 	this.SequenceChannels.SoundTriggers = this.SoundTriggers
 
+	// Each sequence has a trigger from the master clock, used when it's following the clock.
+	for sequenceNumber := 0; sequenceNumber < NumberOfSequences; sequenceNumber++ {
+		this.SequenceChannels.ClockTriggers = append(this.SequenceChannels.ClockTriggers, make(chan common.Command, 1))
+	}
+	clock.Start()
+
+	// Create the programmer, used to grab fixtures and set their channels directly.
+	this.Programmer = fixture.NewProgrammer()
+
+	// The bottom row sets the first group's submaster until a fixture is chosen.
+	this.Groups = groupConfig
+	this.SubmasterFixture = buttons.SUBMASTER_GROUP
+
+	// Create the cue player, it posts each cue to the buttons thread to make the change and starts on the project's first cue list.
+	this.CueRecalls = make(chan buttons.CueRecall, 10)
+	this.CuePlayer = cues.NewPlayer(func(number int, cue cues.Cue) {
+		this.CueRecalls <- buttons.CueRecall{Number: number, Cue: cue}
+	})
+	this.CuePlayer.SetList(cues.FirstCueList(fixturesConfig.CueLists))
+
 	// Create a timer for timing buttons, long and short presses.
 	this.ButtonTimer = &time.Time{}
 
@@ -433,7 +519,7 @@ func main() {
 	this.SoundConfig = sound.NewSoundTrigger(this.SequenceChannels, guiButtons, eventsForLaunchpad)
 
 	// Generate the toolbar at the top.
-	toolbar := gui.MakeToolbar(myWindow, this.SoundConfig, guiButtons, eventsForLaunchpad, commandChannels, dmxInterfaceConfig, this.LaunchpadName, fixturesConfig, startConfig)
+	toolbar := gui.MakeToolbar(myWindow, this.SoundConfig, guiButtons, eventsForLaunchpad, commandChannels, dmxInterfaceConfig, this.LaunchpadName, fixturesConfig, startConfig, groupConfig, this.Programmer, sequences, &this)
 
 	// Create objects for bottom status bar.
 	panel.SpeedLabel = widget.NewLabel(fmt.Sprintf("Speed %02d", common.DEFAULT_SPEED))
@@ -463,6 +549,49 @@ func main() {
 	masterLabel := widget.NewLabel(fmt.Sprintf("Master %02d", this.MasterBrightness))
 	panel.MasterLabel = masterLabel
 
+	// Shows which fixtures are on the launchpad when a sequence has more than eight.
+	pageLabel := widget.NewLabel("")
+	panel.PageLabel = pageLabel
+
+	// Shows what the bottom row sets in programmer mode.
+	programmerLabel := widget.NewLabel("")
+	panel.ProgrammerLabel = programmerLabel
+
+	// Shows the submaster last moved.
+	submasterLabel := widget.NewLabel("")
+	panel.SubmasterLabel = submasterLabel
+
+	// Shows the cue last played.
+	cueLabel := widget.NewLabel("")
+	panel.CueLabel = cueLabel
+
+	// Shows when everything is frozen.
+	freezeLabel := widget.NewLabel("")
+	panel.FreezeLabel = freezeLabel
+
+	// Shows the page of sequences on the launchpad when there are more than it has rows for.
+	sequencePageLabel := widget.NewLabel(buttons.SequencePageLabel())
+	panel.SequencePageLabel = sequencePageLabel
+
+	// Shows the page of presets on the launchpad.
+	presetPageLabel := widget.NewLabel(presets.PageLabel(this.PresetPage))
+	panel.PresetPageLabel = presetPageLabel
+
+	// Choose the global crossfade time used to recall presets.
+	fadeOptions := []string{}
+	for _, fade := range presets.FadeTimes {
+		fadeOptions = append(fadeOptions, presets.FadeLabel(fade))
+	}
+	presetFadeSelect := widget.NewSelect(fadeOptions, func(value string) {
+		for _, fade := range presets.FadeTimes {
+			if presets.FadeLabel(fade) == value {
+				this.PresetFade = fade
+			}
+		}
+	})
+	presetFadeSelect.Selected = presets.FadeLabel(this.PresetFade)
+	panel.PresetFadeSelect = presetFadeSelect
+
 	// Create a thread to handle GUI button events.
 	panel.ListenAndSendToGUI(guiButtons, GuiFlashButtons)
 
@@ -490,6 +619,14 @@ func main() {
 		redLabel,
 		greenLabel,
 		blueLabel,
+		sequencePageLabel,
+		pageLabel,
+		programmerLabel,
+		submasterLabel,
+		cueLabel,
+		freezeLabel,
+		presetPageLabel,
+		presetFadeSelect,
 		layout.NewSpacer(),
 		layout.NewSpacer(),
 		layout.NewSpacer(),
@@ -513,11 +650,9 @@ func main() {
 	content := container.NewBorder(main, nil, nil, nil, bottonStatusBar)
 
 	// Start threads for each sequence.
-	go sequence.PlaySequence(*sequences[0], 0, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
-	go sequence.PlaySequence(*sequences[1], 1, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
-	go sequence.PlaySequence(*sequences[2], 2, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
-	go sequence.PlaySequence(*sequences[3], 3, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
-	go sequence.PlaySequence(*sequences[4], 4, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
+	for sequenceNumber := range sequences {
+		go sequence.PlaySequence(*sequences[sequenceNumber], sequenceNumber, this.RGBPatterns, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.SequenceChannels, this.SwitchChannels, this.SoundConfig, this.DmxInterfacePresent)
+	}
 
 	// Light the first sequence as the default selected.
 	this.SelectedSequence = 0
@@ -530,23 +665,21 @@ func main() {
 	buttons.AllFixturesOff(sequences, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, this.DmxInterfacePresent)
 	buttons.Clear(0, 0, &this, sequences, dmxController, fixturesConfig, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
 
-	// If present create a thread to listen to launchpad button events.
-	if this.LaunchPadConnected {
-		go func(guiButtons chan common.ALight,
-			this *buttons.CurrentState,
-			sequences []*common.Sequence,
-			eventsForLaunchpad chan common.ALight,
-			dmxController *ft232.DMXController,
-			fixturesConfig *fixture.Fixtures,
-			commandChannels []chan common.Command,
-			replyChannels []chan common.Sequence,
-			updateChannels []chan common.Sequence,
-			dmxInterfaceCardPresent bool) {
-
-			launchpad.ReadLaunchPadButtons(guiButtons, this, sequences, eventsForLaunchpad, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels, dmxInterfaceCardPresent)
-
-		}(guiButtons, &this, sequences, eventsForLaunchpad, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels, this.DmxInterfacePresent)
-	}
+	// Create a thread to listen to launchpad button events, if there's one, and recall cues.
+	go func(guiButtons chan common.ALight,
+		this *buttons.CurrentState,
+		sequences []*common.Sequence,
+		eventsForLaunchpad chan common.ALight,
+		dmxController *ft232.DMXController,
+		fixturesConfig *fixture.Fixtures,
+		commandChannels []chan common.Command,
+		replyChannels []chan common.Sequence,
+		updateChannels []chan common.Sequence,
+		dmxInterfaceCardPresent bool) {
+
+		launchpad.ReadLaunchPadButtons(guiButtons, this, sequences, eventsForLaunchpad, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels, dmxInterfaceCardPresent)
+
+	}(guiButtons, &this, sequences, eventsForLaunchpad, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels, this.DmxInterfacePresent)
 
 	// Show this sequence running status in the start/stop button.
 	common.ShowRunningStatus(this.Running[this.SelectedSequence], eventsForLaunchpad, guiButtons)
@@ -554,15 +687,19 @@ func main() {
 
 	myWindow.SetContent(content)
 
+	if presetsErr != nil {
+		gui.PopupPresetsLoadError(myWindow, presetsErr.Error())
+	}
+
 	// Main menu.
 	openProject := fyne.NewMenuItem("Open", func() {
-		gui.FileOpen(myWindow, startConfig, fixturesConfig, commandChannels)
+		gui.FileOpen(myWindow, &this, startConfig, fixturesConfig, groupConfig, commandChannels, eventsForLaunchpad, guiButtons)
 	})
 	saveProject := fyne.NewMenuItem("Save", func() {
-		gui.FileSave(myWindow, startConfig, fixturesConfig, commandChannels)
+		gui.FileSave(myWindow, &this, startConfig, fixturesConfig)
 	})
 	editSettings := fyne.NewMenuItem("Edit", func() {
-		modal := gui.RunSettingsPopUp(myWindow, this.SoundConfig, guiButtons, eventsForLaunchpad, dmxInterfaceConfig, this.LaunchpadName)
+		modal := gui.RunSettingsPopUp(myWindow, &this, this.SoundConfig, guiButtons, eventsForLaunchpad, dmxInterfaceConfig, this.LaunchpadName)
 		modal.Resize(fyne.NewSize(250, 250))
 		modal.Show()
 	})
@@ -579,8 +716,10 @@ func main() {
 	myWindow.ShowAndRun()
 
 	fmt.Println("Saving Presets")
-	presets.SavePresets(this.PresetsStore)
-
+	err = presets.SavePresets(this.PresetsStore)
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to save presets: %s\n", err.Error())
+	}
 }
 
 func makeStaticButtonsStorage() []common.StaticColorButton {
@@ -0,0 +1,128 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These blackout functions fade everything to black in the output stage and
+// back up again, or cut it dead. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// Times the blackout can take to fade to black and back up, zero is instant.
+var BlackoutFadeTimes = []time.Duration{0, 500 * time.Millisecond, 1 * time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 10 * time.Second}
+
+// ToggleBlackout is the blackout button. With no fade time it's a dead blackout, otherwise it fades to black
+// and pressing it again while it's still fading cuts straight to a dead blackout. Once black, pressing it
+// fades back up over the release time.
+func ToggleBlackout(this *CurrentState, commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	switch {
+	case this.Blackout:
+		ReleaseDeadBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
+
+	case this.FadedBlackout && dmx.BlackoutFading(time.Now()):
+		DeadBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
+
+	case this.FadedBlackout:
+		releaseFadedBlackout(this, eventsForLaunchpad, guiButtons)
+
+	case this.BlackoutFadeTime <= 0:
+		DeadBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
+
+	default:
+		fadeBlackout(this, eventsForLaunchpad, guiButtons)
+	}
+}
+
+// ToggleDeadBlackout cuts everything dead or brings it straight back, whatever the fade times are set to.
+func ToggleDeadBlackout(this *CurrentState, commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+	if this.Blackout {
+		ReleaseDeadBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
+	} else {
+		DeadBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
+	}
+}
+
+// DeadBlackout cuts every fixture to black straight away, in the sequences and in the output stage.
+func DeadBlackout(this *CurrentState, commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Dead Blackout\n")
+	}
+
+	this.Blackout = true
+	this.FadedBlackout = false
+	dmx.StartBlackout(0, time.Now())
+
+	cmd := common.Command{
+		Action: common.Blackout,
+	}
+	common.SendCommandToAllSequence(cmd, commandChannels)
+
+	common.LightLamp(common.BLACKOUT_BUTTON, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.FlashLight(common.BLACKOUT_BUTTON, common.Magenta, common.White, eventsForLaunchpad, guiButtons)
+}
+
+// ReleaseDeadBlackout brings every fixture straight back.
+func ReleaseDeadBlackout(this *CurrentState, commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Release Dead Blackout\n")
+	}
+
+	this.Blackout = false
+	this.FadedBlackout = false
+	dmx.ReleaseBlackout(0, time.Now())
+
+	cmd := common.Command{
+		Action: common.Normal,
+	}
+	common.SendCommandToAllSequence(cmd, commandChannels)
+
+	common.LightLamp(common.BLACKOUT_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+}
+
+// fadeBlackout fades every fixture to black in the output stage, so the sequences, switches and
+// mini sequencers all carry on underneath and come back where they've got to.
+func fadeBlackout(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Fade Blackout over %s\n", this.BlackoutFadeTime)
+	}
+
+	this.FadedBlackout = true
+	dmx.StartBlackout(this.BlackoutFadeTime, time.Now())
+
+	common.LightLamp(common.BLACKOUT_BUTTON, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.FlashLight(common.BLACKOUT_BUTTON, common.Magenta, common.White, eventsForLaunchpad, guiButtons)
+}
+
+// releaseFadedBlackout fades every fixture back up over the release time.
+func releaseFadedBlackout(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Release Fade Blackout over %s\n", this.BlackoutReleaseTime)
+	}
+
+	this.FadedBlackout = false
+	dmx.ReleaseBlackout(this.BlackoutReleaseTime, time.Now())
+
+	common.LightLamp(common.BLACKOUT_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+}
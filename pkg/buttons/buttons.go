@@ -26,6 +26,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/dhowlett99/dmxlights/pkg/config"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
 	"github.com/dhowlett99/dmxlights/pkg/pad"
 	"github.com/dhowlett99/dmxlights/pkg/presets"
@@ -46,6 +48,10 @@ const (
 	CHASER_DISPLAY_STATIC            //  Shutter chaser in edit all fixtures mode.
 	CHASER_FUNCTION                  // Show the scammer shutter chaser functions.
 	STATUS                           // Show the fixture status states.
+	PROGRAMMER                       // Select fixtures and set their channels directly.
+	CUES                             // Step through the cue list with GO and BACK.
+	PRESETS                          // Page through the presets.
+	SUBMASTERS                       // Dim fixtures and groups with their submasters.
 )
 
 type CurrentState struct {
@@ -71,13 +77,25 @@ type CurrentState struct {
 	ScannerColor                int                                   // current scanner color.
 	RGBFade                     map[int]int                           // Indexed by sequence.
 	ScannerFade                 map[int]int                           // Indexed by sequence.
+	FixturePage                 map[int]int                           // Page of eight fixtures shown on the launchpad, indexed by sequence.
 	ScannerCoordinates          map[int]int                           // Number of coordinates for scanner patterns is selected from 4 choices. ScannerCoordinates  0=12, 1=16,2=24,3=32,4=64, Indexed by sequence.
 	Running                     map[int]bool                          // Which sequence is running. Indexed by sequence. True if running.
 	Strobe                      map[int]bool                          // We are in strobe mode. True if strobing
 	StrobeSpeed                 map[int]int                           // Strobe speed. value is speed 0-255, indexed by sequence number.
+	StepOrder                   map[int]int                           // Order the steps are played in, common.STEP_ORDER_FORWARD etc. Indexed by sequence.
+	Swing                       map[int]int                           // Swing in percent. Indexed by sequence.
+	StepOrderTimer              *time.Time                            // When the bounce function key was pressed, a long press sets the swing.
 	SavePreset                  bool                                  // Save a preset flag.
 	Config                      bool                                  // Flag to indicate we are in fixture config mode.
-	Blackout                    bool                                  // Blackout all fixtures.
+	Blackout                    bool                                  // Blackout all fixtures, a dead blackout.
+	FadedBlackout               bool                                  // The output stage is fading to black or is black.
+	BlackoutFadeTime            time.Duration                         // Time the blackout button takes to fade to black, zero is a dead blackout.
+	BlackoutReleaseTime         time.Duration                         // Time the blackout button takes to fade back up.
+	Frozen                      bool                                  // Everything is frozen where it is.
+	FaderPage                   bool                                  // The grid is showing the fader page.
+	FaderPageTimer              *time.Time                            // When the master brightness up button was pressed, a long press opens the fader page.
+	SequencePageTimer           *time.Time                            // When a sequence select button was pressed, a long press shows the next page of sequences.
+	FaderPageMaster             int                                   // The master brightness before that press.
 	Flood                       bool                                  // Flood all fixtures.
 	SelectedMode                []int                                 // What mode each sequence is in : normal mode, function mode, status selection mode.
 	LastMode                    []int                                 // Last mode sequence was in : normal mode, function mode, status selection mode.
@@ -104,7 +122,9 @@ type CurrentState struct {
 	OffsetTilt                  int                                   // Offset for Tilt.
 	Pad                         *pad.Pad                              // Pointer to the Novation Launchpad object.
 	PresetsStore                map[string]presets.Preset             // Storage for the Presets.
-	LastPreset                  *string                               // Last preset used.
+	LastPreset                  *string                               // ID of the last preset used.
+	PresetPage                  int                                   // Page of presets shown, presets.FULL_PAGE when every pad is a preset.
+	LastPresetPage              int                                   // Page of presets to go back to when we leave the full page.
 	SoundTriggers               []*common.Trigger                     // Pointer to the Sound Triggers.
 	SoundConfig                 *sound.SoundConfig                    // Pointer to the sound config struct.
 	SequenceChannels            common.Channels                       // Channles used to communicate with the sequence.
@@ -125,6 +145,14 @@ type CurrentState struct {
 	SwitchSequenceNumber        int                                   // Switch sequence number, setup at start.
 	ChaserSequenceNumber        int                                   // Chaser sequence number, setup at start.
 	ScannerSequenceNumber       int                                   // Scanner sequence number, setup at start.
+	Programmer                  *fixture.Programmer                   // Fixtures grabbed and set directly, overrides the sequences.
+	ProgrammerChannel           int                                   // Which channel the bottom row buttons set in programmer mode.
+	Groups                      *fixture.Groups                       // The fixture groups from groups.yaml.
+	SubmasterGroup              int                                   // Which group the bottom row buttons set in submaster mode.
+	SubmasterFixture            int                                   // Which fixture the bottom row buttons set in submaster mode, SUBMASTER_GROUP for the group.
+	CuePlayer                   *cues.Player                          // Steps through the project's cue list.
+	CueRecalls                  chan CueRecall                        // Cues the cue player wants recalled, handled by the buttons thread.
+	PresetFade                  float64                               // Global crossfade time in seconds for recalling presets, zero snaps.
 }
 
 func ProcessButtons(X int, Y int,
@@ -145,6 +173,24 @@ func ProcessButtons(X int, Y int,
 	// Set the sequence type.
 	this.SelectedType = sequences[this.SelectedSequence].Type
 
+	// F A D E R S - While the fader page is showing the grid is a set of faders.
+	if this.FaderPage && X >= 0 && X < 8 && Y >= 0 && Y < 8 {
+		faderPressed(X, Y, this, eventsForLaunchpad, guiButtons)
+		return
+	}
+	if this.FaderPage && X >= 100 && X < 108 && Y >= 0 && Y < 8 {
+		return
+	}
+
+	// F A D E R S - The top row goes back to the normal grid. The launchpad waits for the
+	// release so it isn't taken as the release of a top row button on the normal grid.
+	if this.FaderPage && Y == -1 && (X < 8 || (X >= 100 && X < 108)) {
+		if this.GUI || X >= 100 {
+			closeFaderPage(this, eventsForLaunchpad, guiButtons)
+		}
+		return
+	}
+
 	// The Novation Launchpad is not designed for the number of MIDI
 	// Events we send when all the sequences are chasing at top
 	// Speed, so we look out for the crys for help when the Launchpad
@@ -175,27 +221,43 @@ func ProcessButtons(X int, Y int,
 		common.SendCommandToAllSequence(cmd, commandChannels)
 
 		// Show the presets again.
-		presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+		presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 		this.Crash1 = false
 		this.Crash2 = false
 		return
 	}
 
+	// F U L L   P R E S E T   P A G E - The sequence select buttons take us back to the sequences.
+	if this.PresetPage == presets.FULL_PAGE && X == 8 && Y >= 0 && Y < 3 {
+		leaveFullPresetPage(sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
+		return
+	}
+
+	// The sequence shown on the row pressed, -1 if the row isn't showing one.
+	rowSequence := common.RowSequence(Y)
+
 	// F L A S H   O N   B U T T O N S - Briefly light (flash) the fixtures based on color pattern.
-	if X >= 0 &&
+	if this.PresetPage != presets.FULL_PAGE &&
+		X >= 0 &&
 		X < 8 &&
 		Y >= 0 &&
-		Y < 4 &&
-		!this.Functions[Y][common.Function1_Pattern].State &&
-		!this.Functions[Y][common.Function6_Static_Gobo].State &&
-		!this.Functions[Y][common.Function5_Color].State &&
+		rowSequence >= 0 && rowSequence < len(sequences) &&
+		!this.Functions[rowSequence][common.Function1_Pattern].State &&
+		!this.Functions[rowSequence][common.Function6_Static_Gobo].State &&
+		!this.Functions[rowSequence][common.Function5_Color].State &&
 		!this.Static[this.EditWhichStaticSequence] &&
 		!this.ShowRGBColorPicker &&
 		!this.ShowStaticColorPicker &&
-		sequences[Y].Type != "switch" && // As long as we're not a switch sequence.
-		(this.SelectedMode[Y] == NORMAL || this.SelectedMode[Y] == CHASER_DISPLAY) { // As long as we're in normal or shutter chaser mode for this sequence.
+		sequences[rowSequence].Type != "switch" && // As long as we're not a switch sequence.
+		(this.SelectedMode[rowSequence] == NORMAL || this.SelectedMode[rowSequence] == CHASER_DISPLAY) { // As long as we're in normal or shutter chaser mode for this sequence.
+
+		// Allow for the page of fixtures being shown, the last page may not be full.
+		fixtureNumber := X + this.FixturePage[rowSequence]*common.FIXTURES_PER_PAGE
+		if fixtureNumber >= sequences[rowSequence].NumberFixtures {
+			return
+		}
 
-		this.SelectedType = sequences[Y].Type
+		this.SelectedType = sequences[rowSequence].Type
 
 		if debug {
 			fmt.Printf("Flash ON Fixture Pressed X:%d Y:%d\n", X, Y)
@@ -219,11 +281,11 @@ func ProcessButtons(X int, Y int,
 
 		if this.SelectedType == "rgb" {
 			common.LightLamp(common.Button{X: X, Y: Y}, color, this.MasterBrightness, eventsForLaunchpad, guiButtons)
-			fixture.MapFixtures(false, false, Y, X, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, this.MasterBrightness, this.MasterBrightness, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
+			fixture.MapFixtures(dmx.SequenceSource(rowSequence), false, false, rowSequence, fixtureNumber, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, this.MasterBrightness, this.MasterBrightness, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
 		}
 		if this.SelectedType == "scanner" {
 			common.LightLamp(common.Button{X: X, Y: Y}, common.White, this.MasterBrightness, eventsForLaunchpad, guiButtons)
-			fixture.MapFixtures(false, false, Y, X, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, this.MasterBrightness, this.MasterBrightness, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
+			fixture.MapFixtures(dmx.SequenceSource(rowSequence), false, false, rowSequence, fixtureNumber, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, this.MasterBrightness, this.MasterBrightness, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
 		}
 
 		if this.GUI {
@@ -231,24 +293,25 @@ func ProcessButtons(X int, Y int,
 			brightness := 0
 			master := 0
 			common.LightLamp(common.Button{X: X, Y: Y}, common.Black, common.MIN_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			fixture.MapFixtures(false, false, Y, X, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, brightness, master, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
+			fixture.MapFixtures(dmx.SequenceSource(rowSequence), false, false, rowSequence, fixtureNumber, color, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, brightness, master, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
 		}
 
 		return
 	}
 
 	// F L A S H  O F F   B U T T O N S - Briefly light (flash) the fixtures based on current pattern.
-	if X >= 0 &&
+	if this.PresetPage != presets.FULL_PAGE &&
+		X >= 0 &&
 		X != 108 && X != 117 &&
 		X >= 100 && X < 117 &&
-		Y >= 0 && Y < 4 &&
-		!this.Functions[Y][common.Function1_Pattern].State &&
-		!this.Functions[Y][common.Function6_Static_Gobo].State &&
-		!this.Functions[Y][common.Function5_Color].State &&
+		rowSequence >= 0 && rowSequence < len(sequences) &&
+		!this.Functions[rowSequence][common.Function1_Pattern].State &&
+		!this.Functions[rowSequence][common.Function6_Static_Gobo].State &&
+		!this.Functions[rowSequence][common.Function5_Color].State &&
 		!this.ShowRGBColorPicker &&
 		!this.ShowStaticColorPicker &&
-		sequences[Y].Type != "switch" && // As long as we're not a switch sequence.
-		this.SelectedMode[Y] == NORMAL { // As long as we're in normal mode for this sequence.
+		sequences[rowSequence].Type != "switch" && // As long as we're not a switch sequence.
+		this.SelectedMode[rowSequence] == NORMAL { // As long as we're in normal mode for this sequence.
 
 		if debug {
 			fmt.Printf("Flash OFF Fixture Pressed X:%d Y:%d\n", X, Y)
@@ -256,6 +319,12 @@ func ProcessButtons(X int, Y int,
 
 		X = X - 100
 
+		// Allow for the page of fixtures being shown, the last page may not be full.
+		fixtureNumber := X + this.FixturePage[rowSequence]*common.FIXTURES_PER_PAGE
+		if fixtureNumber >= sequences[rowSequence].NumberFixtures {
+			return
+		}
+
 		pan := common.SCANNER_MID_POINT
 		tilt := common.SCANNER_MID_POINT
 		shutter := 0
@@ -267,13 +336,13 @@ func ProcessButtons(X int, Y int,
 		master := 0
 
 		common.LightLamp(common.Button{X: X, Y: Y}, common.Black, common.MIN_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-		fixture.MapFixtures(false, false, Y, X, common.Black, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, brightness, master, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
+		fixture.MapFixtures(dmx.SequenceSource(rowSequence), false, false, rowSequence, fixtureNumber, common.Black, pan, tilt, shutter, rotate, program, gobo, 0, fixturesConfig, this.Blackout, brightness, master, music, this.Strobe[this.SelectedSequence], this.StrobeSpeed[this.SelectedSequence], dmxController, this.DmxInterfacePresent)
 		return
 	}
 
 	// P R E S E T S - recall (short press) or delete (long press) the preset.
 	if X >= 100 && X < 108 &&
-		(Y > 3 && Y < 7) {
+		presets.IsPresetPad(this.PresetPage, X-100, Y) {
 
 		if debug {
 			fmt.Printf("Preset Pressed X:%d Y:%d\n", X, Y)
@@ -290,7 +359,8 @@ func ProcessButtons(X int, Y int,
 
 		// If this is a valid preset we are either recalling (short press) it or deleting it (long press)
 		// If its not been set i.e. not valid we just ignore and return.
-		if !this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y)].State {
+		id, found := presets.FindPreset(this.PresetsStore, this.PresetPage, X, Y)
+		if !found {
 			return
 		}
 
@@ -305,16 +375,33 @@ func ProcessButtons(X int, Y int,
 			}
 
 			// Delete the config file
-			config.DeleteConfig(fmt.Sprintf("config%d.%d.json", X, Y))
+			err := config.DeleteConfig(id)
+			if err != nil {
+				showError(this, err)
+			}
+			err = config.DeleteProgrammerConfig(id)
+			if err != nil {
+				showError(this, err)
+			}
+			err = config.DeleteSubmasterConfig(id)
+			if err != nil {
+				showError(this, err)
+			}
 
 			// Delete from preset store
-			this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y)] = presets.Preset{State: false, Selected: false, Label: "", ButtonColor: ""}
+			delete(this.PresetsStore, id)
+			if this.LastPreset != nil && *this.LastPreset == id {
+				this.LastPreset = nil
+			}
 
 			// Update the copy of presets on disk.
-			presets.SavePresets(this.PresetsStore)
+			err = presets.SavePresets(this.PresetsStore)
+			if err != nil {
+				showError(this, err)
+			}
 
 			// Show presets again.
-			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 
 		} else {
 			if debug {
@@ -322,7 +409,7 @@ func ProcessButtons(X int, Y int,
 			}
 
 			// Short press means load the config.
-			loadConfig(sequences, this, X, Y, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
+			loadConfig(sequences, this, id, presets.FadeTime(this.PresetsStore[id], this.PresetFade), fixturesConfig, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
 			common.StartStaticSequences(sequences, commandChannels)
 		}
 		return
@@ -408,6 +495,66 @@ func ProcessButtons(X int, Y int,
 		return
 	}
 
+	// F R E E Z E - A long press on the save button freezes or unfreezes everything.
+	if X == 108 && Y == 4 && !this.GUI {
+
+		if debug {
+			fmt.Printf("Save Released X:%d Y:%d\n", X, Y)
+		}
+
+		// Stop the timer for the save button.
+		if this.ButtonTimer != nil && time.Since(*this.ButtonTimer) > FREEZE_LONG_PRESS {
+			// The press turned save mode on, so turn it back off.
+			this.SavePreset = false
+			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+			ToggleFreeze(this, eventsForLaunchpad, guiButtons)
+		}
+		return
+	}
+
+	// F A D E R S - A long press on the master brightness up button opens the fader page.
+	if X == 107 && Y == -1 && !this.GUI {
+
+		if debug {
+			fmt.Printf("Master Up Released X:%d Y:%d\n", X, Y)
+		}
+
+		// Stop the timer for the master brightness up button.
+		if this.FaderPageTimer != nil && time.Since(*this.FaderPageTimer) > FADER_PAGE_LONG_PRESS {
+			// The press turned the master up, so put it back.
+			setMasterBrightness(this, this.FaderPageMaster, guiButtons)
+			openFaderPage(this, eventsForLaunchpad, guiButtons)
+		}
+		this.FaderPageTimer = nil
+		return
+	}
+
+	// S E Q U E N C E   P A G E - A long press on a sequence select button shows the next page of sequences.
+	if X == 108 && Y >= 0 && Y < common.SEQUENCE_PAGE_ROWS && !this.GUI {
+
+		if debug {
+			fmt.Printf("Select Released X:%d Y:%d\n", X, Y)
+		}
+
+		if this.SequencePageTimer != nil && time.Since(*this.SequencePageTimer) > SEQUENCE_PAGE_LONG_PRESS {
+			NextSequencePage(sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
+		}
+		this.SequencePageTimer = nil
+		return
+	}
+
+	// S T E P  O R D E R - The bounce function key acts when it's released, a long press steps the swing.
+	if X == 100+common.Function4_Bounce && Y == common.SequenceRow(this.SelectedSequence) && !this.GUI && this.StepOrderTimer != nil {
+
+		if debug {
+			fmt.Printf("Bounce Function Released X:%d Y:%d\n", X, Y)
+		}
+
+		stepOrderReleased(this, this.TargetSequence, commandChannels)
+		ShowFunctionButtons(this, eventsForLaunchpad, guiButtons)
+		return
+	}
+
 	// Swollow the button off events if not used for flash above.
 	if X >= 100 {
 		if debug {
@@ -425,7 +572,7 @@ func ProcessButtons(X int, Y int,
 
 		// Turn off the flashing save button
 		this.SavePreset = false
-		common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+		saveButtonOff(this, eventsForLaunchpad, guiButtons)
 
 		// Shutdown any function bars.
 		clearAllModes(sequences, this)
@@ -438,8 +585,9 @@ func ProcessButtons(X int, Y int,
 			// Find the currently selected preset and save it's location.
 			for location, preset := range this.PresetsStore {
 				if preset.State && preset.Selected {
-					this.PresetsStore[location] = presets.Preset{State: preset.State, Selected: false, Label: preset.Label, ButtonColor: preset.ButtonColor}
-					presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+					preset.Selected = false
+					this.PresetsStore[location] = preset
+					presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 					this.LastPreset = &location
 					break
 				}
@@ -454,8 +602,9 @@ func ProcessButtons(X int, Y int,
 			// Restore the last preset
 			if this.LastPreset != nil {
 				lastPreset := this.PresetsStore[*this.LastPreset]
-				this.PresetsStore[*this.LastPreset] = presets.Preset{State: lastPreset.State, Selected: true, Label: lastPreset.Label, ButtonColor: lastPreset.ButtonColor}
-				presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+				lastPreset.Selected = true
+				this.PresetsStore[*this.LastPreset] = lastPreset
+				presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 			}
 			floodOff(this, commandChannels, eventsForLaunchpad, guiButtons)
 			return
@@ -515,20 +664,11 @@ func ProcessButtons(X int, Y int,
 
 		buttonTouched(common.Button{X: X, Y: Y}, common.White, common.Cyan, eventsForLaunchpad, guiButtons)
 
-		this.MasterBrightness = this.MasterBrightness - 10
-		if this.MasterBrightness < 0 {
-			this.MasterBrightness = 0
-		}
-		cmd := common.Command{
-			Action: common.Master,
-			Args: []common.Arg{
-				{Name: "Master", Value: this.MasterBrightness},
-			},
+		brightness := this.MasterBrightness - 10
+		if brightness < 0 {
+			brightness = 0
 		}
-		common.SendCommandToAllSequence(cmd, commandChannels)
-
-		// Update the status bar
-		common.UpdateStatusBar(fmt.Sprintf("Master %02d", this.MasterBrightness), "master", false, guiButtons)
+		setMasterBrightness(this, brightness, guiButtons)
 		return
 	}
 
@@ -541,20 +681,18 @@ func ProcessButtons(X int, Y int,
 
 		buttonTouched(common.Button{X: X, Y: Y}, common.White, common.Cyan, eventsForLaunchpad, guiButtons)
 
-		this.MasterBrightness = this.MasterBrightness + 10
-		if this.MasterBrightness > common.MAX_DMX_BRIGHTNESS {
-			this.MasterBrightness = common.MAX_DMX_BRIGHTNESS
-		}
-		cmd := common.Command{
-			Action: common.Master,
-			Args: []common.Arg{
-				{Name: "Master", Value: this.MasterBrightness},
-			},
+		// Start a timer for this button, a long press opens the fader page.
+		if !this.GUI {
+			here := time.Now()
+			this.FaderPageTimer = &here
+			this.FaderPageMaster = this.MasterBrightness
 		}
-		common.SendCommandToAllSequence(cmd, commandChannels)
 
-		// Update the status bar
-		common.UpdateStatusBar(fmt.Sprintf("Master %02d", this.MasterBrightness), "master", false, guiButtons)
+		brightness := this.MasterBrightness + 10
+		if brightness > common.MAX_DMX_BRIGHTNESS {
+			brightness = common.MAX_DMX_BRIGHTNESS
+		}
+		setMasterBrightness(this, brightness, guiButtons)
 		return
 	}
 
@@ -565,24 +703,30 @@ func ProcessButtons(X int, Y int,
 			fmt.Printf("Save Mode\n")
 		}
 
+		// Start a timer for this button, a long press freezes everything.
+		if !this.GUI {
+			here := time.Now()
+			this.ButtonTimer = &here
+		}
+
 		if this.SavePreset { // Turn the save mode off.
 			this.SavePreset = false
-			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
-			common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+			saveButtonOff(this, eventsForLaunchpad, guiButtons)
 			return
 		}
 		this.SavePreset = true
 		if this.Flood { // Turn off flood.
 			floodOff(this, commandChannels, eventsForLaunchpad, guiButtons)
 		}
-		presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+		presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 		common.FlashLight(common.SAVE_BUTTON, common.Magenta, common.White, eventsForLaunchpad, guiButtons)
 
 		return
 	}
 
 	// P R E S E T S
-	if X < 8 && (Y > 3 && Y < 7) {
+	if presets.IsPresetPad(this.PresetPage, X, Y) {
 
 		if debug {
 			fmt.Printf("Ask For Config\n")
@@ -593,32 +737,58 @@ func ProcessButtons(X int, Y int,
 			removeColorPicker(this, sequences, eventsForLaunchpad, guiButtons, commandChannels)
 		}
 
-		location := fmt.Sprint(X) + "," + fmt.Sprint(Y)
+		id, found := presets.FindPreset(this.PresetsStore, this.PresetPage, X, Y)
 
 		if this.SavePreset {
 			// S A V E - Ask all sequences for their current config and save in a file.
 
-			current := this.PresetsStore[location]
-			this.PresetsStore[location] = presets.Preset{State: true, Selected: true, Label: current.Label, ButtonColor: current.ButtonColor}
-			this.LastPreset = &location
+			// A new preset gets a new ID, saving over a preset keeps its ID.
+			if !found {
+				id = presets.NewPresetID(this.PresetsStore)
+			}
+
+			// The preset is only saved if every sequence sent its config and all its files were written,
+			// otherwise nothing changes and a preset saved here before keeps its old config.
+			// Any fixtures held by the programmer and the submaster levels are saved with the preset.
+			err := config.AskToSaveConfig(commandChannels, id, this.Programmer.Values(), fixture.Submasters())
+			if err != nil {
+				showError(this, err)
+				saveButtonOff(this, eventsForLaunchpad, guiButtons)
+				presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+				if this.GUI {
+					this.SavePreset = false
+				}
+				return
+			}
 
-			config.AskToSaveConfig(commandChannels, replyChannels, X, Y)
+			current := this.PresetsStore[id]
+			current.Page = this.PresetPage
+			current.X = X
+			current.Y = Y
+			current.State = true
+			this.PresetsStore[id] = current
+			this.LastPreset = &id
 
 			// turn off the save button from flashing.
-			common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			saveButtonOff(this, eventsForLaunchpad, guiButtons)
 
-			presets.SavePresets(this.PresetsStore)
+			err = presets.SavePresets(this.PresetsStore)
+			if err != nil {
+				showError(this, err)
+			}
 
 			// clear any selected preset.
 			for location, preset := range this.PresetsStore {
 				if preset.State && preset.Selected {
-					this.PresetsStore[location] = presets.Preset{State: preset.State, Selected: false, Label: preset.Label, ButtonColor: preset.ButtonColor}
+					preset.Selected = false
+					this.PresetsStore[location] = preset
 				}
 			}
 
-			// Select this location and flash its button.
-			this.PresetsStore[location] = presets.Preset{State: true, Selected: true, Label: current.Label, ButtonColor: current.ButtonColor}
-			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+			// Select this preset and flash its button.
+			current.Selected = true
+			this.PresetsStore[id] = current
+			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 
 			if this.GUI {
 				this.SavePreset = false
@@ -626,13 +796,13 @@ func ProcessButtons(X int, Y int,
 
 		} else {
 			// L O A D - Load config, but only if it exists in the presets map.
-			if this.PresetsStore[location].State {
+			if found {
 
 				if this.GUI { // GUI path.
 					if this.SavePreset {
 						this.SavePreset = false
 					}
-					loadConfig(sequences, this, X, Y, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
+					loadConfig(sequences, this, id, presets.FadeTime(this.PresetsStore[id], this.PresetFade), fixturesConfig, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
 					common.StartStaticSequences(sequences, commandChannels)
 				} else { // Launchpad path.
 					// This is a valid preset we might be trying to load it or delete it.
@@ -646,6 +816,20 @@ func ProcessButtons(X int, Y int,
 		return
 	}
 
+	// P R O G R A M M E R - The bottom row acts as a fader for the fixtures in the programmer.
+	if X >= 0 && X < 8 && Y == 7 &&
+		this.SelectedMode[this.SelectedSequence] == PROGRAMMER {
+		programmerFaderPressed(X, this, fixturesConfig, guiButtons)
+		return
+	}
+
+	// S U B M A S T E R S - The bottom row acts as a fader for a fixture or group submaster.
+	if X >= 0 && X < 8 && Y == 7 &&
+		this.SelectedMode[this.SelectedSequence] == SUBMASTERS {
+		submasterFaderPressed(X, sequences, this, eventsForLaunchpad, guiButtons)
+		return
+	}
+
 	// Decrease Shift.
 	if X == 2 && Y == 7 && !this.ShowRGBColorPicker {
 
@@ -916,64 +1100,16 @@ func ProcessButtons(X int, Y int,
 		return
 	}
 
-	// S E L E C T    S E Q U E N C E.
-	// Select sequence 1.
-	if X == 8 && Y == 0 {
-
-		this.SelectedSequence = 0
-		this.SelectedType = sequences[this.SelectedSequence].Type
-
-		if debug {
-			fmt.Printf("Select Sequence %d Type %s\n", this.SelectedSequence, this.SelectedType)
-		}
-
-		HandleSelect(sequences, this, eventsForLaunchpad, commandChannels, guiButtons)
-
-		this.ShowRGBColorPicker = false
-		this.EditGoboSelectionMode = false
-		this.DisplayChaserShortCut = false
-		this.EditWhichStaticSequence = 0
-
-		return
-	}
-
-	// Select sequence 2.
-	if X == 8 && Y == 1 {
-
-		this.SelectedSequence = 1
-		this.SelectedType = sequences[this.SelectedSequence].Type
-
-		if debug {
-			fmt.Printf("Select Sequence %d Type %s\n", this.SelectedSequence, this.SelectedType)
-		}
-
-		HandleSelect(sequences, this, eventsForLaunchpad, commandChannels, guiButtons)
-
-		this.ShowRGBColorPicker = false
-		this.EditGoboSelectionMode = false
-		this.DisplayChaserShortCut = false
-		this.EditWhichStaticSequence = 1
-
-		return
-	}
-
-	// Select sequence 3.
-	if X == 8 && Y == 2 {
+	// S E L E C T    S E Q U E N C E - Select the sequence shown on the row.
+	// A long press shows the next page of sequences when there are more than the launchpad has rows for.
+	if X == 8 && Y >= 0 && Y < common.SEQUENCE_PAGE_ROWS && rowSequence >= 0 {
 
-		this.SelectedSequence = 2
-		this.SelectedType = sequences[this.SelectedSequence].Type
-
-		if debug {
-			fmt.Printf("Select Sequence %d Type %s\n", this.SelectedSequence, this.SelectedType)
+		if !this.GUI {
+			here := time.Now()
+			this.SequencePageTimer = &here
 		}
 
-		HandleSelect(sequences, this, eventsForLaunchpad, commandChannels, guiButtons)
-
-		this.ShowRGBColorPicker = false
-		this.EditGoboSelectionMode = false
-		if this.ScannerChaser[this.SelectedSequence] {
-			this.EditWhichStaticSequence = 4
-		}
+		selectSequence(rowSequence, sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
 
 		return
 	}
@@ -1104,7 +1240,7 @@ func ProcessButtons(X int, Y int,
 		// Turn off the flashing save button
 		this.SavePreset = false
 		this.SavePreset = false
-		common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+		saveButtonOff(this, eventsForLaunchpad, guiButtons)
 
 		// Shutdown any function bars.
 		clearAllModes(sequences, this)
@@ -1351,8 +1487,8 @@ func ProcessButtons(X int, Y int,
 	// S W I T C H   B U T T O N's Toggle State of switches for this sequence.
 	if X >= 0 && X < 8 &&
 		Y >= 0 &&
-		Y < 4 &&
-		sequences[Y].Type == "switch" {
+		rowSequence >= 0 && rowSequence < len(sequences) &&
+		sequences[rowSequence].Type == "switch" {
 
 		if debug {
 			fmt.Printf("Switch Key X:%d Y:%d\n", X, Y)
@@ -1364,51 +1500,116 @@ func ProcessButtons(X int, Y int,
 		}
 
 		// Get an upto date copy of the switch information by updating our copy of the switch sequence.
-		sequences[Y] = common.RefreshSequence(Y, commandChannels, updateChannels)
+		sequences[rowSequence] = common.RefreshSequence(rowSequence, commandChannels, updateChannels)
 
 		// We have a valid switch.
-		if X < len(sequences[Y].Switches) {
-			this.SwitchPositions[Y][X] = this.SwitchPositions[Y][X] + 1
-			valuesLength := len(sequences[Y].Switches[X].States)
-			if this.SwitchPositions[Y][X] == valuesLength {
-				this.SwitchPositions[Y][X] = 0
+		if X < len(sequences[rowSequence].Switches) {
+			// Step on from where the switch is, it may have advanced on its own.
+			position := sequences[rowSequence].Switches[X].CurrentPosition + 1
+			valuesLength := len(sequences[rowSequence].Switches[X].States)
+			if position == valuesLength {
+				position = 0
 			}
 
-			// Send a message to the sequence for it to toggle the selected switch.
-			// Y is the sequence.
-			// X is the switch.
-			cmd := common.Command{
-				Action: common.UpdateSwitch,
-				Args: []common.Arg{
-					{Name: "SwitchNumber", Value: X},
-					{Name: "SwitchPosition", Value: this.SwitchPositions[Y][X]},
-				},
-			}
+			// The next state may need another switch set first.
+			err := common.SwitchMoveAllowed(sequences[rowSequence].Switches, X, position)
+			if err != nil {
+				showError(this, err)
+				flashSwitchRefused(X, Y, sequences[rowSequence].Switches[X], eventsForLaunchpad, guiButtons)
+			} else {
+				this.SwitchPositions[rowSequence][X] = position
+
+				// Send a message to the sequence for it to toggle the selected switch.
+				// Y is the sequence.
+				// X is the switch.
+				cmd := common.Command{
+					Action: common.UpdateSwitch,
+					Args: []common.Arg{
+						{Name: "SwitchNumber", Value: X},
+						{Name: "SwitchPosition", Value: this.SwitchPositions[rowSequence][X]},
+					},
+				}
 
-			// Send a message to the switch sequence.
-			common.SendCommandToAllSequenceOfType(sequences, cmd, commandChannels, "switch")
+				// Send a message to the switch sequence.
+				common.SendCommandToAllSequenceOfType(sequences, cmd, commandChannels, "switch")
+			}
 		}
 	}
 
+	// P R O G R A M M E R - Select and deselect fixtures for the programmer.
+	if X >= 0 && X < 8 && Y >= 0 &&
+		this.SelectedSequence == rowSequence &&
+		this.SelectedMode[this.SelectedSequence] == PROGRAMMER {
+		programmerFixturePressed(X, sequences, this, fixturesConfig, eventsForLaunchpad, guiButtons)
+		return
+	}
+
+	// S U B M A S T E R S - Choose the fixture the bottom row dims.
+	if X >= 0 && X < 8 && Y >= 0 &&
+		this.SelectedSequence == rowSequence &&
+		this.SelectedMode[this.SelectedSequence] == SUBMASTERS {
+		submasterFixturePressed(X, sequences, this, eventsForLaunchpad, guiButtons)
+		return
+	}
+
+	// C U E S - GO and BACK on the selected sequence's row.
+	if X >= 0 && X < 8 && Y >= 0 &&
+		this.SelectedSequence == rowSequence &&
+		this.SelectedMode[this.SelectedSequence] == CUES {
+		cueButtonPressed(X, this, guiButtons)
+		return
+	}
+
+	// P R E S E T S - Page through the presets on the selected sequence's row.
+	if X >= 0 && X < 8 && Y >= 0 &&
+		this.SelectedSequence == rowSequence &&
+		this.SelectedMode[this.SelectedSequence] == PRESETS {
+		presetPageButtonPressed(X, sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
+		return
+	}
+
 	// D I S A B L E  / E N A B L E   F I X T U R E  S T A T U S - Used to toggle the scanner state from on, inverted or off.
 	if X >= 0 && X < 8 &&
 		Y >= 0 &&
-		Y < 4 &&
+		rowSequence >= 0 && rowSequence < len(sequences) &&
 		this.SelectedMode[this.SelectedSequence] == STATUS {
 
 		if debug {
 			fmt.Printf("Disable Fixture X:%d Y:%d\n", X, Y)
-			fmt.Printf("Fixture State Enabled %t  Inverted %t Reversed %t\n", this.FixtureState[Y][X].Enabled, this.FixtureState[Y][X].RGBInverted, this.FixtureState[Y][X].RGBInverted)
+			fmt.Printf("Fixture State Enabled %t  Inverted %t Reversed %t\n", this.FixtureState[rowSequence][X].Enabled, this.FixtureState[rowSequence][X].RGBInverted, this.FixtureState[rowSequence][X].RGBInverted)
 		}
 
 		// Rotate the  fixture state based on last fixture state.
-		setFixtureStatus(this, Y, X, commandChannels, sequences[Y])
+		// Allow for the page of fixtures being shown, the last page may not be full.
+		fixtureNumber := X + this.FixturePage[rowSequence]*common.FIXTURES_PER_PAGE
+		if fixtureNumber < len(this.FixtureState[rowSequence]) {
+			setFixtureStatus(this, rowSequence, fixtureNumber, commandChannels, sequences[rowSequence])
+		}
 
 		// Show the status.
-		showFixtureStatus(Y, sequences[Y].Number, sequences[Y].NumberFixtures, this, eventsForLaunchpad, guiButtons, commandChannels)
+		showFixtureStatus(rowSequence, sequences[rowSequence].Number, sequences[rowSequence].NumberFixtures, this, eventsForLaunchpad, guiButtons, commandChannels)
 
 	}
 
+	// LEFT & RIGHT ARROWS - Page through the fixtures when a sequence has more than eight.
+	// RGB sequences page with the arrows, scanners only page while the fixture status, programmer or submasters are shown as the arrows move the pan.
+	if (X == 2 || X == 3) && Y == -1 &&
+		common.NumberOfFixturePages(sequences[this.SelectedSequence].NumberFixtures) > 1 &&
+		!this.Static[this.TargetSequence] &&
+		(sequences[this.SelectedSequence].Type != "scanner" || this.SelectedMode[this.SelectedSequence] == STATUS || this.SelectedMode[this.SelectedSequence] == PROGRAMMER ||
+			this.SelectedMode[this.SelectedSequence] == SUBMASTERS) {
+
+		if debug {
+			fmt.Printf("PAGE ARROW X:%d\n", X)
+		}
+
+		buttonTouched(common.Button{X: X, Y: Y}, common.Cyan, common.White, eventsForLaunchpad, guiButtons)
+
+		changeFixturePage(this, sequences, X == 3, eventsForLaunchpad, guiButtons, commandChannels)
+
+		return
+	}
+
 	// DOWN ARROW
 	if X == 1 && Y == -1 && sequences[this.SelectedSequence].Type == "scanner" {
 
@@ -1639,7 +1840,7 @@ func ProcessButtons(X int, Y int,
 
 	// S E L E C T    S C A N N E R   C O L O R
 	if X >= 0 && X < 8 && Y != -1 &&
-		this.SelectedSequence == Y && // Make sure the buttons pressed are for this sequence.
+		this.SelectedSequence == rowSequence && // Make sure the buttons pressed are for this sequence.
 		!this.EditFixtureSelectionMode &&
 		sequences[this.SelectedSequence].Type == "scanner" &&
 		this.Functions[this.SelectedSequence][common.Function5_Color].State {
@@ -1724,7 +1925,7 @@ func ProcessButtons(X int, Y int,
 
 	// S E L E C T   S C A N N E R   G O B O
 	if X >= 0 && X < 8 && Y != -1 &&
-		this.SelectedSequence == Y && // Make sure the buttons pressed are for this sequence.
+		this.SelectedSequence == rowSequence && // Make sure the buttons pressed are for this sequence.
 		!this.EditFixtureSelectionMode &&
 		sequences[this.SelectedSequence].Type == "scanner" &&
 		this.Functions[this.SelectedSequence][common.Function6_Static_Gobo].State {
@@ -1778,7 +1979,7 @@ func ProcessButtons(X int, Y int,
 	if X >= 0 && X < 8 &&
 		Y != -1 &&
 		!this.EditFixtureSelectionMode &&
-		this.SelectedSequence == Y && // Make sure the buttons pressed are for this sequence.
+		this.SelectedSequence == rowSequence && // Make sure the buttons pressed are for this sequence.
 		(this.SelectedMode[this.SelectedSequence] == NORMAL ||
 			this.SelectedMode[this.SelectedSequence] == NORMAL_STATIC ||
 			this.SelectedMode[this.SelectedSequence] == CHASER_DISPLAY ||
@@ -1786,6 +1987,12 @@ func ProcessButtons(X int, Y int,
 		!this.ShowStaticColorPicker && // Not In Color Picker Mode.
 		getStatic(this) { // Static Function On in any sequence
 
+		// Allow for the page of fixtures being shown, the last page may not be full.
+		fixtureNumber := X + this.FixturePage[this.EditWhichStaticSequence]*common.FIXTURES_PER_PAGE
+		if fixtureNumber >= sequences[this.EditWhichStaticSequence].NumberFixtures {
+			return
+		}
+
 		this.TargetSequence = this.EditWhichStaticSequence
 		this.DisplaySequence = this.SelectedSequence
 
@@ -1796,13 +2003,13 @@ func ProcessButtons(X int, Y int,
 		}
 
 		// Save the selected fixture number.
-		this.SelectedStaticFixtureNumber = X
+		this.SelectedStaticFixtureNumber = fixtureNumber
 
 		// Reset Clear pressed flag so we can clear next selection
 		this.ClearPressed[this.TargetSequence] = false
 
 		// The current color is help in our local copy.
-		color := sequences[this.TargetSequence].StaticColors[X].Color
+		color := sequences[this.TargetSequence].StaticColors[this.SelectedStaticFixtureNumber].Color
 		if color == common.EmptyColor {
 			color = FindCurrentColor(this.SelectedStaticFixtureNumber, this.SelectedSequence, *sequences[this.TargetSequence])
 		}
@@ -1870,6 +2077,15 @@ func ProcessButtons(X int, Y int,
 			}
 			common.SendCommandToSequence(this.TargetSequence, cmd, commandChannels)
 
+			// Have the scanners follow by picking their nearest color wheel slot.
+			cmd = common.Command{
+				Action: common.UpdateScannerColorByRGB,
+				Args: []common.Arg{
+					{Name: "Color", Value: sequences[this.TargetSequence].StaticColors[this.SelectedStaticFixtureNumber].Color},
+				},
+			}
+			common.SendCommandToAllSequenceOfType(sequences, cmd, commandChannels, "scanner")
+
 			this.SelectAllStaticFixtures = false
 
 		} else {
@@ -1964,7 +2180,7 @@ func ProcessButtons(X int, Y int,
 
 	// F U N C T I O N  K E Y S
 	if X >= 0 && X < 8 && Y >= 0 && Y < 3 &&
-		this.SelectedSequence == Y && // Make sure the buttons pressed are for this sequence.
+		this.SelectedSequence == rowSequence && // Make sure the buttons pressed are for this sequence.
 		this.SelectedMode[this.SelectedSequence] == FUNCTION || this.SelectedMode[this.SelectedSequence] == CHASER_FUNCTION {
 		processFunctions(X, Y, sequences, this, eventsForLaunchpad, guiButtons, commandChannels, updateChannels)
 		return
@@ -1977,26 +2193,16 @@ func ProcessButtons(X int, Y int,
 			fmt.Printf("BLACKOUT\n")
 		}
 
+		// Blackout has to get through, so let everything carry on first.
+		if this.Frozen {
+			Unfreeze(this, eventsForLaunchpad, guiButtons)
+		}
+
 		// Turn off the flashing save button
 		this.SavePreset = false
-		common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+		saveButtonOff(this, eventsForLaunchpad, guiButtons)
 
-		if !this.Blackout {
-			this.Blackout = true
-			cmd := common.Command{
-				Action: common.Blackout,
-			}
-			common.SendCommandToAllSequence(cmd, commandChannels)
-			common.LightLamp(common.Button{X: X, Y: Y}, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			common.FlashLight(common.BLACKOUT_BUTTON, common.Magenta, common.White, eventsForLaunchpad, guiButtons)
-		} else {
-			this.Blackout = false
-			cmd := common.Command{
-				Action: common.Normal,
-			}
-			common.SendCommandToAllSequence(cmd, commandChannels)
-			common.LightLamp(common.Button{X: X, Y: Y}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-		}
+		ToggleBlackout(this, commandChannels, eventsForLaunchpad, guiButtons)
 		return
 	}
 }
@@ -2029,7 +2235,7 @@ func AllFixturesOff(sequences []*common.Sequence, eventsForLaunchpad chan common
 		if sequences[y].Type != "switch" && sequences[y].Label != "chaser" {
 			for x := 0; x < 8; x++ {
 				common.LightLamp(common.Button{X: x, Y: y}, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-				fixture.MapFixtures(false, false, y, x, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, true, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+				fixture.MapFixtures(dmx.SequenceSource(y), false, false, y, x, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, true, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 				common.LabelButton(x, y, "", guiButtons)
 			}
 		}
@@ -2136,11 +2342,11 @@ func ShowSelectFixtureButtons(targetSequence common.Sequence, displaySequence in
 		}
 		if fixture.Flash {
 			White := common.Color{R: 255, G: 255, B: 255}
-			common.FlashLight(common.Button{X: fixtureNumber, Y: displaySequence}, fixture.Color, White, eventsForLaunchpad, guiButtons)
+			common.FlashLight(common.Button{X: fixtureNumber, Y: common.SequenceRow(displaySequence)}, fixture.Color, White, eventsForLaunchpad, guiButtons)
 		} else {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: displaySequence}, fixture.Color, targetSequence.Master, eventsForLaunchpad, guiButtons)
+			common.LightLamp(common.Button{X: fixtureNumber, Y: common.SequenceRow(displaySequence)}, fixture.Color, targetSequence.Master, eventsForLaunchpad, guiButtons)
 		}
-		common.LabelButton(fixtureNumber, displaySequence, fixture.Label, guiButtons)
+		common.LabelButton(fixtureNumber, common.SequenceRow(displaySequence), fixture.Label, guiButtons)
 	}
 	if debug {
 		fmt.Printf("Selected Fixture is %d\n", this.SelectedFixture)
@@ -2168,12 +2374,12 @@ func ShowGoboSelectionButtons(sequence common.Sequence, this *CurrentState, even
 		}
 		if gobo.Flash {
 			Black := common.Color{R: 0, G: 0, B: 0}
-			common.FlashLight(common.Button{X: goboNumber, Y: this.SelectedSequence}, gobo.Color, Black, eventsForLaunchpad, guiButtons)
+			common.FlashLight(common.Button{X: goboNumber, Y: common.SequenceRow(this.SelectedSequence)}, gobo.Color, Black, eventsForLaunchpad, guiButtons)
 		} else {
-			common.LightLamp(common.Button{X: goboNumber, Y: this.SelectedSequence}, gobo.Color, sequence.Master, eventsForLaunchpad, guiButtons)
+			common.LightLamp(common.Button{X: goboNumber, Y: common.SequenceRow(this.SelectedSequence)}, gobo.Color, sequence.Master, eventsForLaunchpad, guiButtons)
 		}
 		goboName := common.FormatLabel(gobo.Name)
-		common.LabelButton(goboNumber, this.SelectedSequence, goboName, guiButtons)
+		common.LabelButton(goboNumber, common.SequenceRow(this.SelectedSequence), goboName, guiButtons)
 	}
 }
 
@@ -2194,7 +2400,7 @@ func ShowScannerColorSelectionButtons(sequence common.Sequence, this *CurrentSta
 
 		for _, fixture := range fixtures.Fixtures {
 			if fixture.Group == this.SelectedSequence+1 {
-				common.LightLamp(common.Button{X: fixture.Number - 1, Y: this.SelectedSequence}, common.White, sequence.Master, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: fixture.Number - 1, Y: common.SequenceRow(this.SelectedSequence)}, common.White, sequence.Master, eventsForLaunchpad, guiButtons)
 			}
 		}
 		if this.GUI {
@@ -2216,16 +2422,33 @@ func ShowScannerColorSelectionButtons(sequence common.Sequence, this *CurrentSta
 
 		if lamp.Flash {
 			Black := common.Color{R: 0, G: 0, B: 0}
-			common.FlashLight(common.Button{X: fixtureNumber, Y: this.SelectedSequence}, lamp.Color, Black, eventsForLaunchpad, guiButtons)
+			common.FlashLight(common.Button{X: fixtureNumber, Y: common.SequenceRow(this.SelectedSequence)}, lamp.Color, Black, eventsForLaunchpad, guiButtons)
 		} else {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: this.SelectedSequence}, lamp.Color, sequence.Master, eventsForLaunchpad, guiButtons)
+			common.LightLamp(common.Button{X: fixtureNumber, Y: common.SequenceRow(this.SelectedSequence)}, lamp.Color, sequence.Master, eventsForLaunchpad, guiButtons)
 		}
 		// Remove any labels.
-		common.LabelButton(fixtureNumber, this.SelectedSequence, "", guiButtons)
+		common.LabelButton(fixtureNumber, common.SequenceRow(this.SelectedSequence), "", guiButtons)
 	}
 	return nil
 }
 
+// flashSwitchRefused lights a switch red for a moment when it can't move, then back to the state it's in.
+func flashSwitchRefused(X int, Y int, swiTch common.Switch, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+	common.LightLamp(common.Button{X: X, Y: Y}, common.Red, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	time.AfterFunc(500*time.Millisecond, func() {
+		color, _ := common.GetRGBColorByName(swiTch.States[swiTch.CurrentPosition].ButtonColor)
+		common.LightLamp(common.Button{X: X, Y: Y}, color, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	})
+}
+
+// showError reports a problem to the user, on the GUI too if it's running.
+func showError(this *CurrentState, err error) {
+	fmt.Printf("error: %s\n", err.Error())
+	if this.GUI {
+		displayErrorPopUp(this.MyWindow, err.Error())
+	}
+}
+
 func displayErrorPopUp(w fyne.Window, errorMessage string) (modal *widget.PopUp) {
 
 	title := widget.NewLabel("Error")
@@ -2261,7 +2484,7 @@ func displayErrorPopUp(w fyne.Window, errorMessage string) (modal *widget.PopUp)
 func ClearPatternSelectionButtons(mySequenceNumber int, sequence common.Sequence, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
 	// Check if we need to flash this button.
 	for myFixtureNumber := 0; myFixtureNumber < 4; myFixtureNumber++ {
-		common.LightLamp(common.Button{X: myFixtureNumber, Y: mySequenceNumber}, common.Black, sequence.Master, eventsForLaunchpad, guiButtons)
+		common.LightLamp(common.Button{X: myFixtureNumber, Y: common.SequenceRow(mySequenceNumber)}, common.Black, sequence.Master, eventsForLaunchpad, guiButtons)
 	}
 }
 
@@ -2285,11 +2508,11 @@ func ShowPatternSelectionButtons(this *CurrentState, master int, targetSequence
 				fmt.Printf("pattern is %s\n", pattern.Name)
 			}
 			if pattern.Number == targetSequence.SelectedPattern {
-				common.FlashLight(common.Button{X: pattern.Number, Y: displaySequence}, common.White, common.LightBlue, eventsForLaunchpad, guiButtons)
+				common.FlashLight(common.Button{X: pattern.Number, Y: common.SequenceRow(displaySequence)}, common.White, common.LightBlue, eventsForLaunchpad, guiButtons)
 			} else {
-				common.LightLamp(common.Button{X: pattern.Number, Y: displaySequence}, common.LightBlue, master, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: pattern.Number, Y: common.SequenceRow(displaySequence)}, common.LightBlue, master, eventsForLaunchpad, guiButtons)
 			}
-			common.LabelButton(pattern.Number, displaySequence, pattern.Label, guiButtons)
+			common.LabelButton(pattern.Number, common.SequenceRow(displaySequence), pattern.Label, guiButtons)
 		}
 		return
 	}
@@ -2297,11 +2520,11 @@ func ShowPatternSelectionButtons(this *CurrentState, master int, targetSequence
 	if targetSequence.Type == "scanner" {
 		for _, pattern := range targetSequence.ScannerAvailablePatterns {
 			if pattern.Number == targetSequence.SelectedPattern {
-				common.FlashLight(common.Button{X: pattern.Number, Y: displaySequence}, common.White, common.LightBlue, eventsForLaunchpad, guiButtons)
+				common.FlashLight(common.Button{X: pattern.Number, Y: common.SequenceRow(displaySequence)}, common.White, common.LightBlue, eventsForLaunchpad, guiButtons)
 			} else {
-				common.LightLamp(common.Button{X: pattern.Number, Y: displaySequence}, common.LightBlue, master, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: pattern.Number, Y: common.SequenceRow(displaySequence)}, common.LightBlue, master, eventsForLaunchpad, guiButtons)
 			}
-			common.LabelButton(pattern.Number, displaySequence, pattern.Label, guiButtons)
+			common.LabelButton(pattern.Number, common.SequenceRow(displaySequence), pattern.Label, guiButtons)
 		}
 		return
 	}
@@ -2322,7 +2545,7 @@ func InitButtons(this *CurrentState, eventsForLaunchpad chan common.ALight, guiB
 	common.LightLamp(common.BLACKOUT_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
 
 	// Light up any existing presets.
-	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 
 	// Light the buttons at the bottom.
 	common.ShowBottomButtons("rgb", eventsForLaunchpad, guiButtons)
@@ -2330,8 +2553,8 @@ func InitButtons(this *CurrentState, eventsForLaunchpad chan common.ALight, guiB
 	// Light the top buttons.
 	common.ShowTopButtons("rgb", eventsForLaunchpad, guiButtons)
 
-	// Light the first sequence as the default selected.
-	this.SelectedSequence = 0
+	// Light the first sequence on the page as the default selected.
+	this.SelectedSequence = firstPageSequence()
 	SequenceSelect(eventsForLaunchpad, guiButtons, this)
 
 }
@@ -2466,33 +2689,45 @@ func printMode(mode int) string {
 	if mode == STATUS {
 		return "STATUS"
 	}
+	if mode == PROGRAMMER {
+		return "PROGRAMMER"
+	}
+	if mode == CUES {
+		return "CUES"
+	}
+	if mode == PRESETS {
+		return "PRESETS"
+	}
+	if mode == SUBMASTERS {
+		return "SUBMASTERS"
+	}
 	return "UNKNOWN"
 }
 
 func SequenceSelect(eventsForLauchpad chan common.ALight, guiButtons chan common.ALight, this *CurrentState) {
 
-	NumberOfSelectableSequences := 3
-
 	if debug {
 		fmt.Printf("SequenceSelect\n")
 	}
 
-	if this.SelectedSequence > NumberOfSelectableSequences-1 {
+	// Only the sequences on the paged rows have select buttons.
+	selectedRow := common.SequenceRow(this.SelectedSequence)
+	if selectedRow < 0 || selectedRow >= common.SEQUENCE_PAGE_ROWS {
 		return
 	}
 
 	// Turn off all sequence lights.
-	for seq := 0; seq < NumberOfSelectableSequences; seq++ {
-		common.LightLamp(common.Button{X: 8, Y: seq}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+	for row := 0; row < common.SEQUENCE_PAGE_ROWS; row++ {
+		common.LightLamp(common.Button{X: 8, Y: row}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 	}
 
 	if this.SelectedType == "scanner" && this.ScannerChaser[this.SelectedSequence] &&
 		(this.SelectedMode[this.SelectedSequence] == CHASER_FUNCTION || this.SelectedMode[this.SelectedSequence] == CHASER_DISPLAY) {
 		// If we are in shutter chaser mode, light the lamp yellow.
-		common.LightLamp(common.Button{X: 8, Y: this.SelectedSequence}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+		common.LightLamp(common.Button{X: 8, Y: selectedRow}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 	} else {
 		// Now turn pink the selected sequence select light.
-		common.LightLamp(common.Button{X: 8, Y: this.SelectedSequence}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+		common.LightLamp(common.Button{X: 8, Y: selectedRow}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 	}
 
 }
@@ -142,7 +142,7 @@ func Clear(X int, Y int, this *CurrentState, sequences []*common.Sequence, dmxCo
 
 	// Turn off the flashing save button.
 	this.SavePreset = false
-	common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	saveButtonOff(this, eventsForLaunchpad, guiButtons)
 
 	// Turn off the Running light.
 	common.LightLamp(common.RUNNING_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
@@ -162,6 +162,13 @@ func Clear(X int, Y int, this *CurrentState, sequences []*common.Sequence, dmxCo
 		trigger.State = false
 	}
 
+	// Hand any fixtures held by the programmer back to the sequences.
+	if this.Programmer != nil {
+		this.Programmer.Clear()
+		this.ProgrammerChannel = 0
+		common.UpdateStatusBar("", "programmer", false, guiButtons)
+	}
+
 	// Update status bar.
 	common.UpdateStatusBar("Version 2.1", "version", false, guiButtons)
 
@@ -207,7 +214,9 @@ func Clear(X int, Y int, this *CurrentState, sequences []*common.Sequence, dmxCo
 			this.Functions[sequenceNumber][common.Function1_Pattern].State = false
 			this.Functions[sequenceNumber][common.Function2_Auto_Color].State = false
 			this.Functions[sequenceNumber][common.Function3_Auto_Pattern].State = false
-			this.Functions[sequenceNumber][common.Function4_Bounce].State = false
+			this.StepOrder[sequenceNumber] = common.STEP_ORDER_FORWARD
+			this.Swing[sequenceNumber] = 0
+			showStepOrder(this, sequenceNumber)
 			this.Functions[sequenceNumber][common.Function5_Color].State = false
 			this.Functions[sequenceNumber][common.Function6_Static_Gobo].State = false
 			this.Functions[sequenceNumber][common.Function7_Invert_Chase].State = false
@@ -243,10 +252,10 @@ func Clear(X int, Y int, this *CurrentState, sequences []*common.Sequence, dmxCo
 
 	// Clear the presets and display them.
 	presets.ClearPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
-	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 
-	// Set the first sequnence.
-	this.SelectedSequence = 0
+	// Set the first sequnence on the page.
+	this.SelectedSequence = firstPageSequence()
 	HandleSelect(sequences, this, eventsForLaunchpad, commandChannels, guiButtons)
 
 }
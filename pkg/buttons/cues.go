@@ -0,0 +1,186 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These cue functions put GO and BACK on the launchpad and make the change
+// each cue asks for. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+	"github.com/dhowlett99/dmxlights/pkg/presets"
+)
+
+// In cues mode the selected sequence's row has BACK on the left, GO on the right, the current
+// cue followed by the next ones and a pair of buttons to set the preset crossfade time.
+const CUE_BACK_BUTTON = 0
+const CUE_FADE_DOWN_BUTTON = 5
+const CUE_FADE_UP_BUTTON = 6
+const CUE_GO_BUTTON = 7
+
+// showCueButtons lights the GO, BACK and fade time buttons and the cues coming up on a sequence's row.
+func showCueButtons(sequenceNumber int, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	list := this.CuePlayer.List()
+	current := this.CuePlayer.Current()
+	row := common.SequenceRow(sequenceNumber)
+
+	common.LightLamp(common.Button{X: CUE_BACK_BUTTON, Y: row}, common.Red, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(CUE_BACK_BUTTON, row, "BACK", guiButtons)
+
+	common.LightLamp(common.Button{X: CUE_GO_BUTTON, Y: row}, common.Green, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(CUE_GO_BUTTON, row, "GO", guiButtons)
+
+	common.LightLamp(common.Button{X: CUE_FADE_DOWN_BUTTON, Y: row}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(CUE_FADE_DOWN_BUTTON, row, "FADE-", guiButtons)
+
+	common.LightLamp(common.Button{X: CUE_FADE_UP_BUTTON, Y: row}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(CUE_FADE_UP_BUTTON, row, "FADE+", guiButtons)
+
+	// Before the first GO the row starts with the first cue.
+	first := current
+	if first < 0 {
+		first = 0
+	}
+	for X := CUE_BACK_BUTTON + 1; X < CUE_FADE_DOWN_BUTTON; X++ {
+		cueNumber := first + X - 1
+		button := common.Button{X: X, Y: row}
+		switch {
+		case cueNumber >= len(list.Cues):
+			common.LightLamp(button, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(X, row, "", guiButtons)
+		case cueNumber == current:
+			common.LightLamp(button, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(X, row, list.Cues[cueNumber].Name, guiButtons)
+		default:
+			common.LightLamp(button, common.Blue, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(X, row, list.Cues[cueNumber].Name, guiButtons)
+		}
+	}
+}
+
+// cueButtonPressed handles the GO, BACK and fade time buttons. The cue player posts each cue back to be recalled.
+func cueButtonPressed(X int, this *CurrentState, guiButtons chan common.ALight) {
+
+	var err error
+	switch X {
+	case CUE_BACK_BUTTON:
+		_, err = this.CuePlayer.Back()
+	case CUE_GO_BUTTON:
+		_, err = this.CuePlayer.Go()
+	case CUE_FADE_DOWN_BUTTON:
+		this.PresetFade = nextPresetFade(this.PresetFade, false)
+		common.UpdateStatusBar(presets.FadeLabel(this.PresetFade), "presetfade", false, guiButtons)
+	case CUE_FADE_UP_BUTTON:
+		this.PresetFade = nextPresetFade(this.PresetFade, true)
+		common.UpdateStatusBar(presets.FadeLabel(this.PresetFade), "presetfade", false, guiButtons)
+	default:
+		return
+	}
+	if err != nil {
+		common.UpdateStatusBar(err.Error(), "cue", false, guiButtons)
+	}
+}
+
+// nextPresetFade steps the global crossfade time up or down through the times on offer, stopping at each end.
+func nextPresetFade(fade float64, up bool) float64 {
+	if up {
+		for _, fadeTime := range presets.FadeTimes {
+			if fadeTime > fade {
+				return fadeTime
+			}
+		}
+		return presets.FadeTimes[len(presets.FadeTimes)-1]
+	}
+	for index := len(presets.FadeTimes) - 1; index >= 0; index-- {
+		if presets.FadeTimes[index] < fade {
+			return presets.FadeTimes[index]
+		}
+	}
+	return presets.FadeTimes[0]
+}
+
+// CueRecall is a cue the cue player wants recalled. The player runs on its own thread so
+// it posts the cue to the buttons thread, which owns the current state.
+type CueRecall struct {
+	Number int
+	Cue    cues.Cue
+}
+
+// RecallCue makes the change a cue asks for, crossfading to it over the cue's fade time while its preset
+// is recalled and its commands are sent to the sequences. Called on the buttons thread.
+func RecallCue(number int, cue cues.Cue, sequences []*common.Sequence, this *CurrentState, fixturesConfig *fixture.Fixtures,
+	commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, updateChannels []chan common.Sequence) {
+
+	if debug {
+		fmt.Printf("RecallCue %d %s\n", number+1, cue.Name)
+	}
+
+	// Start the crossfade before anything changes, so it starts from the lights as they are now.
+	dmx.StartCrossfade(cue.FadeTime(), time.Now())
+
+	// Loading a preset puts every sequence back into normal mode, so remember if we're showing the cue buttons.
+	cueSequence := this.SelectedSequence
+	showingCues := this.SelectedMode[cueSequence] == CUES
+
+	if cue.Preset != "" {
+		id := cue.PresetID()
+		if !this.PresetsStore[id].State {
+			fmt.Printf("error: cue %s: preset %s is empty\n", cue.Name, cue.Preset)
+		} else {
+			loadConfig(sequences, this, id, cue.FadeTime(), fixturesConfig, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
+			common.StartStaticSequences(sequences, commandChannels)
+		}
+	}
+
+	for _, cueCommand := range cue.Commands {
+		cmd, err := cueCommand.Command()
+		if err != nil {
+			fmt.Printf("error: cue %s: %s\n", cue.Name, err.Error())
+			continue
+		}
+		sequenceNumber := cueCommand.Sequence - 1
+		if sequenceNumber < 0 || sequenceNumber >= len(sequences) {
+			fmt.Printf("error: cue %s: no sequence %d\n", cue.Name, cueCommand.Sequence)
+			continue
+		}
+		common.SendCommandToSequence(sequenceNumber, cmd, commandChannels)
+
+		// Keep our local copy of the sequence up to date.
+		switch cmd.Action {
+		case common.Start:
+			this.Running[sequenceNumber] = true
+		case common.Stop:
+			this.Running[sequenceNumber] = false
+		case common.UpdateSpeed:
+			this.Speed[sequenceNumber] = cueCommand.Value
+		}
+	}
+	common.ShowRunningStatus(this.Running[this.SelectedSequence], eventsForLaunchpad, guiButtons)
+
+	if showingCues {
+		this.SelectedSequence = cueSequence
+		this.SelectedMode[cueSequence] = CUES
+		displayMode(cueSequence, CUES, this, sequences, eventsForLaunchpad, guiButtons, commandChannels)
+		SequenceSelect(eventsForLaunchpad, guiButtons, this)
+	}
+
+	common.UpdateStatusBar(fmt.Sprintf("Cue %d/%d %s", number+1, len(this.CuePlayer.List().Cues), cue.Name), "cue", false, guiButtons)
+}
@@ -146,6 +146,86 @@ func displayMode(sequenceNumber int, mode int, this *CurrentState, sequences []*
 		// Display the fixture status bar.
 		showFixtureStatus(this.TargetSequence, sequences[sequenceNumber].Number, sequences[sequenceNumber].NumberFixtures, this, eventsForLaunchpad, guiButtons, commandChannels)
 
+		return
+
+	case mode == PROGRAMMER:
+
+		if debug {
+			fmt.Printf("%d: DisplayMode: PROGRAMMER\n", sequenceNumber)
+		}
+
+		// We don't want a shutter chaser in view while selecting fixtures.
+		if this.SelectedType == "scanner" {
+			common.HideSequence(this.ChaserSequenceNumber, commandChannels)
+		}
+
+		// Hide the normal sequence.
+		common.HideSequence(sequenceNumber, commandChannels)
+
+		// Show which fixtures are in the programmer.
+		showProgrammerFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+
+		return
+
+	case mode == CUES:
+
+		if debug {
+			fmt.Printf("%d: DisplayMode: CUES\n", sequenceNumber)
+		}
+
+		// We don't want a shutter chaser in view while stepping through cues.
+		if this.SelectedType == "scanner" {
+			common.HideSequence(this.ChaserSequenceNumber, commandChannels)
+		}
+
+		// Hide the normal sequence.
+		common.HideSequence(sequenceNumber, commandChannels)
+
+		// Show the GO and BACK buttons and the cues coming up.
+		showCueButtons(sequenceNumber, this, eventsForLaunchpad, guiButtons)
+
+		return
+
+	case mode == PRESETS:
+
+		if debug {
+			fmt.Printf("%d: DisplayMode: PRESETS\n", sequenceNumber)
+		}
+
+		// We don't want a shutter chaser in view while paging through presets.
+		if this.SelectedType == "scanner" {
+			common.HideSequence(this.ChaserSequenceNumber, commandChannels)
+		}
+
+		// Hide the normal sequence.
+		common.HideSequence(sequenceNumber, commandChannels)
+
+		// Show the page buttons.
+		showPresetPageButtons(sequenceNumber, this, eventsForLaunchpad, guiButtons)
+
+		return
+
+	case mode == SUBMASTERS:
+
+		if debug {
+			fmt.Printf("%d: DisplayMode: SUBMASTERS\n", sequenceNumber)
+		}
+
+		// We don't want a shutter chaser in view while setting the submasters.
+		if this.SelectedType == "scanner" {
+			common.HideSequence(this.ChaserSequenceNumber, commandChannels)
+		}
+
+		// Hide the normal sequence.
+		common.HideSequence(sequenceNumber, commandChannels)
+
+		// The bottom row starts off setting the group's submaster.
+		this.SubmasterFixture = SUBMASTER_GROUP
+
+		// Show the fixture submaster levels.
+		showSubmasterFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+		showSubmasterStatus(this, guiButtons)
+
 		return
 	}
 
@@ -0,0 +1,253 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These fader functions turn the launchpad grid into a page of eight
+// faders with level meters. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+// A long press on the master brightness up button opens the fader page on the launchpad.
+const FADER_PAGE_LONG_PRESS = 1 * time.Second
+
+// Pads above a fader's level are lit dimly so each fader's column can be seen.
+const FADER_TRACK_BRIGHTNESS = 20
+
+// The first faders are the submasters of the sequence groups.
+const FADER_SEQUENCE_GROUPS = 4
+
+// fader is one of the eight faders on the fader page, one for each column of the grid.
+type fader struct {
+	Label string
+	Color common.Color
+	Min   int
+	Max   int
+	Get   func(this *CurrentState) int
+	Set   func(this *CurrentState, level int, guiButtons chan common.ALight)
+}
+
+// getFaders returns the faders shown on the fader page from left to right. The sequence group submasters,
+// the master brightness, the selected sequence's speed and strobe speed and the sound sensitivity.
+func getFaders(this *CurrentState) []fader {
+
+	faders := []fader{}
+
+	for groupNumber := 1; groupNumber <= FADER_SEQUENCE_GROUPS; groupNumber++ {
+		groupNumber := groupNumber
+		label := faderGroupLabel(this, groupNumber)
+		faders = append(faders, fader{
+			Label: label,
+			Color: common.Yellow,
+			Min:   0,
+			Max:   fixture.SUBMASTER_FULL,
+			Get: func(this *CurrentState) int {
+				return fixture.GroupSubmaster(groupNumber)
+			},
+			Set: func(this *CurrentState, level int, guiButtons chan common.ALight) {
+				fixture.SetGroupSubmaster(groupNumber, level)
+				common.UpdateStatusBar(fmt.Sprintf("Submaster: %s %d%%", label, level*100/fixture.SUBMASTER_FULL), "submaster", false, guiButtons)
+			},
+		})
+	}
+
+	faders = append(faders, fader{
+		Label: "Master",
+		Color: common.White,
+		Min:   0,
+		Max:   common.MAX_DMX_BRIGHTNESS,
+		Get: func(this *CurrentState) int {
+			return this.MasterBrightness
+		},
+		Set: func(this *CurrentState, level int, guiButtons chan common.ALight) {
+			setMasterBrightness(this, level, guiButtons)
+		},
+	})
+
+	faders = append(faders, fader{
+		Label: "Speed",
+		Color: common.Cyan,
+		Min:   1,
+		Max:   common.MAX_SPEED,
+		Get: func(this *CurrentState) int {
+			return this.Speed[this.SelectedSequence]
+		},
+		Set: func(this *CurrentState, level int, guiButtons chan common.ALight) {
+			this.TargetSequence = this.SelectedSequence
+			this.Speed[this.TargetSequence] = level
+			cmd := common.Command{
+				Action: common.UpdateSpeed,
+				Args: []common.Arg{
+					{Name: "Speed", Value: level},
+				},
+			}
+			common.SendCommandToSequence(this.TargetSequence, cmd, this.SequenceChannels.CommmandChannels)
+			// Speed is used to control fade time in mini sequencer so send to switch sequence as well.
+			common.SendCommandToSequence(this.SwitchSequenceNumber, cmd, this.SequenceChannels.CommmandChannels)
+			UpdateSpeed(this, guiButtons)
+		},
+	})
+
+	faders = append(faders, fader{
+		Label: "Strobe",
+		Color: common.Magenta,
+		Min:   0,
+		Max:   255,
+		Get: func(this *CurrentState) int {
+			return this.StrobeSpeed[this.SelectedSequence]
+		},
+		Set: func(this *CurrentState, level int, guiButtons chan common.ALight) {
+			this.StrobeSpeed[this.SelectedSequence] = level
+			cmd := common.Command{
+				Action: common.UpdateStrobeSpeed,
+				Args: []common.Arg{
+					{Name: "STROBE_SPEED", Value: level},
+				},
+			}
+			common.SendCommandToSequence(this.SelectedSequence, cmd, this.SequenceChannels.CommmandChannels)
+			if this.SelectedType == "scanner" && this.ScannerChaser[this.SelectedSequence] {
+				common.SendCommandToSequence(this.ChaserSequenceNumber, cmd, this.SequenceChannels.CommmandChannels)
+			}
+			common.UpdateStatusBar(fmt.Sprintf("Strobe %02d", level), "speed", false, guiButtons)
+		},
+	})
+
+	faders = append(faders, fader{
+		Label: "Sensitivity",
+		Color: common.Green,
+		Min:   0,
+		Max:   13,
+		Get: func(this *CurrentState) int {
+			return common.FindSensitivity(this.SoundGain)
+		},
+		Set: func(this *CurrentState, level int, guiButtons chan common.ALight) {
+			// Sensitivity 0 is a gain of -0.04, each step adds 0.01.
+			this.SoundGain = float32(level-4) / 100
+			for _, trigger := range this.SoundTriggers {
+				trigger.Gain = this.SoundGain
+			}
+			common.UpdateStatusBar(fmt.Sprintf("Sensitivity %02d", level), "sensitivity", false, guiButtons)
+		},
+	})
+
+	return faders
+}
+
+// faderGroupLabel names a sequence group's fader after the group in groups.yaml.
+func faderGroupLabel(this *CurrentState, groupNumber int) string {
+	if this.Groups != nil {
+		for _, group := range this.Groups.Groups {
+			if group.Number == strconv.Itoa(groupNumber) {
+				return group.Name
+			}
+		}
+	}
+	return fmt.Sprintf("Group %d", groupNumber)
+}
+
+// setMasterBrightness sets the master brightness of all the sequences.
+func setMasterBrightness(this *CurrentState, level int, guiButtons chan common.ALight) {
+	this.MasterBrightness = level
+	cmd := common.Command{
+		Action: common.Master,
+		Args: []common.Arg{
+			{Name: "Master", Value: this.MasterBrightness},
+		},
+	}
+	common.SendCommandToAllSequence(cmd, this.SequenceChannels.CommmandChannels)
+
+	// Update the status bar
+	common.UpdateStatusBar(fmt.Sprintf("Master %02d", this.MasterBrightness), "master", false, guiButtons)
+}
+
+// ToggleFaderPage opens the fader page or goes back to the normal grid.
+// Used by the launchpad and the GUI toolbar.
+func ToggleFaderPage(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+	if this.FaderPage {
+		closeFaderPage(this, eventsForLaunchpad, guiButtons)
+	} else {
+		openFaderPage(this, eventsForLaunchpad, guiButtons)
+	}
+}
+
+// openFaderPage covers the grid with the faders. The sequences carry on underneath.
+func openFaderPage(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Open Fader Page\n")
+	}
+
+	this.FaderPage = true
+	common.CoverGrid()
+
+	for column := range getFaders(this) {
+		showFader(column, this, eventsForLaunchpad, guiButtons)
+	}
+}
+
+// closeFaderPage goes back to the normal grid.
+func closeFaderPage(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Close Fader Page\n")
+	}
+
+	this.FaderPage = false
+	common.UncoverGrid(eventsForLaunchpad, guiButtons)
+}
+
+// faderPressed sets a fader from the pad touched in its column and shows its new level.
+func faderPressed(X int, Y int, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	faders := getFaders(this)
+	if X >= len(faders) {
+		return
+	}
+
+	thisFader := faders[X]
+	level := common.FaderLevel(Y, thisFader.Get(this), thisFader.Min, thisFader.Max)
+
+	if debug {
+		fmt.Printf("Fader %s set to %d\n", thisFader.Label, level)
+	}
+
+	thisFader.Set(this, level, guiButtons)
+	showFader(X, this, eventsForLaunchpad, guiButtons)
+}
+
+// showFader lights a fader's column as a meter showing its level.
+func showFader(column int, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	thisFader := getFaders(this)[column]
+	meter := common.FaderMeter(thisFader.Get(this), thisFader.Min, thisFader.Max)
+
+	for row, brightness := range meter {
+		if brightness < FADER_TRACK_BRIGHTNESS {
+			brightness = FADER_TRACK_BRIGHTNESS
+		}
+		common.LightCoverLamp(common.Button{X: column, Y: row}, thisFader.Color, brightness, eventsForLaunchpad, guiButtons)
+		label := ""
+		if row == 0 {
+			label = thisFader.Label
+		}
+		common.LabelCoverButton(column, row, label, guiButtons)
+	}
+}
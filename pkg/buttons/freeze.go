@@ -0,0 +1,79 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These freeze functions hold every sequence, fade and mini sequencer where
+// it is while the next look is set up. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// A long press on the save button freezes or unfreezes everything on the launchpad.
+const FREEZE_LONG_PRESS = 1 * time.Second
+
+// ToggleFreeze freezes everything if it's running or lets it carry on if it's frozen.
+// Used by the launchpad, the GUI toolbar and anything else that wants to freeze the show.
+func ToggleFreeze(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+	if this.Frozen {
+		Unfreeze(this, eventsForLaunchpad, guiButtons)
+	} else {
+		Freeze(this, eventsForLaunchpad, guiButtons)
+	}
+}
+
+// Freeze holds the output and every sequence, fade and mini sequencer where it is. Commands are still taken
+// so the next look can be set up, it's played when the freeze is released.
+func Freeze(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Freeze\n")
+	}
+
+	this.Frozen = true
+	dmx.FreezeOutput(time.Now())
+	common.Freeze()
+
+	common.FlashLight(common.SAVE_BUTTON, common.Cyan, common.White, eventsForLaunchpad, guiButtons)
+	common.UpdateStatusBar("FROZEN", "freeze", false, guiButtons)
+}
+
+// Unfreeze lets everything carry on from exactly where it was frozen.
+func Unfreeze(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	if debug {
+		fmt.Printf("Unfreeze\n")
+	}
+
+	this.Frozen = false
+	common.Unfreeze()
+	dmx.ReleaseOutput(time.Now())
+
+	saveButtonOff(this, eventsForLaunchpad, guiButtons)
+	common.UpdateStatusBar("", "freeze", false, guiButtons)
+}
+
+// saveButtonOff stops the save button flashing for save mode, it carries on flashing if we're frozen.
+func saveButtonOff(this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+	if this.Frozen {
+		common.FlashLight(common.SAVE_BUTTON, common.Cyan, common.White, eventsForLaunchpad, guiButtons)
+		return
+	}
+	common.LightLamp(common.SAVE_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+}
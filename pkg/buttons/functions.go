@@ -36,15 +36,15 @@ func ShowFunctionButtons(this *CurrentState, eventsForLauchpad chan common.ALigh
 			fmt.Printf("ShowFunctionButtons: function %s state %t\n", function.Name, function.State)
 		}
 		if !function.State && this.SelectedMode[this.DisplaySequence] != CHASER_FUNCTION { // Cyan
-			common.LightLamp(common.Button{X: index, Y: this.DisplaySequence}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+			common.LightLamp(common.Button{X: index, Y: common.SequenceRow(this.DisplaySequence)}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 		}
 		if !function.State && this.SelectedMode[this.DisplaySequence] == CHASER_FUNCTION { // Yellow
-			common.LightLamp(common.Button{X: index, Y: this.DisplaySequence}, common.Yellow, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+			common.LightLamp(common.Button{X: index, Y: common.SequenceRow(this.DisplaySequence)}, common.Yellow, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 		}
 		if function.State { // Magenta
-			common.LightLamp(common.Button{X: index, Y: this.DisplaySequence}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+			common.LightLamp(common.Button{X: index, Y: common.SequenceRow(this.DisplaySequence)}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 		}
-		common.LabelButton(index, this.DisplaySequence, function.Label, guiButtons)
+		common.LabelButton(index, common.SequenceRow(this.DisplaySequence), function.Label, guiButtons)
 	}
 }
 
@@ -219,49 +219,14 @@ func processFunctions(X int, Y int, sequences []*common.Sequence, this *CurrentS
 		return
 	}
 
-	// Function 4 Bounce - Toggle bounce feature on.
-	if X == common.Function4_Bounce && !this.Functions[this.TargetSequence][common.Function4_Bounce].State {
+	// Function 4 Step Order - Cycle through forward, bounce, ping-pong, random and shuffle.
+	if X == common.Function4_Bounce {
 
 		if debug {
-			fmt.Printf("Seq%d: Mode:%d common.Function4_Bounce On \n", this.TargetSequence, this.SelectedMode[this.TargetSequence])
+			fmt.Printf("Seq%d: Mode:%d common.Function4_Bounce Step Order %d\n", this.TargetSequence, this.SelectedMode[this.TargetSequence], this.StepOrder[this.TargetSequence])
 		}
 
-		this.Functions[this.TargetSequence][common.Function4_Bounce].State = true
-
-		cmd := common.Command{
-			Action: common.UpdateBounce,
-			Args: []common.Arg{
-				{Name: "Bounce", Value: true},
-			},
-		}
-		common.SendCommandToSequence(this.TargetSequence, cmd, commandChannels)
-
-		ShowFunctionButtons(this, eventsForLaunchpad, guiButtons)
-
-		// If we are in the chaser function mode, we wannt to make sure the sequence shows the shutter chaser.
-		if this.SelectedMode[this.DisplaySequence] == CHASER_FUNCTION {
-			// Jump straight to showing the shutter chaser.
-			this.DisplayChaserShortCut = true
-		}
-
-		return
-	}
-	// Function 4 Bounce - Toggle bounce feature off.
-	if X == common.Function4_Bounce && this.Functions[this.TargetSequence][common.Function4_Bounce].State {
-
-		if debug {
-			fmt.Printf("Seq%d: Mode:%d common.Function4_Bounce Off \n", this.TargetSequence, this.SelectedMode[this.TargetSequence])
-		}
-
-		this.Functions[this.TargetSequence][common.Function4_Bounce].State = false
-
-		cmd := common.Command{
-			Action: common.UpdateBounce,
-			Args: []common.Arg{
-				{Name: "Bounce", Value: false},
-			},
-		}
-		common.SendCommandToSequence(this.TargetSequence, cmd, commandChannels)
+		nextStepOrder(this, this.TargetSequence, commandChannels)
 
 		ShowFunctionButtons(this, eventsForLaunchpad, guiButtons)
 
@@ -360,7 +325,7 @@ func processFunctions(X int, Y int, sequences []*common.Sequence, this *CurrentS
 		this.SelectAllStaticFixtures = false
 
 		// Starting a static sequence will turn off any running sequence, so turn off the start lamp
-		common.LightLamp(common.Button{X: X, Y: this.DisplaySequence}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+		common.LightLamp(common.Button{X: X, Y: common.SequenceRow(this.DisplaySequence)}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
 		//  and remember that this sequence is off.
 		this.Running[this.TargetSequence] = false
 
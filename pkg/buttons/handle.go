@@ -141,10 +141,9 @@ func HandleSelect(sequences []*common.Sequence, this *CurrentState, eventsForLau
 		this.SelectedMode[this.SelectedSequence] = getNextMenuItem(this.SelectedMode[this.SelectedSequence], this.ScannerChaser[this.SelectedSequence], getStatic(this))
 	}
 	if !this.SelectButtonPressed[this.SelectedSequence] {
-		this.SelectButtonPressed[0] = false
-		this.SelectButtonPressed[1] = false
-		this.SelectButtonPressed[2] = false
-		this.SelectButtonPressed[3] = false
+		for sequenceNumber := range this.SelectButtonPressed {
+			this.SelectButtonPressed[sequenceNumber] = false
+		}
 		this.SelectButtonPressed[this.SelectedSequence] = true
 	}
 
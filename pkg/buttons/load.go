@@ -18,17 +18,30 @@ package buttons
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/dhowlett99/dmxlights/pkg/config"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
 	"github.com/dhowlett99/dmxlights/pkg/presets"
 )
 
 func loadConfig(sequences []*common.Sequence, this *CurrentState,
-	X int, Y int,
+	id string, fade time.Duration, fixturesConfig *fixture.Fixtures,
 	commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight,
 	guiButtons chan common.ALight, updateChannels []chan common.Sequence) {
 
+	// Check the preset can be read before stopping anything, the sequences carry on as they are if not.
+	_, err := config.LoadConfig(id)
+	if err != nil {
+		showError(this, err)
+		return
+	}
+
+	// Crossfade from what's on now to the preset, or snap if there's no fade time.
+	dmx.StartCrossfade(fade, time.Now())
+
 	// Stop all sequences, so we start in sync.
 	cmd := common.Command{
 		Action: common.Stop,
@@ -37,16 +50,36 @@ func loadConfig(sequences []*common.Sequence, this *CurrentState,
 
 	// Load the config.
 	// Which forces all sequences to load their config.
-	config.AskToLoadConfig(commandChannels, X, Y)
+	config.AskToLoadConfig(commandChannels, id)
+
+	// Restore any fixtures the programmer was holding when the preset was saved.
+	programmerValues, found, err := config.LoadProgrammerConfig(id)
+	if err != nil {
+		showError(this, err)
+	}
+	if found {
+		this.Programmer.Load(programmerValues, fixturesConfig)
+	}
+
+	// Restore the submaster levels, presets saved before there were submasters leave them as they are.
+	submasterValues, found, err := config.LoadSubmasterConfig(id)
+	if err != nil {
+		showError(this, err)
+	}
+	if found {
+		fixture.LoadSubmasters(submasterValues)
+	}
 
 	// Turn the selected preset light flashing it's current color and yellow.
 	if this.LastPreset != nil {
 		last := this.PresetsStore[*this.LastPreset]
-		this.PresetsStore[*this.LastPreset] = presets.Preset{State: last.State, Selected: false, Label: last.Label, ButtonColor: last.ButtonColor}
+		last.Selected = false
+		this.PresetsStore[*this.LastPreset] = last
 	}
-	current := this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y)]
-	this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y)] = presets.Preset{State: current.State, Selected: true, Label: current.Label, ButtonColor: current.ButtonColor}
-	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore)
+	current := this.PresetsStore[id]
+	current.Selected = true
+	this.PresetsStore[id] = current
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 
 	// Preserve this.Blackout.
 	if this.Blackout {
@@ -62,8 +95,7 @@ func loadConfig(sequences []*common.Sequence, this *CurrentState,
 	common.LightLamp(common.FLOOD_BUTTON, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
 
 	// Remember we selected this preset
-	last := fmt.Sprint(X) + "," + fmt.Sprint(Y)
-	this.LastPreset = &last
+	this.LastPreset = &id
 
 	// Get an upto date copy of all of the sequences.
 	for sequenceNumber, sequence := range sequences {
@@ -111,7 +143,6 @@ func loadConfig(sequences []*common.Sequence, this *CurrentState,
 		if sequence.Type == "rgb" {
 			this.Functions[sequenceNumber][common.Function2_Auto_Color].State = sequences[sequenceNumber].AutoColor
 			this.Functions[sequenceNumber][common.Function3_Auto_Pattern].State = sequences[sequenceNumber].AutoPattern
-			this.Functions[sequenceNumber][common.Function4_Bounce].State = sequences[sequenceNumber].Bounce
 			this.Functions[sequenceNumber][common.Function6_Static_Gobo].State = sequences[sequenceNumber].Static
 			this.Functions[sequenceNumber][common.Function7_Invert_Chase].State = sequences[sequenceNumber].RGBInvert
 			this.Functions[sequenceNumber][common.Function8_Music_Trigger].State = sequences[sequenceNumber].MusicTrigger
@@ -119,11 +150,20 @@ func loadConfig(sequences []*common.Sequence, this *CurrentState,
 		if sequence.Type == "scanner" {
 			this.Functions[sequenceNumber][common.Function2_Auto_Color].State = sequences[sequenceNumber].AutoColor
 			this.Functions[sequenceNumber][common.Function3_Auto_Pattern].State = sequences[sequenceNumber].AutoPattern
-			this.Functions[sequenceNumber][common.Function4_Bounce].State = sequences[sequenceNumber].Bounce
 			this.Functions[sequenceNumber][common.Function7_Invert_Chase].State = sequences[sequenceNumber].ScannerChaser
 			this.Functions[sequenceNumber][common.Function8_Music_Trigger].State = sequences[sequenceNumber].MusicTrigger
 		}
 
+		// Restore the step order and swing, presets saved before there were step orders only know about bounce.
+		this.StepOrder[sequenceNumber] = sequences[sequenceNumber].StepOrder
+		if sequences[sequenceNumber].Bounce && this.StepOrder[sequenceNumber] == common.STEP_ORDER_FORWARD {
+			this.StepOrder[sequenceNumber] = common.STEP_ORDER_BOUNCE
+		}
+		this.Swing[sequenceNumber] = sequences[sequenceNumber].Swing
+		if sequence.Type != "switch" {
+			showStepOrder(this, sequenceNumber)
+		}
+
 		// If we are loading a switch sequence, update our local copy of the switch settings.
 		if sequence.Type == "switch" {
 
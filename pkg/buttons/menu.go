@@ -10,7 +10,7 @@ func getNextMenuItem(currentMode int, chaser bool, staticColorMode bool) int {
 		fmt.Printf("getNextMenuItem current Mode %s chaser %t static %t\n", printMode(currentMode), chaser, staticColorMode)
 	}
 
-	menuOrder := []int{NORMAL, NORMAL_STATIC, FUNCTION, CHASER_DISPLAY, CHASER_DISPLAY_STATIC, CHASER_FUNCTION, STATUS}
+	menuOrder := []int{NORMAL, NORMAL_STATIC, FUNCTION, CHASER_DISPLAY, CHASER_DISPLAY_STATIC, CHASER_FUNCTION, STATUS, PROGRAMMER, CUES, PRESETS, SUBMASTERS}
 
 	if !chaser && !staticColorMode {
 		switch {
@@ -21,6 +21,18 @@ func getNextMenuItem(currentMode int, chaser bool, staticColorMode bool) int {
 			return menuOrder[STATUS]
 
 		case currentMode == STATUS:
+			return menuOrder[PROGRAMMER]
+
+		case currentMode == PROGRAMMER:
+			return menuOrder[CUES]
+
+		case currentMode == CUES:
+			return menuOrder[PRESETS]
+
+		case currentMode == PRESETS:
+			return menuOrder[SUBMASTERS]
+
+		case currentMode == SUBMASTERS:
 			return menuOrder[NORMAL]
 		}
 	}
@@ -37,6 +49,18 @@ func getNextMenuItem(currentMode int, chaser bool, staticColorMode bool) int {
 			return menuOrder[STATUS]
 
 		case currentMode == STATUS:
+			return menuOrder[PROGRAMMER]
+
+		case currentMode == PROGRAMMER:
+			return menuOrder[CUES]
+
+		case currentMode == CUES:
+			return menuOrder[PRESETS]
+
+		case currentMode == PRESETS:
+			return menuOrder[SUBMASTERS]
+
+		case currentMode == SUBMASTERS:
 			return menuOrder[NORMAL]
 		}
 	}
@@ -56,6 +80,18 @@ func getNextMenuItem(currentMode int, chaser bool, staticColorMode bool) int {
 			return menuOrder[STATUS]
 
 		case currentMode == STATUS:
+			return menuOrder[PROGRAMMER]
+
+		case currentMode == PROGRAMMER:
+			return menuOrder[CUES]
+
+		case currentMode == CUES:
+			return menuOrder[PRESETS]
+
+		case currentMode == PRESETS:
+			return menuOrder[SUBMASTERS]
+
+		case currentMode == SUBMASTERS:
 			return menuOrder[NORMAL]
 		}
 	}
@@ -78,6 +114,18 @@ func getNextMenuItem(currentMode int, chaser bool, staticColorMode bool) int {
 			return menuOrder[STATUS]
 
 		case currentMode == STATUS:
+			return menuOrder[PROGRAMMER]
+
+		case currentMode == PROGRAMMER:
+			return menuOrder[CUES]
+
+		case currentMode == CUES:
+			return menuOrder[PRESETS]
+
+		case currentMode == PRESETS:
+			return menuOrder[SUBMASTERS]
+
+		case currentMode == SUBMASTERS:
 			return menuOrder[NORMAL]
 		}
 	}
@@ -34,12 +34,48 @@ func Test_getNextMenuItem(t *testing.T) {
 			want: STATUS,
 		},
 		{
-			name: "get next item, send status want normal",
+			name: "get next item, send status want programmer",
 			args: args{
 				selectedMode:    STATUS,
 				chaser:          false,
 				editstaticcolor: false,
 			},
+			want: PROGRAMMER,
+		},
+		{
+			name: "get next item, send programmer want cues",
+			args: args{
+				selectedMode:    PROGRAMMER,
+				chaser:          false,
+				editstaticcolor: false,
+			},
+			want: CUES,
+		},
+		{
+			name: "get next item, send cues want presets",
+			args: args{
+				selectedMode:    CUES,
+				chaser:          false,
+				editstaticcolor: false,
+			},
+			want: PRESETS,
+		},
+		{
+			name: "get next item, send presets want submasters",
+			args: args{
+				selectedMode:    PRESETS,
+				chaser:          false,
+				editstaticcolor: false,
+			},
+			want: SUBMASTERS,
+		},
+		{
+			name: "get next item, send submasters want normal",
+			args: args{
+				selectedMode:    SUBMASTERS,
+				chaser:          false,
+				editstaticcolor: false,
+			},
 			want: NORMAL,
 		},
 
@@ -81,12 +117,48 @@ func Test_getNextMenuItem(t *testing.T) {
 			want: STATUS,
 		},
 		{
-			name: "get next item, send status want normal,",
+			name: "get next item, send status want programmer,",
 			args: args{
 				selectedMode:    STATUS,
 				chaser:          true,
 				editstaticcolor: false,
 			},
+			want: PROGRAMMER,
+		},
+		{
+			name: "get next item, send programmer want cues,",
+			args: args{
+				selectedMode:    PROGRAMMER,
+				chaser:          true,
+				editstaticcolor: false,
+			},
+			want: CUES,
+		},
+		{
+			name: "get next item, send cues want presets,",
+			args: args{
+				selectedMode:    CUES,
+				chaser:          true,
+				editstaticcolor: false,
+			},
+			want: PRESETS,
+		},
+		{
+			name: "get next item, send presets want submasters",
+			args: args{
+				selectedMode:    PRESETS,
+				chaser:          true,
+				editstaticcolor: false,
+			},
+			want: SUBMASTERS,
+		},
+		{
+			name: "get next item, send submasters want normal,",
+			args: args{
+				selectedMode:    SUBMASTERS,
+				chaser:          true,
+				editstaticcolor: false,
+			},
 			want: NORMAL,
 		},
 	}
@@ -0,0 +1,107 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These page functions let a launchpad row show sequences with more than
+// eight fixtures, eight at a time. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// changeFixturePage moves the selected sequence's row on to the next or previous page of eight fixtures.
+// Stops at the first and last pages.
+func changeFixturePage(this *CurrentState, sequences []*common.Sequence, forward bool, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	sequenceNumber := this.SelectedSequence
+	numberFixtures := sequences[sequenceNumber].NumberFixtures
+
+	page := nextFixturePage(this.FixturePage[sequenceNumber], numberFixtures, forward)
+	if page == this.FixturePage[sequenceNumber] {
+		return
+	}
+
+	if debug {
+		fmt.Printf("%d: Fixture page %d\n", sequenceNumber, page)
+	}
+
+	setFixturePage(sequenceNumber, page, this, commandChannels)
+
+	// The shutter chaser shares the scanner row so it follows the scanner's page.
+	if sequenceNumber == common.GlobalScannerSequenceNumber && common.GlobalChaserSequenceNumber != -1 {
+		setFixturePage(common.GlobalChaserSequenceNumber, page, this, commandChannels)
+	}
+
+	// Clear the row ready for the fixtures on the new page.
+	common.ClearSelectedRowOfButtons(sequenceNumber, eventsForLaunchpad, guiButtons)
+
+	// The fixture status isn't played by the sequence so show it here.
+	if this.SelectedMode[sequenceNumber] == STATUS {
+		showFixtureStatus(sequenceNumber, sequences[sequenceNumber].Number, numberFixtures, this, eventsForLaunchpad, guiButtons, commandChannels)
+	}
+
+	// Nor are the programmer's fixture selection buttons.
+	if this.SelectedMode[sequenceNumber] == PROGRAMMER {
+		showProgrammerFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+	}
+
+	// Or the submaster levels.
+	if this.SelectedMode[sequenceNumber] == SUBMASTERS {
+		showSubmasterFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+	}
+
+	// Update the status bar.
+	common.UpdateStatusBar(fixturePageLabel(page, numberFixtures), "page", false, guiButtons)
+}
+
+// nextFixturePage returns the page before or after the current one, staying within the pages available.
+func nextFixturePage(page int, numberFixtures int, forward bool) int {
+	numberPages := common.NumberOfFixturePages(numberFixtures)
+	if forward {
+		page++
+	} else {
+		page--
+	}
+	if page < 0 {
+		return 0
+	}
+	if page >= numberPages {
+		return numberPages - 1
+	}
+	return page
+}
+
+// fixturePageLabel describes the range of fixtures on a page, fixtures are numbered from 1.
+func fixturePageLabel(page int, numberFixtures int) string {
+	first := page*common.FIXTURES_PER_PAGE + 1
+	last := first + common.FIXTURES_PER_PAGE - 1
+	if last > numberFixtures {
+		last = numberFixtures
+	}
+	return fmt.Sprintf("Fixtures %d-%d", first, last)
+}
+
+func setFixturePage(sequenceNumber int, page int, this *CurrentState, commandChannels []chan common.Command) {
+	this.FixturePage[sequenceNumber] = page
+	cmd := common.Command{
+		Action: common.UpdateFixturePage,
+		Args: []common.Arg{
+			{Name: "FixturePage", Value: page},
+		},
+	}
+	common.SendCommandToSequence(sequenceNumber, cmd, commandChannels)
+}
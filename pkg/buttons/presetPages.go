@@ -0,0 +1,134 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These preset page functions page through the banks of presets and
+// switch to the full page, where every pad on the launchpad is a preset.
+// Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/presets"
+)
+
+// In presets mode the selected sequence's row pages through the presets.
+const PRESET_PAGE_DOWN_BUTTON = 0
+const PRESET_PAGE_UP_BUTTON = 1
+const PRESET_PAGE_BUTTON = 3
+const PRESET_FULL_PAGE_BUTTON = 7
+
+// showPresetPageButtons lights the page buttons on a sequence's row.
+func showPresetPageButtons(sequenceNumber int, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	row := common.SequenceRow(sequenceNumber)
+
+	for X := 0; X < 8; X++ {
+		common.LightLamp(common.Button{X: X, Y: row}, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+		common.LabelButton(X, row, "", guiButtons)
+	}
+
+	common.LightLamp(common.Button{X: PRESET_PAGE_DOWN_BUTTON, Y: row}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(PRESET_PAGE_DOWN_BUTTON, row, "PAGE-", guiButtons)
+
+	common.LightLamp(common.Button{X: PRESET_PAGE_UP_BUTTON, Y: row}, common.Cyan, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(PRESET_PAGE_UP_BUTTON, row, "PAGE+", guiButtons)
+
+	common.LightLamp(common.Button{X: PRESET_PAGE_BUTTON, Y: row}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(PRESET_PAGE_BUTTON, row, presets.PageLabel(this.PresetPage), guiButtons)
+
+	common.LightLamp(common.Button{X: PRESET_FULL_PAGE_BUTTON, Y: row}, common.Magenta, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	common.LabelButton(PRESET_FULL_PAGE_BUTTON, row, "FULL PAGE", guiButtons)
+}
+
+// presetPageButtonPressed handles the page buttons on the selected sequence's row.
+func presetPageButtonPressed(X int, sequences []*common.Sequence, this *CurrentState,
+	eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	switch X {
+	case PRESET_PAGE_DOWN_BUTTON:
+		if this.PresetPage > 0 {
+			this.PresetPage--
+		}
+	case PRESET_PAGE_UP_BUTTON:
+		if this.PresetPage < presets.NUMBER_PRESET_PAGES-1 {
+			this.PresetPage++
+		}
+	case PRESET_FULL_PAGE_BUTTON:
+		enterFullPresetPage(sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
+		return
+	default:
+		return
+	}
+
+	if debug {
+		fmt.Printf("Preset %s\n", presets.PageLabel(this.PresetPage))
+	}
+
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+	showPresetPageButtons(this.SelectedSequence, this, eventsForLaunchpad, guiButtons)
+	common.UpdateStatusBar(presets.PageLabel(this.PresetPage), "presetpage", false, guiButtons)
+}
+
+// enterFullPresetPage hides the sequences and uses every pad on the launchpad for presets.
+func enterFullPresetPage(sequences []*common.Sequence, this *CurrentState,
+	eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	if debug {
+		fmt.Printf("Enter Full Preset Page\n")
+	}
+
+	// Remember the page we came from.
+	this.LastPresetPage = this.PresetPage
+	this.PresetPage = presets.FULL_PAGE
+
+	// Every sequence goes back to normal mode when we leave the full page.
+	clearAllModes(sequences, this)
+	common.HideAllSequences(commandChannels)
+
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+	common.UpdateStatusBar(presets.PageLabel(this.PresetPage), "presetpage", false, guiButtons)
+}
+
+// leaveFullPresetPage goes back to the page of presets we came from and shows the sequences again.
+func leaveFullPresetPage(sequences []*common.Sequence, this *CurrentState,
+	eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	if debug {
+		fmt.Printf("Leave Full Preset Page\n")
+	}
+
+	this.PresetPage = this.LastPresetPage
+
+	// Clear the presets from the sequence rows, stopped sequences don't redraw themselves.
+	for Y := 0; Y < presets.FIRST_PRESET_ROW; Y++ {
+		for X := 0; X < 8; X++ {
+			common.LightLamp(common.Button{X: X, Y: Y}, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(X, Y, "", guiButtons)
+		}
+	}
+
+	// Show the sequences again, the shutter chaser stays hidden until it's asked for.
+	for sequenceNumber, sequence := range sequences {
+		if sequence.Label != "chaser" {
+			common.RevealSequence(sequenceNumber, commandChannels)
+		}
+	}
+
+	presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
+	displayMode(this.SelectedSequence, this.SelectedMode[this.SelectedSequence], this, sequences, eventsForLaunchpad, guiButtons, commandChannels)
+	common.UpdateStatusBar(presets.PageLabel(this.PresetPage), "presetpage", false, guiButtons)
+}
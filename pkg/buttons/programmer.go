@@ -0,0 +1,141 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These programmer functions let the launchpad select fixtures and set
+// their channels directly. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+// Steps used by the bottom row buttons when they act as the programmer's fader.
+const PROGRAMMER_FINE_STEP = 1
+const PROGRAMMER_COARSE_STEP = 16
+
+// showProgrammerFixtureButtons uses the fixture selection buttons to show which fixtures on a sequence's row are
+// in the programmer. Selected fixtures flash.
+func showProgrammerFixtureButtons(sequenceNumber int, sequences []*common.Sequence, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	page := this.FixturePage[sequenceNumber]
+
+	target := common.Sequence{Master: common.MAX_DMX_BRIGHTNESS}
+	for column := 0; column < common.FIXTURES_PER_PAGE; column++ {
+		fixtureNumber := page*common.FIXTURES_PER_PAGE + column
+		button := common.StaticColorButton{}
+		if fixtureNumber < sequences[sequenceNumber].NumberFixtures {
+			button.Number = fixtureNumber + 1
+			button.Label = fmt.Sprintf("Fixture %d", fixtureNumber+1)
+			button.Color = common.Color{R: 255, G: 0, B: 0}
+			button.Flash = this.Programmer.IsSelected(sequenceNumber, fixtureNumber)
+		}
+		target.ScannersAvailable = append(target.ScannersAvailable, button)
+	}
+
+	// We don't want the last fixture selected for a scanner color or gobo to flash as well.
+	selectedFixture := this.SelectedFixture
+	this.SelectedFixture = -1
+	ShowSelectFixtureButtons(target, sequenceNumber, this, eventsForLaunchpad, "Programmer", guiButtons)
+	this.SelectedFixture = selectedFixture
+}
+
+// programmerFixturePressed adds or removes a fixture from the programmer.
+func programmerFixturePressed(X int, sequences []*common.Sequence, this *CurrentState, fixturesConfig *fixture.Fixtures, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	sequenceNumber := this.SelectedSequence
+	fixtureNumber := X + this.FixturePage[sequenceNumber]*common.FIXTURES_PER_PAGE
+	if fixtureNumber >= sequences[sequenceNumber].NumberFixtures {
+		return
+	}
+
+	selected := this.Programmer.ToggleFixture(sequenceNumber, fixtureNumber)
+	if debug {
+		fmt.Printf("Programmer sequence %d fixture %d selected %t\n", sequenceNumber, fixtureNumber, selected)
+	}
+
+	showProgrammerFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+	showProgrammerStatus(this, fixturesConfig, guiButtons)
+}
+
+// programmerFaderPressed lets the bottom row of buttons act as a fader for the selected fixtures.
+// The first pair of buttons choose the channel, the next two pairs step the value down and up
+// finely and coarsely, and the last pair set the channel to off and full.
+func programmerFaderPressed(X int, this *CurrentState, fixturesConfig *fixture.Fixtures, guiButtons chan common.ALight) {
+
+	channelNames := this.Programmer.ChannelNames(fixturesConfig)
+	if len(channelNames) == 0 {
+		common.UpdateStatusBar("Programmer: select a fixture", "programmer", false, guiButtons)
+		return
+	}
+	if this.ProgrammerChannel >= len(channelNames) {
+		this.ProgrammerChannel = 0
+	}
+
+	channelName := channelNames[this.ProgrammerChannel]
+	value, _ := this.Programmer.GetChannel(channelName)
+
+	switch X {
+	case 0:
+		this.ProgrammerChannel = (this.ProgrammerChannel + len(channelNames) - 1) % len(channelNames)
+	case 1:
+		this.ProgrammerChannel = (this.ProgrammerChannel + 1) % len(channelNames)
+	case 2:
+		this.Programmer.SetChannel(channelName, stepProgrammerValue(value, -PROGRAMMER_FINE_STEP), fixturesConfig)
+	case 3:
+		this.Programmer.SetChannel(channelName, stepProgrammerValue(value, PROGRAMMER_FINE_STEP), fixturesConfig)
+	case 4:
+		this.Programmer.SetChannel(channelName, stepProgrammerValue(value, -PROGRAMMER_COARSE_STEP), fixturesConfig)
+	case 5:
+		this.Programmer.SetChannel(channelName, stepProgrammerValue(value, PROGRAMMER_COARSE_STEP), fixturesConfig)
+	case 6:
+		this.Programmer.SetChannel(channelName, 0, fixturesConfig)
+	case 7:
+		this.Programmer.SetChannel(channelName, 255, fixturesConfig)
+	}
+
+	showProgrammerStatus(this, fixturesConfig, guiButtons)
+}
+
+// stepProgrammerValue moves a channel value up or down, staying within 0-255.
+func stepProgrammerValue(value byte, step int) byte {
+	newValue := int(value) + step
+	if newValue < 0 {
+		return 0
+	}
+	if newValue > 255 {
+		return 255
+	}
+	return byte(newValue)
+}
+
+// showProgrammerStatus shows the channel the bottom row is controlling in the status bar.
+func showProgrammerStatus(this *CurrentState, fixturesConfig *fixture.Fixtures, guiButtons chan common.ALight) {
+
+	channelNames := this.Programmer.ChannelNames(fixturesConfig)
+	if len(channelNames) == 0 {
+		common.UpdateStatusBar("Programmer: select a fixture", "programmer", false, guiButtons)
+		return
+	}
+	if this.ProgrammerChannel >= len(channelNames) {
+		this.ProgrammerChannel = 0
+	}
+
+	channelName := channelNames[this.ProgrammerChannel]
+	value, _ := this.Programmer.GetChannel(channelName)
+	common.UpdateStatusBar(fmt.Sprintf("Programmer: %s %d", channelName, value), "programmer", false, guiButtons)
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These page functions let the launchpad show more sequences than it has
+// rows for, a page at a time. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/presets"
+)
+
+// Holding a sequence select button for this long shows the next page of sequences.
+const SEQUENCE_PAGE_LONG_PRESS = 1 * time.Second
+
+// NextSequencePage shows the next page of sequences on the launchpad, going round to the first page after the last.
+// Used by the launchpad and the GUI toolbar.
+func NextSequencePage(sequences []*common.Sequence, this *CurrentState,
+	eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	// The full preset page is using the sequence rows.
+	if common.NumberOfSequencePages() < 2 || this.PresetPage == presets.FULL_PAGE {
+		return
+	}
+
+	common.SetSequencePage(common.SequencePage() + 1)
+
+	if debug {
+		fmt.Printf("Sequence page %d\n", common.SequencePage())
+	}
+
+	// Every sequence goes back to normal mode on the new page.
+	clearAllModes(sequences, this)
+
+	// Clear the rows ready for the sequences on the new page, stopped sequences don't redraw themselves.
+	for Y := 0; Y < common.SEQUENCE_PAGE_ROWS; Y++ {
+		for X := 0; X < 8; X++ {
+			common.LightLamp(common.Button{X: X, Y: Y}, common.Black, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(X, Y, "", guiButtons)
+		}
+	}
+
+	// Show the sequences on the new page, the shutter chaser stays hidden until it's asked for.
+	for Y := 0; Y < common.SEQUENCE_PAGE_ROWS; Y++ {
+		sequenceNumber := common.RowSequence(Y)
+		if sequenceNumber >= 0 {
+			common.RevealSequence(sequenceNumber, commandChannels)
+		}
+	}
+	if common.GlobalChaserSequenceNumber != -1 {
+		common.HideSequence(common.GlobalChaserSequenceNumber, commandChannels)
+	}
+
+	// Select the first sequence on the page.
+	selectSequence(firstPageSequence(), sequences, this, eventsForLaunchpad, guiButtons, commandChannels)
+
+	common.UpdateStatusBar(SequencePageLabel(), "sequencepage", false, guiButtons)
+}
+
+// selectSequence makes a sequence the selected one, the same as pressing its select button.
+func selectSequence(sequenceNumber int, sequences []*common.Sequence, this *CurrentState,
+	eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight, commandChannels []chan common.Command) {
+
+	this.SelectedSequence = sequenceNumber
+	this.SelectedType = sequences[this.SelectedSequence].Type
+
+	if debug {
+		fmt.Printf("Select Sequence %d Type %s\n", this.SelectedSequence, this.SelectedType)
+	}
+
+	HandleSelect(sequences, this, eventsForLaunchpad, commandChannels, guiButtons)
+
+	this.ShowRGBColorPicker = false
+	this.EditGoboSelectionMode = false
+
+	// The scanner's static colors are edited on the shutter chaser when it's running.
+	if this.ScannerChaser[this.SelectedSequence] {
+		this.EditWhichStaticSequence = this.ChaserSequenceNumber
+	} else {
+		this.DisplayChaserShortCut = false
+		this.EditWhichStaticSequence = this.SelectedSequence
+	}
+}
+
+// firstPageSequence returns the sequence on the top row of the page being shown.
+func firstPageSequence() int {
+	sequenceNumber := common.RowSequence(0)
+	if sequenceNumber < 0 {
+		return 0
+	}
+	return sequenceNumber
+}
+
+// SequencePageLabel describes the page of sequences being shown, there's nothing to show if they fit on one page.
+func SequencePageLabel() string {
+	pages := common.NumberOfSequencePages()
+	if pages < 2 {
+		return ""
+	}
+	return fmt.Sprintf("Sequences %d/%d", common.SequencePage()+1, pages)
+}
@@ -294,7 +294,12 @@ func showFixtureStatus(selectedSequence int, sequenceNumber int, NumberFixtures
 
 	common.HideSequence(selectedSequence, commandChannels)
 
-	for fixtureNumber := 0; fixtureNumber < NumberFixtures; fixtureNumber++ {
+	// Only show the fixtures on the page being displayed.
+	page := this.FixturePage[sequenceNumber]
+	row := common.SequenceRow(sequenceNumber)
+	for fixtureNumber := page * common.FIXTURES_PER_PAGE; fixtureNumber < NumberFixtures && common.FixtureOnPage(fixtureNumber, page); fixtureNumber++ {
+
+		column := common.FixtureColumn(fixtureNumber)
 
 		if debug {
 			fmt.Printf("Sequence %d: Fixture %d Enabled %t Inverted %t\n", sequenceNumber, fixtureNumber, this.FixtureState[sequenceNumber][fixtureNumber].Enabled, this.FixtureState[sequenceNumber][fixtureNumber].RGBInverted)
@@ -302,32 +307,32 @@ func showFixtureStatus(selectedSequence int, sequenceNumber int, NumberFixtures
 
 		// Enabled but not inverted then On and green.
 		if this.FixtureState[sequenceNumber][fixtureNumber].Enabled && !this.FixtureState[sequenceNumber][fixtureNumber].RGBInverted {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, common.Green, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			common.LabelButton(fixtureNumber, sequenceNumber, "On", guiButtons)
+			common.LightLamp(common.Button{X: column, Y: row}, common.Green, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "On", guiButtons)
 		}
 
 		// Enabled and inverted then Invert and puple. Not reversed
 		if this.FixtureState[sequenceNumber][fixtureNumber].Enabled && this.FixtureState[sequenceNumber][fixtureNumber].RGBInverted && !this.FixtureState[sequenceNumber][fixtureNumber].ScannerPatternReversed {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, common.Purple, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			common.LabelButton(fixtureNumber, sequenceNumber, "Invert", guiButtons)
+			common.LightLamp(common.Button{X: column, Y: row}, common.Purple, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "Invert", guiButtons)
 		}
 
 		// Enabled not inverted but revesed then reverse and yellow.
 		if this.FixtureState[sequenceNumber][fixtureNumber].Enabled && !this.FixtureState[sequenceNumber][fixtureNumber].RGBInverted && this.FixtureState[sequenceNumber][fixtureNumber].ScannerPatternReversed {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, common.Yellow, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			common.LabelButton(fixtureNumber, sequenceNumber, "Reversed", guiButtons)
+			common.LightLamp(common.Button{X: column, Y: row}, common.Yellow, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "Reversed", guiButtons)
 		}
 
 		// Enabled  inverted and revesed then reverse and white.
 		if this.FixtureState[sequenceNumber][fixtureNumber].Enabled && this.FixtureState[sequenceNumber][fixtureNumber].RGBInverted && this.FixtureState[sequenceNumber][fixtureNumber].ScannerPatternReversed {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
-			common.LabelButton(fixtureNumber, sequenceNumber, "Invert & Reversed", guiButtons)
+			common.LightLamp(common.Button{X: column, Y: row}, common.White, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "Invert & Reversed", guiButtons)
 		}
 
 		// Not enabled and not inverted then off and blue.
 		if !this.FixtureState[sequenceNumber][fixtureNumber].Enabled {
-			common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, common.Red, this.MasterBrightness, eventsForLaunchpad, guiButtons)
-			common.LabelButton(fixtureNumber, sequenceNumber, "Off", guiButtons)
+			common.LightLamp(common.Button{X: column, Y: row}, common.Red, this.MasterBrightness, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "Off", guiButtons)
 		}
 
 	}
@@ -0,0 +1,140 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These functions select the order the steps of a chase are played in
+// and how much they swing, from the bounce function key. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// A long press on the bounce function key on the launchpad steps the swing rather than the step order.
+const STEP_ORDER_LONG_PRESS = 1 * time.Second
+
+// nextStepOrder is called when the bounce function key is pressed. On the launchpad nothing changes until
+// the key is released, so holding it down can step the swing instead, see stepOrderReleased.
+// The GUI only sends presses, so each press moves on to the next step order, forward, bounce, ping-pong,
+// random, shuffle and back to forward, and going round to forward steps the swing.
+func nextStepOrder(this *CurrentState, sequenceNumber int, commandChannels []chan common.Command) {
+
+	// Remember when we pressed, the launchpad acts when the key is released.
+	if !this.GUI {
+		here := time.Now()
+		this.StepOrderTimer = &here
+		return
+	}
+
+	stepOrder := (this.StepOrder[sequenceNumber] + 1) % common.NUMBER_STEP_ORDERS
+	if stepOrder == common.STEP_ORDER_FORWARD {
+		stepSwing(this, sequenceNumber, commandChannels)
+	}
+	setStepOrder(this, sequenceNumber, stepOrder, commandChannels)
+}
+
+// stepOrderReleased is called when the bounce function key is released on the launchpad.
+// A short press moves on to the next step order, a long press steps the swing.
+func stepOrderReleased(this *CurrentState, sequenceNumber int, commandChannels []chan common.Command) {
+
+	longPress := this.StepOrderTimer != nil && time.Since(*this.StepOrderTimer) > STEP_ORDER_LONG_PRESS
+	this.StepOrderTimer = nil
+
+	if longPress {
+		stepSwing(this, sequenceNumber, commandChannels)
+		return
+	}
+
+	setStepOrder(this, sequenceNumber, (this.StepOrder[sequenceNumber]+1)%common.NUMBER_STEP_ORDERS, commandChannels)
+}
+
+// stepSwing steps the swing up, wrapping round to no swing.
+func stepSwing(this *CurrentState, sequenceNumber int, commandChannels []chan common.Command) {
+	swing := this.Swing[sequenceNumber] + common.SWING_STEP
+	if swing > common.MAX_SWING {
+		swing = 0
+	}
+	setSwing(this, sequenceNumber, swing, commandChannels)
+}
+
+// setStepOrder sends the step order to the sequence and shows it on the bounce function key.
+func setStepOrder(this *CurrentState, sequenceNumber int, stepOrder int, commandChannels []chan common.Command) {
+
+	if debug {
+		fmt.Printf("Seq%d: Step Order %d\n", sequenceNumber, stepOrder)
+	}
+
+	this.StepOrder[sequenceNumber] = stepOrder
+
+	cmd := common.Command{
+		Action: common.UpdateStepOrder,
+		Args: []common.Arg{
+			{Name: "StepOrder", Value: stepOrder},
+		},
+	}
+	common.SendCommandToSequence(sequenceNumber, cmd, commandChannels)
+
+	showStepOrder(this, sequenceNumber)
+}
+
+// setSwing sends the swing to the sequence and shows it on the bounce function key.
+func setSwing(this *CurrentState, sequenceNumber int, swing int, commandChannels []chan common.Command) {
+
+	if debug {
+		fmt.Printf("Seq%d: Swing %d\n", sequenceNumber, swing)
+	}
+
+	this.Swing[sequenceNumber] = swing
+
+	cmd := common.Command{
+		Action: common.UpdateSwing,
+		Args: []common.Arg{
+			{Name: "Swing", Value: swing},
+		},
+	}
+	common.SendCommandToSequence(sequenceNumber, cmd, commandChannels)
+
+	showStepOrder(this, sequenceNumber)
+}
+
+// showStepOrder updates the bounce function key, it's lit when the steps aren't played forward
+// or they swing, and its label shows the step order and the swing.
+func showStepOrder(this *CurrentState, sequenceNumber int) {
+
+	if len(this.Functions[sequenceNumber]) <= common.Function4_Bounce {
+		return
+	}
+
+	stepOrder := this.StepOrder[sequenceNumber]
+	if stepOrder < 0 || stepOrder >= common.NUMBER_STEP_ORDERS {
+		stepOrder = common.STEP_ORDER_FORWARD
+	}
+	swing := this.Swing[sequenceNumber]
+
+	function := this.Functions[sequenceNumber][common.Function4_Bounce]
+	function.State = stepOrder != common.STEP_ORDER_FORWARD || swing > 0
+
+	// Keep the sequence type on the first line of the label.
+	label := strings.Split(function.Label, "\n")[0] + "\n" + common.StepOrderLabels[stepOrder]
+	if swing > 0 {
+		label = label + fmt.Sprintf("\nSwing %d%%", swing)
+	}
+	function.Label = label
+
+	this.Functions[sequenceNumber][common.Function4_Bounce] = function
+}
@@ -0,0 +1,91 @@
+package buttons
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+func Test_stepOrderKey(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		gui           bool
+		stepOrder     int
+		swing         int
+		held          time.Duration // How long the launchpad key is held down.
+		wantPressed   int           // Step order straight after the press.
+		wantStepOrder int
+		wantSwing     int
+	}{
+		{
+			name:          "launchpad short press moves on when released",
+			stepOrder:     common.STEP_ORDER_FORWARD,
+			wantPressed:   common.STEP_ORDER_FORWARD,
+			wantStepOrder: common.STEP_ORDER_BOUNCE,
+		},
+		{
+			name:          "launchpad long press steps the swing",
+			stepOrder:     common.STEP_ORDER_RANDOM,
+			held:          2 * STEP_ORDER_LONG_PRESS,
+			wantPressed:   common.STEP_ORDER_RANDOM,
+			wantStepOrder: common.STEP_ORDER_RANDOM,
+			wantSwing:     common.SWING_STEP,
+		},
+		{
+			name:          "launchpad long press wraps round to no swing",
+			stepOrder:     common.STEP_ORDER_FORWARD,
+			swing:         common.MAX_SWING,
+			held:          2 * STEP_ORDER_LONG_PRESS,
+			wantPressed:   common.STEP_ORDER_FORWARD,
+			wantStepOrder: common.STEP_ORDER_FORWARD,
+			wantSwing:     0,
+		},
+		{
+			name:          "gui press moves on",
+			gui:           true,
+			stepOrder:     common.STEP_ORDER_BOUNCE,
+			wantPressed:   common.STEP_ORDER_PING_PONG,
+			wantStepOrder: common.STEP_ORDER_PING_PONG,
+		},
+		{
+			name:          "gui press going round to forward steps the swing",
+			gui:           true,
+			stepOrder:     common.NUMBER_STEP_ORDERS - 1,
+			swing:         common.SWING_STEP,
+			wantPressed:   common.STEP_ORDER_FORWARD,
+			wantStepOrder: common.STEP_ORDER_FORWARD,
+			wantSwing:     2 * common.SWING_STEP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			this := &CurrentState{
+				GUI:       tt.gui,
+				StepOrder: map[int]int{0: tt.stepOrder},
+				Swing:     map[int]int{0: tt.swing},
+			}
+			commandChannels := []chan common.Command{make(chan common.Command, 10)}
+
+			nextStepOrder(this, 0, commandChannels)
+			if this.StepOrder[0] != tt.wantPressed {
+				t.Errorf("nextStepOrder() step order = %v, want %v", this.StepOrder[0], tt.wantPressed)
+			}
+
+			// The GUI doesn't send releases.
+			if !tt.gui {
+				pressed := time.Now().Add(-tt.held)
+				this.StepOrderTimer = &pressed
+				stepOrderReleased(this, 0, commandChannels)
+			}
+
+			if this.StepOrder[0] != tt.wantStepOrder {
+				t.Errorf("step order = %v, want %v", this.StepOrder[0], tt.wantStepOrder)
+			}
+			if this.Swing[0] != tt.wantSwing {
+				t.Errorf("swing = %v, want %v", this.Swing[0], tt.wantSwing)
+			}
+		})
+	}
+}
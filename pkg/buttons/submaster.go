@@ -0,0 +1,175 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These submaster functions let the launchpad dim single fixtures and
+// whole groups of fixtures. Used by the buttons package.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buttons
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+// The bottom row sets a group's submaster until a fixture is chosen.
+const SUBMASTER_GROUP = -1
+
+// showSubmasterFixtureButtons lights the fixture buttons on a sequence's row as bright as their submasters.
+// The fixture the bottom row is setting flashes.
+func showSubmasterFixtureButtons(sequenceNumber int, sequences []*common.Sequence, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	page := this.FixturePage[sequenceNumber]
+	row := common.SequenceRow(sequenceNumber)
+
+	for column := 0; column < common.FIXTURES_PER_PAGE; column++ {
+		fixtureNumber := page*common.FIXTURES_PER_PAGE + column
+		button := common.Button{X: column, Y: row}
+		if fixtureNumber >= sequences[sequenceNumber].NumberFixtures {
+			common.LightLamp(button, common.Black, common.MIN_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+			common.LabelButton(column, row, "", guiButtons)
+			continue
+		}
+		if fixtureNumber == this.SubmasterFixture && sequenceNumber == this.SelectedSequence {
+			common.FlashLight(button, common.Yellow, common.White, eventsForLaunchpad, guiButtons)
+		} else {
+			level := fixture.FixtureSubmaster(sequenceNumber, fixtureNumber)
+			common.LightLamp(button, common.Yellow, level, eventsForLaunchpad, guiButtons)
+		}
+		common.LabelButton(column, row, fmt.Sprintf("Fixture %d", fixtureNumber+1), guiButtons)
+	}
+}
+
+// submasterFixturePressed chooses a fixture for the bottom row to set, pressing it again goes back to its group.
+func submasterFixturePressed(X int, sequences []*common.Sequence, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	sequenceNumber := this.SelectedSequence
+	fixtureNumber := X + this.FixturePage[sequenceNumber]*common.FIXTURES_PER_PAGE
+	if fixtureNumber >= sequences[sequenceNumber].NumberFixtures {
+		return
+	}
+
+	if this.SubmasterFixture == fixtureNumber {
+		this.SubmasterFixture = SUBMASTER_GROUP
+	} else {
+		this.SubmasterFixture = fixtureNumber
+	}
+
+	if debug {
+		fmt.Printf("Submaster sequence %d fixture %d\n", sequenceNumber, this.SubmasterFixture)
+	}
+
+	showSubmasterFixtureButtons(sequenceNumber, sequences, this, eventsForLaunchpad, guiButtons)
+	showSubmasterStatus(this, guiButtons)
+}
+
+// submasterFaderPressed lets the bottom row of buttons act as a fader for a submaster.
+// The first pair of buttons step through the groups, the next two pairs step the level down and up
+// finely and coarsely, and the last pair set the submaster to off and full.
+func submasterFaderPressed(X int, sequences []*common.Sequence, this *CurrentState, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
+
+	numberGroups := 0
+	if this.Groups != nil {
+		numberGroups = len(this.Groups.Groups)
+	}
+
+	// Stepping through the groups hands the bottom row back to the groups.
+	if X == 0 || X == 1 {
+		if numberGroups > 0 {
+			if X == 0 {
+				this.SubmasterGroup = (this.SubmasterGroup + numberGroups - 1) % numberGroups
+			} else {
+				this.SubmasterGroup = (this.SubmasterGroup + 1) % numberGroups
+			}
+		}
+		this.SubmasterFixture = SUBMASTER_GROUP
+		showSubmasterFixtureButtons(this.SelectedSequence, sequences, this, eventsForLaunchpad, guiButtons)
+		showSubmasterStatus(this, guiButtons)
+		return
+	}
+
+	level := getSubmaster(this)
+
+	switch X {
+	case 2:
+		level = int(stepProgrammerValue(byte(level), -PROGRAMMER_FINE_STEP))
+	case 3:
+		level = int(stepProgrammerValue(byte(level), PROGRAMMER_FINE_STEP))
+	case 4:
+		level = int(stepProgrammerValue(byte(level), -PROGRAMMER_COARSE_STEP))
+	case 5:
+		level = int(stepProgrammerValue(byte(level), PROGRAMMER_COARSE_STEP))
+	case 6:
+		level = 0
+	case 7:
+		level = fixture.SUBMASTER_FULL
+	}
+
+	if this.SubmasterFixture == SUBMASTER_GROUP {
+		groupNumber, ok := submasterGroupNumber(this)
+		if !ok {
+			showSubmasterStatus(this, guiButtons)
+			return
+		}
+		fixture.SetGroupSubmaster(groupNumber, level)
+	} else {
+		fixture.SetFixtureSubmaster(this.SelectedSequence, this.SubmasterFixture, level)
+		showSubmasterFixtureButtons(this.SelectedSequence, sequences, this, eventsForLaunchpad, guiButtons)
+	}
+
+	showSubmasterStatus(this, guiButtons)
+}
+
+// getSubmaster returns the level of the submaster the bottom row is setting.
+func getSubmaster(this *CurrentState) int {
+	if this.SubmasterFixture != SUBMASTER_GROUP {
+		return fixture.FixtureSubmaster(this.SelectedSequence, this.SubmasterFixture)
+	}
+	groupNumber, ok := submasterGroupNumber(this)
+	if !ok {
+		return fixture.SUBMASTER_FULL
+	}
+	return fixture.GroupSubmaster(groupNumber)
+}
+
+// submasterGroupNumber returns the number of the group the bottom row is setting.
+func submasterGroupNumber(this *CurrentState) (int, bool) {
+	if this.Groups == nil || this.SubmasterGroup >= len(this.Groups.Groups) {
+		return 0, false
+	}
+	groupNumber, err := strconv.Atoi(this.Groups.Groups[this.SubmasterGroup].Number)
+	if err != nil {
+		fmt.Printf("error: group %s has a bad number %s\n", this.Groups.Groups[this.SubmasterGroup].Name, this.Groups.Groups[this.SubmasterGroup].Number)
+		return 0, false
+	}
+	return groupNumber, true
+}
+
+// showSubmasterStatus shows the submaster the bottom row is setting in the status bar.
+func showSubmasterStatus(this *CurrentState, guiButtons chan common.ALight) {
+
+	level := getSubmaster(this) * 100 / fixture.SUBMASTER_FULL
+
+	if this.SubmasterFixture != SUBMASTER_GROUP {
+		common.UpdateStatusBar(fmt.Sprintf("Submaster: S%d.F%d %d%%", this.SelectedSequence+1, this.SubmasterFixture+1, level), "submaster", false, guiButtons)
+		return
+	}
+	if _, ok := submasterGroupNumber(this); !ok {
+		common.UpdateStatusBar("Submaster: no groups", "submaster", false, guiButtons)
+		return
+	}
+	common.UpdateStatusBar(fmt.Sprintf("Submaster: %s %d%%", this.Groups.Groups[this.SubmasterGroup].Name, level), "submaster", false, guiButtons)
+}
@@ -0,0 +1,344 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights master clock. Sequences subscribe to it so their
+// steps land on the beat together. Driven internally, by tap, audio or MIDI clock.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package clock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+const debug = false
+
+const PPQN = 24 // Ticks per beat, the same as MIDI clock.
+const DEFAULT_BPM = 120
+const MIN_BPM = 40
+const MAX_BPM = 240
+
+// Audio beats closer together than this are the same beat.
+const MIN_BEAT_INTERVAL = time.Minute / MAX_BPM
+
+// Taps further apart than this start counting the tempo again.
+const TAP_TIMEOUT = 2 * time.Second
+
+// The tempo is worked out from the last few taps.
+const MAX_TAPS = 4
+
+// MIDI real time messages.
+const MIDI_CLOCK = 0xF8
+const MIDI_START = 0xFA
+const MIDI_STOP = 0xFC
+
+// Where the clock gets its tempo from.
+const (
+	INTERNAL = "internal" // Runs at the BPM it's set to.
+	TAP      = "tap"      // Runs at the tempo tapped in, each tap is a downbeat.
+	AUDIO    = "audio"    // Follows the beats found by the sound trigger.
+	MIDI     = "midi"     // Follows MIDI clock, 24 ticks a beat.
+)
+
+var Sources = []string{INTERNAL, TAP, AUDIO, MIDI}
+
+// Ratio is how often a subscribed sequence steps, as a number of steps every few beats.
+type Ratio struct {
+	Label      string
+	Multiplier int
+	Divider    int
+}
+
+// Ratios a sequence can follow the clock at.
+var Ratios = []Ratio{
+	{Label: "4 beats", Multiplier: 1, Divider: 4},
+	{Label: "2 beats", Multiplier: 1, Divider: 2},
+	{Label: "1 beat", Multiplier: 1, Divider: 1},
+	{Label: "1/2 beat", Multiplier: 2, Divider: 1},
+	{Label: "1/3 beat", Multiplier: 3, Divider: 1},
+	{Label: "1/4 beat", Multiplier: 4, Divider: 1},
+	{Label: "1/8 beat", Multiplier: 8, Divider: 1},
+}
+
+type subscriber struct {
+	trigger  chan common.Command // Sent a command every time the sequence should step.
+	interval int                 // Ticks between steps.
+}
+
+var clockLock sync.Mutex
+var bpm = float64(DEFAULT_BPM)
+var source = INTERNAL
+var tick int // The next tick to play, every PPQN ticks is a beat.
+var subscribers = map[int]subscriber{}
+var taps []time.Time
+var lastBeat time.Time
+var midiTicks []time.Time
+var resync = make(chan bool, 1)
+
+// Start runs the clock's own tick generator, used by every source but MIDI.
+func Start() {
+	go func() {
+		next := time.Now()
+		for {
+			clockLock.Lock()
+			interval := tickInterval(bpm)
+			generate := source != MIDI
+			clockLock.Unlock()
+
+			// Don't try and catch up on ticks we've missed.
+			next = next.Add(interval)
+			if time.Until(next) < -interval {
+				next = time.Now().Add(interval)
+			}
+
+			select {
+			case <-time.After(time.Until(next)):
+			case <-resync:
+				// A downbeat has just been played, time the next tick from it.
+				next = time.Now()
+				continue
+			}
+
+			if generate {
+				clockLock.Lock()
+				step()
+				clockLock.Unlock()
+			}
+		}
+	}()
+}
+
+// Subscribe steps a sequence with the clock, multiplier steps every divider beats.
+// Every sequence's steps start on the same downbeat.
+func Subscribe(sequenceNumber int, trigger chan common.Command, multiplier int, divider int) {
+	if debug {
+		fmt.Printf("clock: sequence %d subscribed %d/%d\n", sequenceNumber, multiplier, divider)
+	}
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	// Throw away a step left over from before the sequence last unsubscribed,
+	// otherwise the sequence would take its first step straight away.
+	select {
+	case <-trigger:
+	default:
+	}
+
+	subscribers[sequenceNumber] = subscriber{trigger: trigger, interval: Interval(multiplier, divider)}
+}
+
+// Unsubscribe stops sending steps to a sequence, it goes back to its own speed.
+func Unsubscribe(sequenceNumber int) {
+	if debug {
+		fmt.Printf("clock: sequence %d unsubscribed\n", sequenceNumber)
+	}
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	delete(subscribers, sequenceNumber)
+}
+
+// Interval returns the number of ticks between steps for a sequence making multiplier steps every divider beats.
+func Interval(multiplier int, divider int) int {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	if divider < 1 {
+		divider = 1
+	}
+	interval := PPQN * divider / multiplier
+	if interval < 1 {
+		interval = 1
+	}
+	return interval
+}
+
+// StepDuration returns how long a sequence making multiplier steps every divider beats takes over a step at the current tempo.
+func StepDuration(multiplier int, divider int) time.Duration {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	return time.Duration(float64(time.Minute) * float64(Interval(multiplier, divider)) / (limitBPM(bpm) * PPQN))
+}
+
+// BPM returns the clock's tempo.
+func BPM() float64 {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	return bpm
+}
+
+// SetBPM sets the tempo used by the internal source.
+func SetBPM(newBPM float64) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	bpm = limitBPM(newBPM)
+}
+
+// Source returns where the clock gets its tempo from.
+func Source() string {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	return source
+}
+
+// SetSource sets where the clock gets its tempo from.
+func SetSource(newSource string) {
+	for _, valid := range Sources {
+		if newSource == valid {
+			clockLock.Lock()
+			source = newSource
+			taps = nil
+			midiTicks = nil
+			clockLock.Unlock()
+			return
+		}
+	}
+	fmt.Printf("error: clock: unknown source %s\n", newSource)
+}
+
+// Tap sets the tempo from the last few taps, the clock switches to the tap source
+// and the tap becomes the downbeat.
+func Tap(now time.Time) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	source = TAP
+
+	// Start again if it's been a while since the last tap.
+	if len(taps) > 0 && now.Sub(taps[len(taps)-1]) > TAP_TIMEOUT {
+		taps = nil
+	}
+	taps = append(taps, now)
+	if len(taps) > MAX_TAPS {
+		taps = taps[len(taps)-MAX_TAPS:]
+	}
+	if len(taps) > 1 {
+		bpm = bpmFromTimes(taps, 1)
+	}
+
+	downbeat()
+}
+
+// Beat is called by the sound trigger when it hears a beat. When following audio the
+// tempo is eased towards the gap between beats and the beat becomes the downbeat.
+func Beat(now time.Time) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	if source != AUDIO {
+		return
+	}
+
+	// The sound trigger fires a few times for each beat.
+	gap := now.Sub(lastBeat)
+	if gap < MIN_BEAT_INTERVAL {
+		return
+	}
+	lastBeat = now
+
+	// A gap longer than the slowest tempo is a break in the music, not a beat.
+	if gap <= time.Minute/MIN_BPM {
+		bpm = limitBPM(bpm*0.75 + bpmFromTimes([]time.Time{now.Add(-gap), now}, 1)*0.25)
+	}
+
+	downbeat()
+}
+
+// MidiClock follows a MIDI real time message, clock ticks step the clock and start goes back to the downbeat.
+func MidiClock(message byte, now time.Time) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	if source != MIDI {
+		return
+	}
+
+	switch message {
+	case MIDI_START:
+		tick = 0
+		midiTicks = nil
+
+	case MIDI_CLOCK:
+		// Work out the tempo over the last beat's worth of ticks.
+		midiTicks = append(midiTicks, now)
+		if len(midiTicks) > PPQN+1 {
+			midiTicks = midiTicks[len(midiTicks)-PPQN-1:]
+		}
+		if len(midiTicks) > 1 {
+			bpm = bpmFromTimes(midiTicks, PPQN)
+		}
+		step()
+	}
+}
+
+// step plays a tick, triggering every sequence due to step on it.
+// Must be called with the clock locked.
+func step() {
+	for sequenceNumber, subscriber := range subscribers {
+		if tick%subscriber.interval == 0 {
+			select {
+			case subscriber.trigger <- common.Command{}:
+			default:
+				// The sequence hasn't taken its last step yet.
+				if debug {
+					fmt.Printf("clock: sequence %d missed tick %d\n", sequenceNumber, tick)
+				}
+			}
+		}
+	}
+	tick++
+}
+
+// downbeat makes now a beat. If a beat has only just played the clock is running a little
+// fast, so it's left alone, otherwise it skips on to the next beat and plays it now.
+// Must be called with the clock locked.
+func downbeat() {
+	phase := tick % PPQN
+	if phase == 0 || phase > PPQN/4 {
+		tick += (PPQN - phase) % PPQN
+		step()
+	}
+
+	// Time the next tick from this beat.
+	select {
+	case resync <- true:
+	default:
+	}
+}
+
+// bpmFromTimes works out the tempo from evenly spaced times, ticksPerBeat apart.
+func bpmFromTimes(times []time.Time, ticksPerBeat int) float64 {
+	gap := times[len(times)-1].Sub(times[0]) / time.Duration(len(times)-1)
+	if gap <= 0 {
+		return bpm
+	}
+	return limitBPM(float64(time.Minute) / float64(gap*time.Duration(ticksPerBeat)))
+}
+
+// tickInterval returns the time between ticks at a tempo.
+func tickInterval(bpm float64) time.Duration {
+	return time.Duration(float64(time.Minute) / (limitBPM(bpm) * PPQN))
+}
+
+func limitBPM(bpm float64) float64 {
+	if bpm < MIN_BPM {
+		return MIN_BPM
+	}
+	if bpm > MAX_BPM {
+		return MAX_BPM
+	}
+	return bpm
+}
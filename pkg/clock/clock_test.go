@@ -0,0 +1,254 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights master clock test code.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// resetClock puts the clock back to how it starts.
+func resetClock(newSource string) {
+	bpm = DEFAULT_BPM
+	source = newSource
+	tick = 0
+	subscribers = map[int]subscriber{}
+	taps = nil
+	lastBeat = time.Time{}
+	midiTicks = nil
+}
+
+// countSteps drains a trigger channel, returning how many steps were sent.
+func countSteps(trigger chan common.Command) int {
+	steps := 0
+	for {
+		select {
+		case <-trigger:
+			steps++
+		default:
+			return steps
+		}
+	}
+}
+
+// playTicks plays ticks like the tick generator would between beats.
+func playTicks(ticks int) {
+	for thisTick := 0; thisTick < ticks; thisTick++ {
+		step()
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiplier int
+		divider    int
+		want       int
+	}{
+		{name: "every beat", multiplier: 1, divider: 1, want: 24},
+		{name: "every other beat", multiplier: 1, divider: 2, want: 48},
+		{name: "four steps a beat", multiplier: 4, divider: 1, want: 6},
+		{name: "three steps a beat", multiplier: 3, divider: 1, want: 8},
+		{name: "not set is every beat", multiplier: 0, divider: 0, want: 24},
+		{name: "faster than the ticks", multiplier: 48, divider: 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interval(tt.multiplier, tt.divider); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribersStepTogether(t *testing.T) {
+	resetClock(INTERNAL)
+
+	// Each trigger can hold a step, so check them every tick.
+	everyBeat := make(chan common.Command, 1)
+	twiceABeat := make(chan common.Command, 1)
+	everyBar := make(chan common.Command, 1)
+	Subscribe(0, everyBeat, 1, 1)
+	Subscribe(1, twiceABeat, 2, 1)
+	Subscribe(2, everyBar, 1, 4)
+
+	var beats, halfBeats, bars []int
+	for thisTick := 0; thisTick < 4*PPQN; thisTick++ {
+		step()
+		if countSteps(everyBeat) > 0 {
+			beats = append(beats, thisTick)
+		}
+		if countSteps(twiceABeat) > 0 {
+			halfBeats = append(halfBeats, thisTick)
+		}
+		if countSteps(everyBar) > 0 {
+			bars = append(bars, thisTick)
+		}
+	}
+
+	if len(beats) != 4 || beats[1] != PPQN {
+		t.Errorf("every beat stepped on ticks %v", beats)
+	}
+	if len(halfBeats) != 8 || halfBeats[1] != PPQN/2 {
+		t.Errorf("twice a beat stepped on ticks %v", halfBeats)
+	}
+	if len(bars) != 1 || bars[0] != 0 {
+		t.Errorf("every bar stepped on ticks %v", bars)
+	}
+
+	// Once unsubscribed no more steps are sent.
+	Unsubscribe(0)
+	for thisTick := 0; thisTick < PPQN; thisTick++ {
+		step()
+	}
+	if steps := countSteps(everyBeat); steps != 0 {
+		t.Errorf("unsubscribed sequence was sent %d steps", steps)
+	}
+}
+
+func TestSubscribeDropsStaleStep(t *testing.T) {
+	resetClock(INTERNAL)
+
+	// A step sent just before the sequence unsubscribed is still waiting in its trigger.
+	trigger := make(chan common.Command, 1)
+	Subscribe(0, trigger, 1, 1)
+	step()
+	Unsubscribe(0)
+
+	// Subscribing again throws it away, the first step waits for the clock.
+	tick = 1
+	Subscribe(0, trigger, 1, 1)
+	if steps := countSteps(trigger); steps != 0 {
+		t.Errorf("resubscribed sequence had %d steps waiting, want 0", steps)
+	}
+}
+
+func TestTap(t *testing.T) {
+	resetClock(INTERNAL)
+	trigger := make(chan common.Command, 1)
+	Subscribe(0, trigger, 1, 1)
+
+	start := time.Now()
+	tapTimes := []time.Duration{0, 500 * time.Millisecond, 1000 * time.Millisecond, 1500 * time.Millisecond}
+	for _, tapTime := range tapTimes {
+		Tap(start.Add(tapTime))
+		if countSteps(trigger) != 1 {
+			t.Errorf("tap at %v wasn't a downbeat", tapTime)
+		}
+		// The generator is running a little slow, so the tap is the beat.
+		playTicks(PPQN - 2)
+		countSteps(trigger)
+	}
+	if source != TAP {
+		t.Errorf("source = %s, want %s", source, TAP)
+	}
+	if bpm != 120 {
+		t.Errorf("bpm = %v, want 120", bpm)
+	}
+
+	// A tap after a long gap starts counting again, so the tempo isn't changed.
+	Tap(start.Add(10 * time.Second))
+	if bpm != 120 {
+		t.Errorf("bpm after a gap = %v, want 120", bpm)
+	}
+
+	// The generator is running a little fast and has already played the beat, so it isn't played twice.
+	playTicks(PPQN + 1)
+	countSteps(trigger)
+	Tap(start.Add(10500 * time.Millisecond))
+	if steps := countSteps(trigger); steps != 0 {
+		t.Errorf("tap just after the beat stepped %d times", steps)
+	}
+}
+
+func TestBeat(t *testing.T) {
+	resetClock(INTERNAL)
+	trigger := make(chan common.Command, 1)
+	Subscribe(0, trigger, 1, 1)
+
+	// Beats are ignored unless we're following audio.
+	start := time.Now()
+	Beat(start)
+	if steps := countSteps(trigger); steps != 0 {
+		t.Errorf("beat stepped when not following audio")
+	}
+
+	SetSource(AUDIO)
+	Beat(start)
+	if countSteps(trigger) != 1 {
+		t.Errorf("first beat wasn't a downbeat")
+	}
+
+	// The sound trigger firing again straight away is the same beat.
+	Beat(start.Add(10 * time.Millisecond))
+	if steps := countSteps(trigger); steps != 0 {
+		t.Errorf("the same beat stepped twice")
+	}
+
+	// Beats at 100 BPM ease the tempo down.
+	playTicks(PPQN - 1)
+	countSteps(trigger)
+	Beat(start.Add(600 * time.Millisecond))
+	if countSteps(trigger) != 1 {
+		t.Errorf("second beat wasn't a downbeat")
+	}
+	if bpm != 115 {
+		t.Errorf("bpm = %v, want 115", bpm)
+	}
+}
+
+func TestMidiClock(t *testing.T) {
+	resetClock(MIDI)
+	trigger := make(chan common.Command, 1)
+	Subscribe(0, trigger, 1, 1)
+
+	// A beat's worth of ticks at 100 BPM.
+	start := time.Now()
+	tickGap := time.Minute / (100 * PPQN)
+	MidiClock(MIDI_START, start)
+	steps := 0
+	for thisTick := 0; thisTick <= PPQN; thisTick++ {
+		MidiClock(MIDI_CLOCK, start.Add(time.Duration(thisTick)*tickGap))
+		steps += countSteps(trigger)
+	}
+	if steps != 2 {
+		t.Errorf("steps = %d, want 2", steps)
+	}
+	if bpm < 99.9 || bpm > 100.1 {
+		t.Errorf("bpm = %v, want 100", bpm)
+	}
+
+	// Start goes back to the downbeat.
+	MidiClock(MIDI_CLOCK, start.Add(time.Duration(PPQN+1)*tickGap))
+	MidiClock(MIDI_START, start)
+	MidiClock(MIDI_CLOCK, start)
+	if countSteps(trigger) != 1 {
+		t.Errorf("start didn't go back to the downbeat")
+	}
+}
+
+func TestStepDuration(t *testing.T) {
+	resetClock(INTERNAL)
+	if got := StepDuration(1, 1); got != 500*time.Millisecond {
+		t.Errorf("StepDuration() every beat = %v, want 500ms", got)
+	}
+	if got := StepDuration(4, 1); got != 125*time.Millisecond {
+		t.Errorf("StepDuration() four a beat = %v, want 125ms", got)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dhowlett99/dmxlights/pkg/clock"
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/dhowlett99/dmxlights/pkg/config"
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
@@ -29,18 +30,34 @@ import (
 const debug = false
 const beatDebug = false
 
-// listenCommandChannelAndWait listens on channel for instructions or timeout and go to next step of sequence.
+// ListenCommandChannelAndWait listens on channel for instructions or timeout and go to next step of sequence.
 func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duration, sequence common.Sequence, channels common.Channels, fixturesConfig *fixture.Fixtures) common.Sequence {
+	return listenCommandChannelAndWait(mySequenceNumber, time.After(currentSpeed), nil, sequence, channels, fixturesConfig)
+}
+
+// ListenCommandChannelAndWaitForClock listens on channel for instructions and goes to the next step of the sequence
+// when the master clock says so, rather than timing the step itself.
+func ListenCommandChannelAndWaitForClock(mySequenceNumber int, sequence common.Sequence, channels common.Channels, fixturesConfig *fixture.Fixtures) common.Sequence {
+	var clockTrigger chan common.Command
+	if mySequenceNumber < len(channels.ClockTriggers) {
+		clockTrigger = channels.ClockTriggers[mySequenceNumber]
+	}
+	return listenCommandChannelAndWait(mySequenceNumber, nil, clockTrigger, sequence, channels, fixturesConfig)
+}
+
+// listenCommandChannelAndWait waits for whichever comes first, a command, a music trigger, a clock step or the timeout,
+// then processes any command.
+func listenCommandChannelAndWait(mySequenceNumber int, timeout <-chan time.Time, clockTrigger chan common.Command, sequence common.Sequence, channels common.Channels, fixturesConfig *fixture.Fixtures) common.Sequence {
 
 	// Setup channels.
 	commandChannel := channels.CommmandChannels[mySequenceNumber]
-	replyChannel := channels.ReplyChannels[mySequenceNumber]
 	updateChannel := channels.UpdateChannels[mySequenceNumber]
 
 	// Create an empty command.
 	command := common.Command{}
 
-	// Wait for a trigger: sound, command or timeout.
+	// Wait for a trigger: sound, command, clock or timeout.
+	// A nil clock trigger or timeout is never ready.
 	select {
 	case command = <-channels.SoundTriggers[mySequenceNumber].Channel:
 		if sequence.MusicTrigger {
@@ -52,7 +69,10 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 	case command = <-commandChannel:
 		break
 
-	case <-time.After(currentSpeed):
+	case command = <-clockTrigger:
+		break
+
+	case <-timeout:
 		break
 	}
 
@@ -87,6 +107,9 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 		sequence.FloodPlayOnce = true
 		// Set Master brightness back to max.
 		sequence.Master = common.MAX_DMX_BRIGHTNESS
+		// Go back to our own speed.
+		sequence.ClockSync = false
+		syncToClock(mySequenceNumber, sequence, channels)
 
 		// Enable all fixtures
 		for fixture := 0; fixture < sequence.NumberFixtures; fixture++ {
@@ -134,6 +157,8 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 		sequence.AutoColor = false
 		sequence.AutoPattern = false
 		sequence.Bounce = false
+		sequence.StepOrder = common.STEP_ORDER_FORWARD
+		sequence.Swing = 0
 		sequence.ScannerChaser = false
 		sequence.RGBInvert = false
 		sequence.MusicTrigger = false
@@ -146,10 +171,10 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 			// read the fixtures config currently in memory.
 			sequence.Switches = LoadSwitchConfiguration(mySequenceNumber, fixturesConfig)
 
-			// Clear switch positions to their first positions.
+			// Clear switch positions to their power on positions.
 			for switchNumber := 0; switchNumber < len(sequence.Switches); switchNumber++ {
 				newSwitch := common.Switch{}
-				newSwitch.CurrentPosition = 0
+				newSwitch.CurrentPosition = sequence.Switches[switchNumber].DefaultPosition
 				newSwitch.Description = sequence.Switches[switchNumber].Description
 				newSwitch.Fixture = sequence.Switches[switchNumber].Fixture
 				newSwitch.Label = sequence.Switches[switchNumber].Label
@@ -157,6 +182,9 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 				newSwitch.Number = sequence.Switches[switchNumber].Number
 				newSwitch.States = sequence.Switches[switchNumber].States
 				newSwitch.UseFixture = sequence.Switches[switchNumber].UseFixture
+				newSwitch.Advance = sequence.Switches[switchNumber].Advance
+				newSwitch.Interlock = sequence.Switches[switchNumber].Interlock
+				newSwitch.DefaultPosition = sequence.Switches[switchNumber].DefaultPosition
 				sequence.Switches[switchNumber] = newSwitch
 			}
 			sequence.PlaySwitchOnce = true
@@ -417,6 +445,37 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 		sequence.Bounce = command.Args[STATE].Value.(bool)
 		return sequence
 
+	case common.UpdateStepOrder:
+		const STEP_ORDER = 0
+		if debug {
+			fmt.Printf("%d: Command Update Step Order to %d\n", mySequenceNumber, command.Args[STEP_ORDER].Value)
+		}
+		sequence.StepOrder = command.Args[STEP_ORDER].Value.(int)
+		// Bounce is still played by calculating the reversed positions.
+		sequence.Bounce = sequence.StepOrder == common.STEP_ORDER_BOUNCE
+		return sequence
+
+	case common.UpdateClock:
+		const CLOCK_SYNC = 0
+		const CLOCK_MULTIPLIER = 1
+		const CLOCK_DIVIDER = 2
+		if debug {
+			fmt.Printf("%d: Command Update Clock to %t %d/%d\n", mySequenceNumber, command.Args[CLOCK_SYNC].Value, command.Args[CLOCK_MULTIPLIER].Value, command.Args[CLOCK_DIVIDER].Value)
+		}
+		sequence.ClockSync = command.Args[CLOCK_SYNC].Value.(bool)
+		sequence.ClockMultiplier = command.Args[CLOCK_MULTIPLIER].Value.(int)
+		sequence.ClockDivider = command.Args[CLOCK_DIVIDER].Value.(int)
+		syncToClock(mySequenceNumber, sequence, channels)
+		return sequence
+
+	case common.UpdateSwing:
+		const SWING = 0
+		if debug {
+			fmt.Printf("%d: Command Update Swing to %d\n", mySequenceNumber, command.Args[SWING].Value)
+		}
+		sequence.Swing = command.Args[SWING].Value.(int)
+		return sequence
+
 	case common.UpdateStatic: // Update Static will force the sequence to play the static scene.
 		const STATIC = 0
 		if debug {
@@ -533,6 +592,39 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 		sequence.ScannerColor[command.Args[FIXTURE_NUMBER].Value.(int)] = command.Args[SELECTED_COLOR].Value.(int)
 		return sequence
 
+	case common.UpdateScannerColorByRGB:
+		const COLOR = 0 // Color
+		if debug {
+			fmt.Printf("%d: Command Update Scanner Color By RGB to %+v\n", mySequenceNumber, command.Args[COLOR].Value)
+		}
+		// Find the nearest color wheel slot for each of the scanners in this sequence.
+		for _, f := range fixturesConfig.Fixtures {
+			if f.Group == mySequenceNumber+1 && f.Type == "scanner" {
+				wheelColor, err := fixture.FindNearestWheelColor(&f, command.Args[COLOR].Value.(common.Color))
+				if err != nil {
+					if debug {
+						fmt.Printf("%d: %s\n", mySequenceNumber, err)
+					}
+					continue
+				}
+				sequence.ScannerColor[f.Number-1] = wheelColor
+			}
+		}
+		sequence.SaveColors = true
+		return sequence
+
+	case common.UpdateFixturePage:
+		const FIXTURE_PAGE = 0
+		if debug {
+			fmt.Printf("%d: Command Update Fixture Page to %d\n", mySequenceNumber, command.Args[FIXTURE_PAGE].Value)
+		}
+		sequence.FixturePage = command.Args[FIXTURE_PAGE].Value.(int)
+		// Redisplay the static colors on the new page.
+		if sequence.Static {
+			sequence.PlayStaticOnce = true
+		}
+		return sequence
+
 	case common.ClearSequenceColor:
 		if debug {
 			fmt.Printf("%d: Command Clear Sequence Color \n", mySequenceNumber)
@@ -575,10 +667,16 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 
 	// If we are being asked for our config we must reply with our current sequence.
 	case common.ReadConfig:
+		const SNAPSHOT = 0
 		if debug {
 			fmt.Printf("%d: Command Read Config\n", mySequenceNumber)
 		}
-		replyChannel <- sequence
+		request := command.Args[SNAPSHOT].Value.(common.SnapshotRequest)
+		request.Reply <- common.SnapshotReply{
+			ID:             request.ID,
+			SequenceNumber: mySequenceNumber,
+			Sequence:       sequence,
+		}
 		return sequence
 
 	// We are setting the Master brightness in this sequence.
@@ -621,12 +719,11 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 			fmt.Printf("%d: Command Update Switch %d to Position %d\n", mySequenceNumber, command.Args[SWITCH_NUMBER].Value, command.Args[SWITCH_POSITION].Value)
 		}
 
-		// Loop through all the switchies. and reset their current state back to 0.
 		switchNumber := command.Args[SWITCH_NUMBER].Value.(int)
 		switchPosition := command.Args[SWITCH_POSITION].Value.(int)
 
 		newSwitch := common.Switch{}
-		newSwitch.CurrentPosition = switchPosition
+		newSwitch.CurrentPosition = sequence.Switches[switchNumber].CurrentPosition
 		newSwitch.Description = sequence.Switches[switchNumber].Description
 		newSwitch.Fixture = sequence.Switches[switchNumber].Fixture
 		newSwitch.Label = sequence.Switches[switchNumber].Label
@@ -634,8 +731,20 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 		newSwitch.Number = sequence.Switches[switchNumber].Number
 		newSwitch.States = sequence.Switches[switchNumber].States
 		newSwitch.UseFixture = sequence.Switches[switchNumber].UseFixture
+		newSwitch.Advance = sequence.Switches[switchNumber].Advance
+		newSwitch.Interlock = sequence.Switches[switchNumber].Interlock
+		newSwitch.DefaultPosition = sequence.Switches[switchNumber].DefaultPosition
 		sequence.Switches[switchNumber] = newSwitch
-		sequence.CurrentSwitch = command.Args[SWITCH_NUMBER].Value.(int)
+
+		// Interlocks and dependencies can move other switches with this one.
+		moved, err := common.MoveSwitch(sequence.Switches, switchNumber, switchPosition)
+		if err != nil {
+			// Leave the switch where it is and show it there.
+			fmt.Printf("%d: error: %s\n", mySequenceNumber, err.Error())
+			moved = []int{switchNumber}
+		}
+		sequence.CurrentSwitch = moved[0]
+		sequence.SwitchesToPlay = moved[1:]
 		sequence.PlaySwitchOnce = true
 		sequence.PlaySingleSwitch = true
 		sequence.Run = false
@@ -788,17 +897,28 @@ func ListenCommandChannelAndWait(mySequenceNumber int, currentSpeed time.Duratio
 
 	// If we are being asked to load a config, use the new sequence.
 	case common.LoadConfig:
-		const X = 0
-		const Y = 1
+		const PRESET = 0
 		if debug {
 			fmt.Printf("%d: Command Load Config\n", mySequenceNumber)
 		}
-		x := command.Args[X].Value.(int)
-		y := command.Args[Y].Value.(int)
-		config := config.LoadConfig(fmt.Sprintf("config%d.%d.json", x, y))
+		id := command.Args[PRESET].Value.(string)
+		config, err := config.LoadConfig(id)
+		if err != nil {
+			fmt.Printf("%d: error: %s\n", mySequenceNumber, err.Error())
+			return sequence
+		}
 		for _, seq := range config {
 			if seq.Number == sequence.Number {
+				// Switches advance as set in the fixtures config, that isn't saved with presets.
+				for switchNumber, swiTch := range seq.Switches {
+					if current, ok := sequence.Switches[switchNumber]; ok {
+						swiTch.Advance = current.Advance
+						seq.Switches[switchNumber] = swiTch
+					}
+				}
 				sequence = seq
+				// Follow the master clock if the preset did.
+				syncToClock(mySequenceNumber, sequence, channels)
 				// Don't assume we're blacked out.
 				sequence.Blackout = false
 				sequence.StaticFadeUpOnce = true
@@ -916,6 +1036,21 @@ func LoadSwitchConfiguration(mySequenceNumber int, fixturesConfig *fixture.Fixtu
 			newSwitch.Description = fixture.Description
 			newSwitch.UseFixture = fixture.UseFixture
 
+			// A switch can step through its states on its own.
+			advance, err := fixture.Advance.SwitchAdvance(fixture.States)
+			if err != nil {
+				fmt.Printf("error: switch %s: %s, not advancing on its own\n", fixture.Name, err.Error())
+			}
+			newSwitch.Advance = advance
+
+			// Switches can be tied together and power on in any state.
+			newSwitch.Interlock = fixture.Interlock
+			newSwitch.DefaultPosition, err = fixture.DefaultSwitchPosition()
+			if err != nil {
+				fmt.Printf("error: switch %s: %s\n", fixture.Name, err.Error())
+			}
+			newSwitch.CurrentPosition = newSwitch.DefaultPosition
+
 			// A switch has a number of states.
 			newSwitch.States = make(map[int]common.State)
 			for stateNumber, state := range fixture.States {
@@ -932,10 +1067,15 @@ func LoadSwitchConfiguration(mySequenceNumber int, fixturesConfig *fixture.Fixtu
 					newSetting.Number = setting.Number
 					newSetting.Channel = setting.Channel
 					newSetting.FixtureValue = setting.Value
+					newSetting.Delay, newSetting.FadeIn, newSetting.FadeOut, err = setting.Times()
+					if err != nil {
+						fmt.Printf("error: switch %s state %s: %s\n", fixture.Name, state.Name, err.Error())
+					}
 					newState.Settings = append(newState.Settings, newSetting)
 				}
 				newState.ButtonColor = state.ButtonColor
 				newState.Flash = state.Flash
+				newState.Requires = state.SwitchRequirements()
 
 				// Copy values.
 				newState.Values = []common.Value{}
@@ -1059,3 +1199,12 @@ func GetNumberOfFixtures(sequenceNumber int, fixtures *fixture.Fixtures) int {
 	}
 	return numberFixtures
 }
+
+// syncToClock subscribes this sequence to the master clock or unsubscribes it, as the sequence is set.
+func syncToClock(mySequenceNumber int, sequence common.Sequence, channels common.Channels) {
+	if sequence.ClockSync && mySequenceNumber < len(channels.ClockTriggers) {
+		clock.Subscribe(mySequenceNumber, channels.ClockTriggers[mySequenceNumber], sequence.ClockMultiplier, sequence.ClockDivider)
+	} else {
+		clock.Unsubscribe(mySequenceNumber)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -59,12 +60,51 @@ const SCANNER_MID_POINT = 127
 const DEFAULT_RGB_FADE_STEPS = 10
 const DEFAULT_STROBE_SPEED = 255
 
+// Orders the steps of a chase can be played in, selected with the bounce function key.
+const (
+	STEP_ORDER_FORWARD   = iota // Steps play first to last.
+	STEP_ORDER_BOUNCE           // Steps play forward then reverse, the end steps play once.
+	STEP_ORDER_PING_PONG        // Steps play forward then reverse, the end steps are held for a second step.
+	STEP_ORDER_RANDOM           // Steps are picked at random, never the same step twice in a row.
+	STEP_ORDER_SHUFFLE          // Every step plays once a cycle, in an order shuffled at the start of each cycle.
+	NUMBER_STEP_ORDERS
+)
+
+// Labels for the step orders, shown on the bounce function key.
+var StepOrderLabels = []string{"Bounce", "Bounce", "Ping\nPong", "Random", "Shuffle"}
+
+// Swing is a percentage of the step time, moved from every other step onto the step before it.
+const SWING_STEP = 25
+const MAX_SWING = 75
+
 const IS_SCANNER = true
 const IS_RGB = false
 
 var DefaultSequenceColors = []Color{{R: 0, G: 255, B: 0}}
 var GlobalScannerSequenceNumber int
 
+// Sequence numbers of the shutter chaser and the switches, set from sequences.yaml.
+// -1 when the sequence isn't configured.
+var GlobalChaserSequenceNumber = -1
+var GlobalSwitchSequenceNumber = -1
+
+// The top rows of the launchpad show one sequence each, the rows below are used by the presets.
+// The switch sequence always has the last of these rows, the others show the rest of the
+// sequences a page at a time.
+const MAX_SEQUENCE_ROWS = 4
+const SEQUENCE_PAGE_ROWS = 3
+const SWITCH_ROW = 3
+
+// HIDDEN_ROW is the row of a sequence that isn't on the page being shown, its lamps and labels are dropped.
+const HIDDEN_ROW = -100
+
+// The launchpad shows eight fixtures across a row, sequences with more fixtures are shown a page at a time.
+const FIXTURES_PER_PAGE = 8
+
+// RGB patterns are eight fixtures wide, sequences with more fixtures either scale or tile the pattern.
+const PATTERN_FIT_SCALE = "scale"
+const PATTERN_FIT_TILE = "tile"
+
 var FLOOD_BUTTON = Button{X: 8, Y: 3}
 var SAVE_BUTTON = Button{X: 8, Y: 4}
 var RUNNING_BUTTON = Button{X: 8, Y: 5}
@@ -150,6 +190,9 @@ type Setting struct {
 	Channel      string
 	Value        int16
 	FixtureValue string
+	Delay        time.Duration
+	FadeIn       time.Duration
+	FadeOut      time.Duration
 }
 
 type State struct {
@@ -161,6 +204,13 @@ type State struct {
 	Actions     []Action
 	Settings    []Setting
 	Flash       bool
+	Requires    []Requirement // Other switches that must be in a given state before a switch can move to this state.
+}
+
+// Requirement is a state another switch must be in.
+type Requirement struct {
+	Switch string // The other switch's name.
+	State  int16  // The state's number.
 }
 
 type Action struct {
@@ -182,6 +232,50 @@ type Action struct {
 	GoboSpeed    string
 }
 
+// Ways a switch can advance through its states on its own.
+const SWITCH_ADVANCE_TIMER = "timer" // Each state in turn, all held for the same time.
+const SWITCH_ADVANCE_BEAT = "beat"   // Each state in turn, held for a number of beats on the switch's sound trigger.
+const SWITCH_ADVANCE_ORDER = "order" // The states in a given order, each held for its own time.
+
+// SwitchAdvance steps a switch through its states without its button being pressed.
+type SwitchAdvance struct {
+	Mode  string
+	Beats int           // Beat mode, how many beats each step is held for.
+	Steps []AdvanceStep // The positions stepped through in order.
+}
+
+type AdvanceStep struct {
+	Position int
+	Hold     time.Duration // Timer and order modes.
+}
+
+// Due returns true once a switch has been on a step long enough to move on to the next one.
+func (advance *SwitchAdvance) Due(step int, held time.Duration, beats int) bool {
+	if advance.Mode == SWITCH_ADVANCE_BEAT {
+		return beats >= advance.Beats
+	}
+	return held >= advance.Steps[step].Hold
+}
+
+// Next returns the step after this one, going back to the first step after the last.
+func (advance *SwitchAdvance) Next(step int) int {
+	return (step + 1) % len(advance.Steps)
+}
+
+// StepOf finds the step a switch is on from its position, a button press can move a switch
+// anywhere. A position that isn't one of the steps leaves the switch on the step it was on.
+func (advance *SwitchAdvance) StepOf(position int, step int) int {
+	if step < len(advance.Steps) && advance.Steps[step].Position == position {
+		return step
+	}
+	for stepNumber, advanceStep := range advance.Steps {
+		if advanceStep.Position == position {
+			return stepNumber
+		}
+	}
+	return step
+}
+
 type Switch struct {
 	ID                   int
 	Name                 string
@@ -196,6 +290,143 @@ type Switch struct {
 	MiniSequencerRunning bool
 	Blackout             bool
 	Master               int
+	Advance              *SwitchAdvance `json:"-"` // Set from the fixtures config, not saved with presets.
+	Interlock            string         // Switches in the same interlock group are never on together.
+	DefaultPosition      int            // The position the switch powers on in.
+}
+
+// A switch's first state is off.
+const SWITCH_OFF_POSITION = 0
+
+// SwitchMoveAllowed checks the switches a state needs are in the states it needs them in.
+func SwitchMoveAllowed(switches map[int]Switch, switchNumber int, position int) error {
+
+	swiTch, found := switches[switchNumber]
+	if !found {
+		return fmt.Errorf("switch %d not found", switchNumber+1)
+	}
+	state, found := swiTch.States[position]
+	if !found {
+		return fmt.Errorf("switch %s has no position %d", swiTch.Label, position)
+	}
+	return requirementsMet(switches, swiTch, state)
+}
+
+func requirementsMet(switches map[int]Switch, swiTch Switch, state State) error {
+
+	for _, requirement := range state.Requires {
+		other, found := findSwitchByName(switches, requirement.Switch)
+		if !found {
+			return fmt.Errorf("%s %s needs switch %s which isn't in this sequence", swiTch.Label, state.Label, requirement.Switch)
+		}
+		if other.States[other.CurrentPosition].Number != requirement.State {
+			label := fmt.Sprintf("state %d", requirement.State)
+			for _, otherState := range other.States {
+				if otherState.Number == requirement.State {
+					label = otherState.Label
+				}
+			}
+			return fmt.Errorf("%s %s needs %s set to %s", swiTch.Label, state.Label, other.Label, label)
+		}
+	}
+	return nil
+}
+
+func findSwitchByName(switches map[int]Switch, name string) (Switch, bool) {
+	for _, swiTch := range switches {
+		if swiTch.Name == name {
+			return swiTch, true
+		}
+	}
+	return Switch{}, false
+}
+
+// MoveSwitch moves a switch to a new position along with the switches tied to it. Moving a switch
+// on, out of its first position, turns off the other switches in its interlock group, then any
+// switch left in a state that needs a switch which has moved is turned off too.
+// Returns the switches moved, this switch first.
+func MoveSwitch(switches map[int]Switch, switchNumber int, position int) ([]int, error) {
+
+	err := SwitchMoveAllowed(switches, switchNumber, position)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work through the switches in order so they always move the same way.
+	switchNumbers := []int{}
+	for number := range switches {
+		switchNumbers = append(switchNumbers, number)
+	}
+	sort.Ints(switchNumbers)
+
+	moved := []int{switchNumber}
+	setSwitchPosition(switches, switchNumber, position)
+
+	// Radio buttons.
+	interlock := switches[switchNumber].Interlock
+	if interlock != "" && position != SWITCH_OFF_POSITION {
+		for _, number := range switchNumbers {
+			other := switches[number]
+			if number != switchNumber && other.Interlock == interlock && other.CurrentPosition != SWITCH_OFF_POSITION {
+				setSwitchPosition(switches, number, SWITCH_OFF_POSITION)
+				moved = append(moved, number)
+			}
+		}
+	}
+
+	// Turning a switch off can leave another without what it needs, so keep going until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for _, number := range switchNumbers {
+			other := switches[number]
+			if other.CurrentPosition == SWITCH_OFF_POSITION {
+				continue
+			}
+			if requirementsMet(switches, other, other.States[other.CurrentPosition]) != nil {
+				setSwitchPosition(switches, number, SWITCH_OFF_POSITION)
+				if !containsInt(moved, number) {
+					moved = append(moved, number)
+				}
+				changed = true
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+func setSwitchPosition(switches map[int]Switch, switchNumber int, position int) {
+	swiTch := switches[switchNumber]
+	swiTch.CurrentPosition = position
+	switches[switchNumber] = swiTch
+}
+
+func containsInt(numbers []int, number int) bool {
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgrammerValue is one channel value held by the programmer, saved with a preset.
+// Sequence and fixture are numbered from 0.
+type ProgrammerValue struct {
+	Sequence int    `json:"sequence"`
+	Fixture  int    `json:"fixture"`
+	Channel  string `json:"channel"`
+	Value    byte   `json:"value"`
+}
+
+// SubmasterValue is the level of a fixture or group submaster, saved with a preset.
+// A group submaster has the group's number from groups.yaml, otherwise it's the submaster
+// of a fixture given by its sequence and fixture number, both numbered from 0.
+type SubmasterValue struct {
+	Group    int `json:"group,omitempty"`
+	Sequence int `json:"sequence"`
+	Fixture  int `json:"fixture"`
+	Level    int `json:"level"`
 }
 
 type StaticColorButton struct {
@@ -248,6 +479,9 @@ const (
 	UpdateStatic
 	UpdateFlashAllStaticColorButtons
 	UpdateBounce
+	UpdateStepOrder
+	UpdateSwing
+	UpdateClock
 	UpdateAllStaticColor
 	UpdateStaticColor
 	UpdateASingeSequenceColor
@@ -300,6 +534,8 @@ const (
 	UpdateMusicTrigger
 	UpdateScannerHasShutterChase
 	UpdateFixturesConfig
+	UpdateScannerColorByRGB
+	UpdateFixturePage
 )
 
 // A full step cycle is 39 ticks ie 39 values.
@@ -357,8 +593,15 @@ type Sequence struct {
 	Number                      int                         // Sequence number.
 	Run                         bool                        // True if this sequence is running.
 	Bounce                      bool                        // True if this sequence is bouncing.
+	StepOrder                   int                         // Order the steps are played in, forward, bounce, ping-pong, random or shuffle.
+	Swing                       int                         // Swing in percent, lengthens every other step.
+	ClockSync                   bool                        // True if this sequence steps with the master clock rather than its own speed.
+	ClockMultiplier             int                         // Steps taken every ClockDivider beats of the master clock.
+	ClockDivider                int                         // Beats of the master clock for ClockMultiplier steps.
 	RGBInvert                   bool                        // True if RGB sequence patten is inverted.
 	Hidden                      bool                        // Hidden is used to indicate sequence buttons are not visible.
+	FixturePage                 int                         // Page of eight fixtures shown on the launchpad, used when a sequence has more than eight fixtures.
+	PatternFit                  string                      // How the eight wide RGB patterns fit sequences with more fixtures, scale or tile.
 	Type                        string                      // Type of sequnece, current valid values are :- rgb, scanner,  or switch.
 	Master                      int                         // Master Brightness
 	MasterChanging              bool                        // flag to indicate we are changing brightness.
@@ -436,6 +679,7 @@ type Sequence struct {
 	UpdateSequenceColor         bool                        // Command to update the sequence colors.
 	Switches                    map[int]Switch              // A switch sequence stores its data in here.
 	CurrentSwitch               int                         // Play this current switch position.
+	SwitchesToPlay              []int                       // Switches to play after the current switch, moved with it by interlocks and dependencies.
 	Optimisation                bool                        // Flag to decide on calculatePositions Optimisation.
 	RGBNumberStepsInFade        int                         // Number of steps in a RGB fade.
 }
@@ -449,11 +693,25 @@ type Function struct {
 	Label          string
 }
 
+// SnapshotRequest asks a sequence for a copy of itself with the ReadConfig command.
+// The copy is sent back on Reply tagged with the request's ID.
+type SnapshotRequest struct {
+	ID    int
+	Reply chan SnapshotReply
+}
+
+type SnapshotReply struct {
+	ID             int
+	SequenceNumber int
+	Sequence       Sequence
+}
+
 type Channels struct {
 	CommmandChannels []chan Command
 	ReplyChannels    []chan Sequence
 	UpdateChannels   []chan Sequence
 	SoundTriggers    []*Trigger
+	ClockTriggers    []chan Command
 }
 
 type SwitchChannel struct {
@@ -486,6 +744,7 @@ type FixtureCommand struct {
 
 	// Common commands.
 	Hidden         bool
+	FixturePage    int
 	Strobe         bool
 	StrobeSpeed    int
 	Master         int
@@ -601,11 +860,9 @@ func SendCommandToSequence(targetSequence int, command Command, commandChannels
 }
 
 func SendCommandToAllSequence(command Command, commandChannels []chan Command) {
-	commandChannels[0] <- command
-	commandChannels[1] <- command
-	commandChannels[2] <- command
-	commandChannels[3] <- command
-	commandChannels[4] <- command
+	for index := range commandChannels {
+		commandChannels[index] <- command
+	}
 }
 
 func SendCommandToAllSequenceOfType(sequences []*Sequence, command Command, commandChannels []chan Command, Type string) {
@@ -857,6 +1114,9 @@ func GetRGBColorByName(color string) (Color, error) {
 	case "Pink":
 		return Pink, nil
 
+	case "Magenta":
+		return Magenta, nil
+
 	case "White":
 		return White, nil
 
@@ -993,14 +1253,130 @@ func RefreshSequence(selectedSequence int, commandChannels []chan Command, updat
 	return &newSequence
 }
 
-func ShowStaticButtons(sequence *Sequence, staticFlashing bool, eventsForLaunchpad chan ALight, guiButtons chan ALight) {
+// FixtureOnPage returns true if the fixture is shown on the given page of the launchpad.
+func FixtureOnPage(fixtureNumber int, page int) bool {
+	return fixtureNumber/FIXTURES_PER_PAGE == page
+}
 
-	var sequenceNumber int
-	if sequence.Number == 4 {
-		sequenceNumber = 2
-	} else {
-		sequenceNumber = sequence.Number
+// FixtureColumn returns the launchpad column used to show a fixture.
+func FixtureColumn(fixtureNumber int) int {
+	return fixtureNumber % FIXTURES_PER_PAGE
+}
+
+// NumberOfFixturePages returns the number of launchpad pages needed to show all the fixtures in a sequence.
+func NumberOfFixturePages(numberFixtures int) int {
+	if numberFixtures <= FIXTURES_PER_PAGE {
+		return 1
 	}
+	return (numberFixtures + FIXTURES_PER_PAGE - 1) / FIXTURES_PER_PAGE
+}
+
+var sequenceRowsLock sync.Mutex
+var pageSequences []int // Sequences shown on the paged rows, in sequences.yaml order.
+var sequencePage int    // Page of sequences being shown.
+
+// SetSequenceRows works out which sequences take turns on the paged rows and goes back to the first page.
+// The switch sequence has a row of its own and the shutter chaser shares the scanner row.
+func SetSequenceRows(sequences []*Sequence) {
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+
+	pageSequences = []int{}
+	for _, sequence := range sequences {
+		if sequence.Number == GlobalSwitchSequenceNumber || sequence.Number == GlobalChaserSequenceNumber {
+			continue
+		}
+		pageSequences = append(pageSequences, sequence.Number)
+	}
+	sequencePage = 0
+}
+
+// SequenceRow returns the launchpad row a sequence is shown on, or HIDDEN_ROW if it isn't on the page being shown.
+// Until the rows are set each sequence is shown on the row matching its number.
+func SequenceRow(sequenceNumber int) int {
+	if sequenceNumber == GlobalSwitchSequenceNumber {
+		return SWITCH_ROW
+	}
+	sequenceNumber = GetDisplaySequenceNumber(sequenceNumber)
+
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+
+	if pageSequences == nil {
+		return sequenceNumber
+	}
+	for index, pageSequence := range pageSequences {
+		if pageSequence == sequenceNumber {
+			if index/SEQUENCE_PAGE_ROWS != sequencePage {
+				return HIDDEN_ROW
+			}
+			return index % SEQUENCE_PAGE_ROWS
+		}
+	}
+	return HIDDEN_ROW
+}
+
+// RowSequence returns the sequence shown on a launchpad row, or -1 if the row isn't showing a sequence.
+func RowSequence(row int) int {
+	if row < 0 || row >= MAX_SEQUENCE_ROWS {
+		return -1
+	}
+
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+
+	if pageSequences == nil {
+		return row
+	}
+	if row == SWITCH_ROW {
+		return GlobalSwitchSequenceNumber
+	}
+	index := sequencePage*SEQUENCE_PAGE_ROWS + row
+	if index >= len(pageSequences) {
+		return -1
+	}
+	return pageSequences[index]
+}
+
+// SequencePage returns the page of sequences being shown.
+func SequencePage() int {
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+	return sequencePage
+}
+
+// NumberOfSequencePages returns how many pages it takes to show all the sequences.
+func NumberOfSequencePages() int {
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+	if len(pageSequences) <= SEQUENCE_PAGE_ROWS {
+		return 1
+	}
+	return (len(pageSequences) + SEQUENCE_PAGE_ROWS - 1) / SEQUENCE_PAGE_ROWS
+}
+
+// SetSequencePage shows another page of sequences, going round to the first page after the last.
+func SetSequencePage(page int) {
+	pages := NumberOfSequencePages()
+
+	sequenceRowsLock.Lock()
+	defer sequenceRowsLock.Unlock()
+	sequencePage = ((page % pages) + pages) % pages
+}
+
+// GetDisplaySequenceNumber returns the launchpad row used to display a sequence.
+// The shutter chaser doesn't have a row of its own, it shares the scanner row.
+func GetDisplaySequenceNumber(sequenceNumber int) int {
+	if sequenceNumber == GlobalChaserSequenceNumber {
+		return GlobalScannerSequenceNumber
+	}
+	return sequenceNumber
+}
+
+func ShowStaticButtons(sequence *Sequence, staticFlashing bool, eventsForLaunchpad chan ALight, guiButtons chan ALight) {
+
+	sequenceNumber := GetDisplaySequenceNumber(sequence.Number)
+	row := SequenceRow(sequenceNumber)
 
 	if debug {
 		fmt.Printf("%d: ShowStaticButtons\n", sequenceNumber)
@@ -1016,9 +1392,9 @@ func ShowStaticButtons(sequence *Sequence, staticFlashing bool, eventsForLaunchp
 		if staticColorButton.Enabled {
 			if staticColorButton.Flash || staticFlashing {
 				onColor := Color{R: staticColorButton.Color.R, G: staticColorButton.Color.G, B: staticColorButton.Color.B}
-				FlashLight(Button{X: fixtureNumber, Y: sequenceNumber}, onColor, Black, eventsForLaunchpad, guiButtons)
+				FlashLight(Button{X: fixtureNumber, Y: row}, onColor, Black, eventsForLaunchpad, guiButtons)
 			} else {
-				LightLamp(Button{X: fixtureNumber, Y: sequenceNumber}, staticColorButton.Color, sequence.Master, eventsForLaunchpad, guiButtons)
+				LightLamp(Button{X: fixtureNumber, Y: row}, staticColorButton.Color, sequence.Master, eventsForLaunchpad, guiButtons)
 			}
 		}
 	}
@@ -1028,22 +1404,23 @@ func ClearSelectedRowOfButtons(selectedSequence int, eventsForLauchpad chan ALig
 	if debug {
 		fmt.Printf("%d: ClearSelectedRowOfButtons\n", selectedSequence)
 	}
-	// TODO replace with constants for switch and chase sequence numbers.
-	if selectedSequence == 4 || selectedSequence == 3 {
+	if selectedSequence == GlobalChaserSequenceNumber || selectedSequence == GlobalSwitchSequenceNumber {
 		return
 	}
+	row := SequenceRow(selectedSequence)
 	for x := 0; x < 8; x++ {
-		LightLamp(Button{X: x, Y: selectedSequence}, Black, MIN_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
-		LabelButton(x, selectedSequence, "", guiButtons)
+		LightLamp(Button{X: x, Y: row}, Black, MIN_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+		LabelButton(x, row, "", guiButtons)
 	}
 }
 
 func ClearLabelsSelectedRowOfButtons(selectedSequence int, guiButtons chan ALight) {
-	if selectedSequence == 4 {
+	if selectedSequence == GlobalChaserSequenceNumber {
 		return
 	}
+	row := SequenceRow(selectedSequence)
 	for x := 0; x < 8; x++ {
-		LabelButton(x, selectedSequence, "", guiButtons)
+		LabelButton(x, row, "", guiButtons)
 	}
 }
 
@@ -1178,6 +1555,10 @@ func LabelButton(X int, Y int, label string, guiButtons chan ALight) {
 	if debug {
 		fmt.Printf("Label Button  X:%d  Y:%d  with %s\n", X, Y, label)
 	}
+	// Sequences that aren't on the page being shown don't have a row.
+	if Y == HIDDEN_ROW {
+		return
+	}
 	// Send message to GUI
 	event := ALight{
 		UpdateLabel: true,
@@ -1187,6 +1568,16 @@ func LabelButton(X int, Y int, label string, guiButtons chan ALight) {
 		},
 		Label: label,
 	}
+
+	gridLock.Lock()
+	defer gridLock.Unlock()
+	button := Button{X: X, Y: Y}
+	if onGrid(button) {
+		gridLabels[button] = event
+		if gridCovered {
+			return
+		}
+	}
 	guiButtons <- event
 }
 
@@ -1204,10 +1595,9 @@ func LightLamp(button Button, color Color, master int, eventsForLauchpad chan AL
 		Blue:       color.B,
 		Flash:      false,
 	}
-	eventsForLauchpad <- event // Event will be received by pkg/launchpad/launchpad.go ListenAndSendToLaunchPad()
 
 	// Send message to fyne.io GUI.
-	event = ALight{
+	guiEvent := ALight{
 		Button: Button{
 			X: button.X,
 			Y: button.Y + 1,
@@ -1218,7 +1608,30 @@ func LightLamp(button Button, color Color, master int, eventsForLauchpad chan AL
 		Blue:       color.B,
 		Flash:      false,
 	}
-	guiButtons <- event // Event will be received by dmxlights.go by pkg/gui/gui.go ListenAndSendToGUI()
+
+	sendLamp(button, event, guiEvent, eventsForLauchpad, guiButtons)
+}
+
+// sendLamp sends a lamp event to the launchpad and the GUI, unless the grid is covered by the fader page.
+// The last lamp event for each grid button is kept so it can be shown again when the grid is uncovered.
+func sendLamp(button Button, event ALight, guiEvent ALight, eventsForLauchpad chan ALight, guiButtons chan ALight) {
+	// Sequences that aren't on the page being shown don't have a row.
+	if button.Y == HIDDEN_ROW {
+		return
+	}
+
+	gridLock.Lock()
+	defer gridLock.Unlock()
+
+	if onGrid(button) {
+		gridLamps[button] = event
+		gridGUILamps[button] = guiEvent
+		if gridCovered {
+			return
+		}
+	}
+	eventsForLauchpad <- event // Event will be received by pkg/launchpad/launchpad.go ListenAndSendToLaunchPad()
+	guiButtons <- guiEvent     // Event will be received by dmxlights.go by pkg/gui/gui.go ListenAndSendToGUI()
 }
 
 func UpdateStatusBar(status string, which string, hide bool, guiButtons chan ALight) {
@@ -1264,7 +1677,6 @@ func FlashLight(button Button, onColor Color, offColor Color, eventsForLauchpad
 		OnColor:    onColor,
 		OffColor:   offColor,
 	}
-	eventsForLauchpad <- e // Event will be received by pkg/launchpad/launchpad.go ListenAndSendToLaunchPad()
 
 	// Send message to GUI
 	event := ALight{
@@ -1274,8 +1686,8 @@ func FlashLight(button Button, onColor Color, offColor Color, eventsForLauchpad
 		OnColor:    onColor,
 		OffColor:   offColor,
 	}
-	guiButtons <- event // Event will be received by dmxlights.go by pkg/gui/gui.go ListenAndSendToGUI()
 
+	sendLamp(button, e, event, eventsForLauchpad, guiButtons)
 }
 
 // InvertColor just reverses the DMX values.
@@ -1592,3 +2004,310 @@ func GetColor(X int, Y int) ColorPicker {
 	}
 	return ColorPicker{}
 }
+
+// Freeze holds every sequence, fixture fade and mini sequencer where it is until the freeze is released.
+// Anything that moves on with time waits on the freeze gate before taking its next step.
+var freezeLock sync.Mutex
+var freezeReleased = closedChannel()    // Closed while not frozen.
+var freezeStarted = make(chan struct{}) // Closed while frozen.
+var freezeStart time.Time
+var freezeTotal time.Duration
+
+func closedChannel() chan struct{} {
+	channel := make(chan struct{})
+	close(channel)
+	return channel
+}
+
+// Freeze stops everything that moves on with time at its next step.
+func Freeze() {
+	freezeLock.Lock()
+	defer freezeLock.Unlock()
+	if isFrozen() {
+		return
+	}
+	freezeStart = time.Now()
+	freezeReleased = make(chan struct{})
+	close(freezeStarted)
+}
+
+// Unfreeze lets everything carry on from where it was held.
+func Unfreeze() {
+	freezeLock.Lock()
+	defer freezeLock.Unlock()
+	if !isFrozen() {
+		return
+	}
+	freezeTotal += time.Since(freezeStart)
+	freezeStarted = make(chan struct{})
+	close(freezeReleased)
+}
+
+// IsFrozen returns true while a freeze is on.
+func IsFrozen() bool {
+	freezeLock.Lock()
+	defer freezeLock.Unlock()
+	return isFrozen()
+}
+
+func isFrozen() bool {
+	select {
+	case <-freezeReleased:
+		return false
+	default:
+		return true
+	}
+}
+
+// FreezeReleased returns a channel which is closed when there is no freeze on, so it can be used in a select.
+func FreezeReleased() <-chan struct{} {
+	freezeLock.Lock()
+	defer freezeLock.Unlock()
+	return freezeReleased
+}
+
+// WaitWhileFrozen waits until any freeze is released.
+func WaitWhileFrozen() {
+	<-FreezeReleased()
+}
+
+// FrozenTime returns how long has been spent frozen since we started, including any freeze that's on now.
+// The difference between two readings is the time spent frozen in between.
+func FrozenTime() time.Duration {
+	freezeLock.Lock()
+	defer freezeLock.Unlock()
+	if isFrozen() {
+		return freezeTotal + time.Since(freezeStart)
+	}
+	return freezeTotal
+}
+
+// FreezableWait waits for the given time, not counting any time spent frozen.
+// Returns false if cancelled first.
+func FreezableWait(waitTime time.Duration, cancel <-chan bool) bool {
+	for {
+		// Wait for any freeze to be released.
+		select {
+		case <-cancel:
+			return false
+		case <-FreezeReleased():
+		}
+
+		freezeLock.Lock()
+		started := freezeStarted
+		freezeLock.Unlock()
+
+		if waitTime <= 0 {
+			select {
+			case <-cancel:
+				return false
+			default:
+				return true
+			}
+		}
+
+		// Wait out the time unless a freeze starts first, then wait for what's left once it's released.
+		begin := time.Now()
+		timer := time.NewTimer(waitTime)
+		select {
+		case <-cancel:
+			timer.Stop()
+			return false
+		case <-timer.C:
+			return true
+		case <-started:
+			timer.Stop()
+			waitTime -= time.Since(begin)
+		}
+	}
+}
+
+// The fader page covers the eight by eight grid of the launchpad and the GUI. The sequences, switches and
+// presets carry on lighting the grid underneath, what they light is shown again when the grid is uncovered.
+var gridLock sync.Mutex
+var gridCovered bool
+var gridLamps = make(map[Button]ALight)    // Last lamp event for each grid button on the launchpad.
+var gridGUILamps = make(map[Button]ALight) // And in the GUI.
+var gridLabels = make(map[Button]ALight)   // Last label for each grid button in the GUI.
+
+// The fader page has a fader in each column of the grid, each a column of pads.
+const FADER_STEPS = 8
+
+// onGrid returns true for the buttons in the eight by eight grid, not the top row or the side buttons.
+func onGrid(button Button) bool {
+	return button.X >= 0 && button.X < 8 && button.Y >= 0 && button.Y < 8
+}
+
+// CoverGrid stops lamps and labels reaching the grid, so the fader page can use it.
+func CoverGrid() {
+	gridLock.Lock()
+	gridCovered = true
+	gridLock.Unlock()
+}
+
+// UncoverGrid shows what was lit on the grid underneath the fader page again.
+func UncoverGrid(eventsForLauchpad chan ALight, guiButtons chan ALight) {
+	gridLock.Lock()
+	defer gridLock.Unlock()
+
+	gridCovered = false
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			button := Button{X: x, Y: y}
+			if event, ok := gridLamps[button]; ok {
+				eventsForLauchpad <- event
+				guiButtons <- gridGUILamps[button]
+			} else {
+				eventsForLauchpad <- ALight{Button: button}
+				guiButtons <- ALight{Button: Button{X: x, Y: y + 1}}
+			}
+			if label, ok := gridLabels[button]; ok {
+				guiButtons <- label
+			} else {
+				guiButtons <- ALight{UpdateLabel: true, Button: Button{X: x, Y: y + 1}}
+			}
+		}
+	}
+}
+
+// IsGridCovered returns true while the fader page covers the grid.
+func IsGridCovered() bool {
+	gridLock.Lock()
+	defer gridLock.Unlock()
+	return gridCovered
+}
+
+// LightCoverLamp lights a grid button on the fader page covering the grid.
+func LightCoverLamp(button Button, color Color, brightness int, eventsForLauchpad chan ALight, guiButtons chan ALight) {
+	gridLock.Lock()
+	defer gridLock.Unlock()
+
+	if !gridCovered {
+		return
+	}
+	eventsForLauchpad <- ALight{Button: button, Brightness: brightness, Red: color.R, Green: color.G, Blue: color.B}
+	guiButtons <- ALight{Button: Button{X: button.X, Y: button.Y + 1}, Brightness: brightness, Red: color.R, Green: color.G, Blue: color.B}
+}
+
+// LabelCoverButton labels a grid button in the GUI on the fader page covering the grid.
+func LabelCoverButton(X int, Y int, label string, guiButtons chan ALight) {
+	gridLock.Lock()
+	defer gridLock.Unlock()
+
+	if !gridCovered {
+		return
+	}
+	guiButtons <- ALight{UpdateLabel: true, Button: Button{X: X, Y: Y + 1}, Label: label}
+}
+
+// StepPlayOrder returns the order the steps of a chase are played in for one cycle.
+// lastStep is the last step played in the previous cycle, or -1, so random never repeats a step
+// across cycles either. pick(n) returns a random number from 0 to n-1.
+// Bounce is left to CalculatePositions, so bounce returns the steps forward.
+func StepPlayOrder(numberSteps int, order int, lastStep int, pick func(int) int) []int {
+
+	playOrder := []int{}
+
+	switch order {
+	case STEP_ORDER_PING_PONG:
+		// Forward and then back again, so the last and first steps play twice.
+		for step := 0; step < numberSteps; step++ {
+			playOrder = append(playOrder, step)
+		}
+		for step := numberSteps - 1; step >= 0; step-- {
+			playOrder = append(playOrder, step)
+		}
+
+	case STEP_ORDER_RANDOM:
+		for step := 0; step < numberSteps; step++ {
+			if numberSteps == 1 {
+				playOrder = append(playOrder, 0)
+				continue
+			}
+			// Pick from all the steps but the last one, skipping over it.
+			next := pick(numberSteps - 1)
+			if lastStep >= 0 && next >= lastStep {
+				next++
+			}
+			playOrder = append(playOrder, next)
+			lastStep = next
+		}
+
+	case STEP_ORDER_SHUFFLE:
+		for step := 0; step < numberSteps; step++ {
+			playOrder = append(playOrder, step)
+		}
+		// Fisher-Yates shuffle.
+		for step := numberSteps - 1; step > 0; step-- {
+			swap := pick(step + 1)
+			playOrder[step], playOrder[swap] = playOrder[swap], playOrder[step]
+		}
+
+	default:
+		for step := 0; step < numberSteps; step++ {
+			playOrder = append(playOrder, step)
+		}
+	}
+
+	return playOrder
+}
+
+// SwingSpeed returns how long to wait on a step with swing applied. Even steps are lengthened by the
+// swing percentage and odd steps shortened by the same amount, so a pair of steps still takes as long.
+func SwingSpeed(speed time.Duration, swing int, step int) time.Duration {
+	if swing <= 0 {
+		return speed
+	}
+	if swing > MAX_SWING {
+		swing = MAX_SWING
+	}
+	if step%2 == 0 {
+		return speed * time.Duration(100+swing) / 100
+	}
+	return speed * time.Duration(100-swing) / 100
+}
+
+// FaderLevel returns the level set by touching a fader's pad. The top row is full and each row down is a step lower.
+// Touching the bottom pad when the fader is already on its bottom step turns it right down.
+func FaderLevel(row int, level int, min int, max int) int {
+	if row < 0 {
+		row = 0
+	}
+	if row > FADER_STEPS-1 {
+		row = FADER_STEPS - 1
+	}
+	step := FADER_STEPS - row
+	if step == 1 && level <= min+(max-min)/FADER_STEPS {
+		return min
+	}
+	return min + (max-min)*step/FADER_STEPS
+}
+
+// FaderMeter returns the brightness of each pad in a fader's column from the top row down, so the
+// column shows the level as a meter. The pads below the level are lit, the pad at the level is lit
+// in proportion to how far the level is into its step.
+func FaderMeter(level int, min int, max int) [FADER_STEPS]int {
+	var meter [FADER_STEPS]int
+	if max <= min {
+		return meter
+	}
+	if level < min {
+		level = min
+	}
+	if level > max {
+		level = max
+	}
+	// How many pads are lit, in units of a pad's full brightness.
+	lit := (level - min) * FADER_STEPS * MAX_DMX_BRIGHTNESS / (max - min)
+	for row := 0; row < FADER_STEPS; row++ {
+		brightness := lit - (FADER_STEPS-1-row)*MAX_DMX_BRIGHTNESS
+		if brightness < 0 {
+			brightness = 0
+		}
+		if brightness > MAX_DMX_BRIGHTNESS {
+			brightness = MAX_DMX_BRIGHTNESS
+		}
+		meter[row] = brightness
+	}
+	return meter
+}
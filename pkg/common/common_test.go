@@ -19,6 +19,7 @@ package common
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_invertColor(t *testing.T) {
@@ -317,3 +318,421 @@ func TestReverseDmx(t *testing.T) {
 		})
 	}
 }
+
+func TestFixturePages(t *testing.T) {
+	tests := []struct {
+		name           string
+		fixtureNumber  int
+		numberFixtures int
+		wantPage       int
+		wantColumn     int
+		wantPages      int
+	}{
+		{name: "first fixture of eight", fixtureNumber: 0, numberFixtures: 8, wantPage: 0, wantColumn: 0, wantPages: 1},
+		{name: "last fixture of eight", fixtureNumber: 7, numberFixtures: 8, wantPage: 0, wantColumn: 7, wantPages: 1},
+		{name: "ninth fixture of sixteen", fixtureNumber: 8, numberFixtures: 16, wantPage: 1, wantColumn: 0, wantPages: 2},
+		{name: "last fixture of seventeen", fixtureNumber: 16, numberFixtures: 17, wantPage: 2, wantColumn: 0, wantPages: 3},
+		{name: "fewer than eight fixtures", fixtureNumber: 2, numberFixtures: 4, wantPage: 0, wantColumn: 2, wantPages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !FixtureOnPage(tt.fixtureNumber, tt.wantPage) {
+				t.Errorf("FixtureOnPage(%d, %d) = false, want true", tt.fixtureNumber, tt.wantPage)
+			}
+			if FixtureOnPage(tt.fixtureNumber, tt.wantPage+1) {
+				t.Errorf("FixtureOnPage(%d, %d) = true, want false", tt.fixtureNumber, tt.wantPage+1)
+			}
+			if got := FixtureColumn(tt.fixtureNumber); got != tt.wantColumn {
+				t.Errorf("FixtureColumn() = %d, want %d", got, tt.wantColumn)
+			}
+			if got := NumberOfFixturePages(tt.numberFixtures); got != tt.wantPages {
+				t.Errorf("NumberOfFixturePages() = %d, want %d", got, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestSwitchAdvance(t *testing.T) {
+	timer := &SwitchAdvance{
+		Mode:  SWITCH_ADVANCE_ORDER,
+		Steps: []AdvanceStep{{Position: 2, Hold: time.Second}, {Position: 0, Hold: 3 * time.Second}},
+	}
+	beat := &SwitchAdvance{
+		Mode:  SWITCH_ADVANCE_BEAT,
+		Beats: 4,
+		Steps: []AdvanceStep{{Position: 0}, {Position: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		advance *SwitchAdvance
+		step    int
+		held    time.Duration
+		beats   int
+		want    bool
+	}{
+		{name: "held less than the step's hold", advance: timer, step: 1, held: 2 * time.Second, want: false},
+		{name: "held for the step's hold", advance: timer, step: 1, held: 3 * time.Second, want: true},
+		{name: "time doesn't count on the beat", advance: beat, step: 0, held: time.Hour, beats: 3, want: false},
+		{name: "enough beats", advance: beat, step: 0, beats: 4, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.advance.Due(tt.step, tt.held, tt.beats); got != tt.want {
+				t.Errorf("Due() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	if got := timer.Next(1); got != 0 {
+		t.Errorf("Next() after the last step = %d, want 0", got)
+	}
+	if got := timer.StepOf(0, 0); got != 1 {
+		t.Errorf("StepOf() a pressed switch = %d, want 1", got)
+	}
+	if got := timer.StepOf(1, 1); got != 1 {
+		t.Errorf("StepOf() a position not in the order = %d, want 1", got)
+	}
+}
+
+func TestMoveSwitch(t *testing.T) {
+	offOn := func(name string, interlock string, requires ...Requirement) Switch {
+		return Switch{
+			Name:      name,
+			Label:     name,
+			Interlock: interlock,
+			States: map[int]State{
+				0: {Number: 0, Label: "Off"},
+				1: {Number: 1, Label: "On", Requires: requires},
+			},
+		}
+	}
+	newSwitches := func(positions ...int) map[int]Switch {
+		switches := map[int]Switch{
+			0: offOn("hazer", "smoke"),
+			1: offOn("pyro", "smoke"),
+			2: offOn("fan", ""),
+			3: offOn("uv", "", Requirement{Switch: "fan", State: 1}),
+		}
+		for switchNumber, position := range positions {
+			setSwitchPosition(switches, switchNumber, position)
+		}
+		return switches
+	}
+
+	tests := []struct {
+		name          string
+		switches      map[int]Switch
+		switchNumber  int
+		position      int
+		wantMoved     []int
+		wantPositions []int
+		wantErr       bool
+	}{
+		{
+			name:          "interlock turns the other switch off",
+			switches:      newSwitches(1, 0, 0, 0),
+			switchNumber:  1,
+			position:      1,
+			wantMoved:     []int{1, 0},
+			wantPositions: []int{0, 1, 0, 0},
+		},
+		{
+			name:          "turning off doesn't touch the interlock group",
+			switches:      newSwitches(0, 1, 0, 0),
+			switchNumber:  1,
+			position:      0,
+			wantMoved:     []int{1},
+			wantPositions: []int{0, 0, 0, 0},
+		},
+		{
+			name:          "requirement not met",
+			switches:      newSwitches(0, 0, 0, 0),
+			switchNumber:  3,
+			position:      1,
+			wantPositions: []int{0, 0, 0, 0},
+			wantErr:       true,
+		},
+		{
+			name:          "requirement met",
+			switches:      newSwitches(0, 0, 1, 0),
+			switchNumber:  3,
+			position:      1,
+			wantMoved:     []int{3},
+			wantPositions: []int{0, 0, 1, 1},
+		},
+		{
+			name:          "turning off a required switch turns off the switch needing it",
+			switches:      newSwitches(0, 0, 1, 1),
+			switchNumber:  2,
+			position:      0,
+			wantMoved:     []int{2, 3},
+			wantPositions: []int{0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved, err := MoveSwitch(tt.switches, tt.switchNumber, tt.position)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveSwitch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(moved, tt.wantMoved) {
+				t.Errorf("MoveSwitch() moved = %v, want %v", moved, tt.wantMoved)
+			}
+			for switchNumber, want := range tt.wantPositions {
+				if got := tt.switches[switchNumber].CurrentPosition; got != want {
+					t.Errorf("switch %d position = %d, want %d", switchNumber, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	defer Unfreeze()
+
+	if IsFrozen() {
+		t.Fatalf("IsFrozen() before freezing = true")
+	}
+
+	// A wait doesn't count the time spent frozen.
+	before := FrozenTime()
+	start := time.Now()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		Freeze()
+		time.Sleep(100 * time.Millisecond)
+		Unfreeze()
+	}()
+	if !FreezableWait(50*time.Millisecond, make(chan bool)) {
+		t.Fatalf("FreezableWait() = false, want true")
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("FreezableWait() took %v, want at least 150ms", elapsed)
+	}
+	if frozen := FrozenTime() - before; frozen < 100*time.Millisecond {
+		t.Errorf("FrozenTime() grew by %v, want at least 100ms", frozen)
+	}
+
+	// A cancel still works while frozen.
+	Freeze()
+	cancel := make(chan bool)
+	close(cancel)
+	if FreezableWait(time.Millisecond, cancel) {
+		t.Errorf("FreezableWait() cancelled while frozen = true, want false")
+	}
+
+	// Waiters are let go on release.
+	released := make(chan bool)
+	go func() {
+		WaitWhileFrozen()
+		close(released)
+	}()
+	Unfreeze()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Errorf("WaitWhileFrozen() still waiting after Unfreeze()")
+	}
+}
+
+func TestFaderLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		row   int
+		level int
+		min   int
+		max   int
+		want  int
+	}{
+		{name: "top pad is full", row: 0, level: 0, min: 0, max: 255, want: 255},
+		{name: "half way down", row: 4, level: 255, min: 0, max: 255, want: 127},
+		{name: "bottom pad is the bottom step", row: 7, level: 255, min: 0, max: 255, want: 31},
+		{name: "bottom pad again turns it right down", row: 7, level: 31, min: 0, max: 255, want: 0},
+		{name: "range not starting at zero", row: 0, level: 1, min: 1, max: 12, want: 12},
+		{name: "off the grid is kept on it", row: 9, level: 255, min: 0, max: 255, want: 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FaderLevel(tt.row, tt.level, tt.min, tt.max); got != tt.want {
+				t.Errorf("FaderLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaderMeter(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		min   int
+		max   int
+		want  [FADER_STEPS]int
+	}{
+		{name: "off", level: 0, min: 0, max: 255, want: [FADER_STEPS]int{0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "full", level: 255, min: 0, max: 255, want: [FADER_STEPS]int{255, 255, 255, 255, 255, 255, 255, 255}},
+		{name: "half", level: 6, min: 0, max: 12, want: [FADER_STEPS]int{0, 0, 0, 0, 255, 255, 255, 255}},
+		{name: "part way into a step", level: 3, min: 0, max: 16, want: [FADER_STEPS]int{0, 0, 0, 0, 0, 0, 127, 255}},
+		{name: "out of range is full", level: 300, min: 0, max: 255, want: [FADER_STEPS]int{255, 255, 255, 255, 255, 255, 255, 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FaderMeter(tt.level, tt.min, tt.max); got != tt.want {
+				t.Errorf("FaderMeter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoverGrid(t *testing.T) {
+	eventsForLaunchpad := make(chan ALight, 1000)
+	guiButtons := make(chan ALight, 1000)
+	drain := func() (lamps []ALight) {
+		for len(eventsForLaunchpad) > 0 {
+			lamps = append(lamps, <-eventsForLaunchpad)
+		}
+		for len(guiButtons) > 0 {
+			<-guiButtons
+		}
+		return lamps
+	}
+
+	LightLamp(Button{X: 1, Y: 2}, Red, MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	drain()
+
+	// While covered the grid only shows the cover, the buttons outside the grid carry on.
+	CoverGrid()
+	LightLamp(Button{X: 1, Y: 2}, Blue, MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	LightLamp(SAVE_BUTTON, White, MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	LightCoverLamp(Button{X: 1, Y: 2}, Green, MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	lamps := drain()
+	if len(lamps) != 2 || lamps[0].Button != SAVE_BUTTON || lamps[1].Green != Green.G {
+		t.Fatalf("covered grid sent %+v, want the save button and the cover", lamps)
+	}
+
+	// Uncovering shows the last lamp lit underneath.
+	UncoverGrid(eventsForLaunchpad, guiButtons)
+	for _, lamp := range drain() {
+		if lamp.Button == (Button{X: 1, Y: 2}) && lamp.Blue != Blue.B {
+			t.Errorf("uncovered lamp = %+v, want blue", lamp)
+		}
+	}
+	LightCoverLamp(Button{X: 1, Y: 2}, Green, MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+	if lamps := drain(); len(lamps) != 0 {
+		t.Errorf("cover lamp sent %+v while uncovered", lamps)
+	}
+}
+
+func TestStepPlayOrder(t *testing.T) {
+	first := func(n int) int { return 0 }
+	last := func(n int) int { return n - 1 }
+	tests := []struct {
+		name        string
+		numberSteps int
+		order       int
+		lastStep    int
+		pick        func(int) int
+		want        []int
+	}{
+		{name: "forward", numberSteps: 4, order: STEP_ORDER_FORWARD, lastStep: -1, pick: first, want: []int{0, 1, 2, 3}},
+		{name: "bounce is left to the positions", numberSteps: 4, order: STEP_ORDER_BOUNCE, lastStep: -1, pick: first, want: []int{0, 1, 2, 3}},
+		{name: "ping-pong holds the end steps", numberSteps: 4, order: STEP_ORDER_PING_PONG, lastStep: -1, pick: first, want: []int{0, 1, 2, 3, 3, 2, 1, 0}},
+		{name: "random never repeats a step", numberSteps: 4, order: STEP_ORDER_RANDOM, lastStep: -1, pick: first, want: []int{0, 1, 0, 1}},
+		{name: "random doesn't repeat the last cycle's step", numberSteps: 4, order: STEP_ORDER_RANDOM, lastStep: 0, pick: first, want: []int{1, 0, 1, 0}},
+		{name: "random with one step", numberSteps: 1, order: STEP_ORDER_RANDOM, lastStep: 0, pick: first, want: []int{0}},
+		{name: "shuffle", numberSteps: 4, order: STEP_ORDER_SHUFFLE, lastStep: -1, pick: first, want: []int{1, 2, 3, 0}},
+		{name: "shuffle left in order", numberSteps: 4, order: STEP_ORDER_SHUFFLE, lastStep: -1, pick: last, want: []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StepPlayOrder(tt.numberSteps, tt.order, tt.lastStep, tt.pick); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StepPlayOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwingSpeed(t *testing.T) {
+	tests := []struct {
+		name  string
+		speed time.Duration
+		swing int
+		step  int
+		want  time.Duration
+	}{
+		{name: "no swing", speed: 100 * time.Millisecond, swing: 0, step: 0, want: 100 * time.Millisecond},
+		{name: "even step is lengthened", speed: 100 * time.Millisecond, swing: 50, step: 2, want: 150 * time.Millisecond},
+		{name: "odd step is shortened", speed: 100 * time.Millisecond, swing: 50, step: 3, want: 50 * time.Millisecond},
+		{name: "too much swing is limited", speed: 100 * time.Millisecond, swing: 100, step: 1, want: 25 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SwingSpeed(tt.speed, tt.swing, tt.step); got != tt.want {
+				t.Errorf("SwingSpeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceRows(t *testing.T) {
+
+	// Seven groups, a scanner with its shutter chaser and a switch.
+	types := []string{"rgb", "rgb", "rgb", "rgb", "rgb", "rgb", "rgb", "scanner", "switch", "rgb"}
+	sequences := []*Sequence{}
+	for number, tYpe := range types {
+		sequences = append(sequences, &Sequence{Number: number, Type: tYpe})
+	}
+
+	GlobalScannerSequenceNumber = 7
+	GlobalSwitchSequenceNumber = 8
+	GlobalChaserSequenceNumber = 9
+	defer func() {
+		GlobalScannerSequenceNumber = 0
+		GlobalSwitchSequenceNumber = -1
+		GlobalChaserSequenceNumber = -1
+		pageSequences = nil
+		sequencePage = 0
+	}()
+
+	SetSequenceRows(sequences)
+
+	if got := NumberOfSequencePages(); got != 3 {
+		t.Errorf("NumberOfSequencePages() = %d, want 3", got)
+	}
+
+	tests := []struct {
+		name     string
+		page     int
+		sequence int
+		wantRow  int
+	}{
+		{name: "first page", page: 0, sequence: 1, wantRow: 1},
+		{name: "not on the first page", page: 0, sequence: 4, wantRow: HIDDEN_ROW},
+		{name: "second page", page: 1, sequence: 4, wantRow: 1},
+		{name: "scanner on the last page", page: 2, sequence: 7, wantRow: 1},
+		{name: "chaser shares the scanner row", page: 2, sequence: 9, wantRow: 1},
+		{name: "chaser hidden with the scanner", page: 0, sequence: 9, wantRow: HIDDEN_ROW},
+		{name: "switch is on every page", page: 1, sequence: 8, wantRow: SWITCH_ROW},
+		{name: "pages go round", page: 3, sequence: 0, wantRow: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSequencePage(tt.page)
+			row := SequenceRow(tt.sequence)
+			if row != tt.wantRow {
+				t.Errorf("SequenceRow() = %d, want %d", row, tt.wantRow)
+			}
+			if row != HIDDEN_ROW && tt.sequence != GlobalChaserSequenceNumber {
+				if got := RowSequence(row); got != tt.sequence {
+					t.Errorf("RowSequence() = %d, want %d", got, tt.sequence)
+				}
+			}
+		})
+	}
+
+	// The last page has an empty row.
+	SetSequencePage(2)
+	if got := RowSequence(2); got != -1 {
+		t.Errorf("RowSequence() on an empty row = %d, want -1", got)
+	}
+}
@@ -19,59 +19,155 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/project"
 )
 
-func SaveConfig(config []common.Sequence, filename string) {
+const debug = false
 
-	// Marshall the config into a json object.
-	data, err := json.MarshalIndent(config, "", " ")
+// Each preset saves its sequence configs in config<ID>.json, and its programmer
+// values and submaster levels in programmer<ID>.json and submasters<ID>.json.
+const CONFIG_PREFIX = "config"
+const PROGRAMMER_PREFIX = "programmer"
+const SUBMASTERS_PREFIX = "submasters"
+
+// PresetFilename names one of the files saved with a preset, kept in the project being used.
+func PresetFilename(prefix string, id string) string {
+	return project.File(fmt.Sprintf("%s%s.json", prefix, id))
+}
+
+// savePresetFile saves values in one of the files saved with a preset. The file is replaced in one go
+// and the previous one kept as a backup, so a crash while saving never loses a preset.
+func savePresetFile(prefix string, id string, values interface{}) error {
+
+	filename := PresetFilename(prefix, id)
+
+	data, err := marshalPresetFile(prefix, values)
 	if err != nil {
-		log.Fatalf("error: marshalling config: %v", err)
+		return err
 	}
 	// Write to file
-	err = os.WriteFile(filename, data, 0644)
+	err = project.WriteFile(filename, data)
+	if err != nil {
+		return fmt.Errorf("writing %s: %v to file:%s", prefix, err, filename)
+	}
+	return nil
+}
+
+// marshalPresetFile makes the contents of one of the files saved with a preset.
+func marshalPresetFile(prefix string, values interface{}) ([]byte, error) {
+	// Marshall the values into a json object.
+	data, err := json.MarshalIndent(values, "", " ")
 	if err != nil {
-		log.Fatalf("error: writing config: %v to file:%s", err, filename)
+		return nil, fmt.Errorf("marshalling %s: %v", prefix, err)
 	}
+	return data, nil
 }
 
-func LoadConfig(filename string) []common.Sequence {
+// loadPresetFile loads values from one of the files saved with a preset.
+// Returns false if the preset was saved without this file.
+func loadPresetFile(prefix string, id string, values interface{}) (bool, error) {
 
-	config := []common.Sequence{}
+	filename := PresetFilename(prefix, id)
 
 	// Read the file.
 	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
 	if err != nil {
-		log.Fatalf("error: reading config: %v from file:%s", err, filename)
+		return false, fmt.Errorf("reading %s: %v from file:%s", prefix, err, filename)
 	}
 
-	err = json.Unmarshal(data, &config)
+	err = json.Unmarshal(data, values)
 	if err != nil {
-		log.Fatalf("error:  reading config: %v from file:%s", err, filename)
+		return false, fmt.Errorf("reading %s: %v from file:%s", prefix, err, filename)
 	}
-	return config
+	return true, nil
 }
 
-func DeleteConfig(filename string) error {
+// deletePresetFile removes one of the files saved with a preset, if there is one.
+func deletePresetFile(prefix string, id string) error {
+	filename := PresetFilename(prefix, id)
 	err := os.Remove(filename)
-	if err != nil {
-		log.Fatalf("error:  deleting config: %v from file:%s", err, filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("deleting %s: %v from file:%s", prefix, err, filename)
 	}
 	return nil
 }
 
-func AskToLoadConfig(commandChannels []chan common.Command, X int, Y int) {
+// SaveConfig saves the sequence configs for a preset.
+func SaveConfig(config []common.Sequence, id string) error {
+	return savePresetFile(CONFIG_PREFIX, id, config)
+}
+
+// LoadConfig loads the sequence configs saved with a preset, every preset has them.
+func LoadConfig(id string) ([]common.Sequence, error) {
+	config := []common.Sequence{}
+	found, err := loadPresetFile(CONFIG_PREFIX, id, &config)
+	if err == nil && !found {
+		err = fmt.Errorf("reading %s: no file:%s", CONFIG_PREFIX, PresetFilename(CONFIG_PREFIX, id))
+	}
+	return config, err
+}
+
+// DeleteConfig removes the sequence configs saved with a preset, if there are any.
+func DeleteConfig(id string) error {
+	return deletePresetFile(CONFIG_PREFIX, id)
+}
+
+// SaveProgrammerConfig saves the programmer values along with a preset.
+// If the programmer is empty any values saved with the preset before are removed.
+func SaveProgrammerConfig(values []common.ProgrammerValue, id string) error {
+	if len(values) == 0 {
+		return DeleteProgrammerConfig(id)
+	}
+	return savePresetFile(PROGRAMMER_PREFIX, id, values)
+}
+
+// LoadProgrammerConfig loads the programmer values saved with a preset.
+// Returns false if the preset was saved without any.
+func LoadProgrammerConfig(id string) ([]common.ProgrammerValue, bool, error) {
+	values := []common.ProgrammerValue{}
+	found, err := loadPresetFile(PROGRAMMER_PREFIX, id, &values)
+	return values, found, err
+}
+
+// DeleteProgrammerConfig removes the programmer values saved with a preset, if there are any.
+func DeleteProgrammerConfig(id string) error {
+	return deletePresetFile(PROGRAMMER_PREFIX, id)
+}
+
+// SaveSubmasterConfig saves the submaster levels along with a preset.
+// The file is saved even with every submaster at full, so recalling the preset puts them back to full.
+func SaveSubmasterConfig(values []common.SubmasterValue, id string) error {
+	return savePresetFile(SUBMASTERS_PREFIX, id, values)
+}
+
+// LoadSubmasterConfig loads the submaster levels saved with a preset.
+// Returns false if the preset was saved before it had submasters.
+func LoadSubmasterConfig(id string) ([]common.SubmasterValue, bool, error) {
+	values := []common.SubmasterValue{}
+	found, err := loadPresetFile(SUBMASTERS_PREFIX, id, &values)
+	return values, found, err
+}
+
+// DeleteSubmasterConfig removes the submaster levels saved with a preset, if there are any.
+func DeleteSubmasterConfig(id string) error {
+	return deletePresetFile(SUBMASTERS_PREFIX, id)
+}
+
+func AskToLoadConfig(commandChannels []chan common.Command, id string) {
 	command := common.Command{
 		Action: common.LoadConfig,
 		Args: []common.Arg{
-			{Name: "X", Value: X},
-			{Name: "Y", Value: Y},
+			{Name: "Preset", Value: id},
 		},
 	}
 
@@ -80,42 +176,114 @@ func AskToLoadConfig(commandChannels []chan common.Command, X int, Y int) {
 	}
 }
 
-func AskToSaveConfig(sequences []chan common.Command, replyChannel []chan common.Sequence, X int, Y int) {
+// How long the sequences have to send their config when a preset is saved.
+const SNAPSHOT_TIMEOUT = 2 * time.Second
 
-	config := []common.Sequence{}
+// Each snapshot is numbered so a reply can only count towards the snapshot that asked for it.
+var lastSnapshotID int64
+
+// AskToSaveConfig asks every sequence for its config and saves them with a preset, along with
+// the programmer values and the submaster levels. The preset is saved complete or not at all,
+// the error says which sequences didn't reply or which file couldn't be written.
+func AskToSaveConfig(commandChannels []chan common.Command, id string, programmer []common.ProgrammerValue, submasters []common.SubmasterValue) error {
+
+	config, err := TakeSnapshot(commandChannels, SNAPSHOT_TIMEOUT)
+	if err != nil {
+		return fmt.Errorf("preset %s not saved: %w", id, err)
+	}
+
+	files := map[string][]byte{}
+	remove := []string{}
 
-	go func() {
-		// Wait for responses from sequences.
-		time.Sleep(100 * time.Millisecond)
-		for _, replyChannel := range replyChannel {
-			config = append(config, WaitForConfig(replyChannel))
+	values := map[string]interface{}{
+		CONFIG_PREFIX:     config,
+		PROGRAMMER_PREFIX: programmer,
+		SUBMASTERS_PREFIX: submasters,
+	}
+	for prefix, value := range values {
+		// An empty programmer removes any values saved with the preset before.
+		if prefix == PROGRAMMER_PREFIX && len(programmer) == 0 {
+			remove = append(remove, PresetFilename(prefix, id))
+			continue
+		}
+		data, err := marshalPresetFile(prefix, value)
+		if err != nil {
+			return fmt.Errorf("preset %s not saved: %w", id, err)
 		}
+		files[PresetFilename(prefix, id)] = data
+	}
+
+	// Write all the files together.
+	err = project.WriteFiles(files, remove)
+	if err != nil {
+		return fmt.Errorf("preset %s not saved: %w", id, err)
+	}
+	return nil
+}
 
-		// Write to config file.
-		SaveConfig(config, fmt.Sprintf("config%d.%d.json", X, Y))
-	}()
+// TakeSnapshot asks every sequence for a copy of itself, returned in sequence order.
+// A sequence that doesn't take the request, or doesn't reply, within the timeout is an error.
+func TakeSnapshot(commandChannels []chan common.Command, timeout time.Duration) ([]common.Sequence, error) {
+
+	// Replies go to a channel of our own with room for every sequence, so a sequence
+	// replying after we've given up never blocks.
+	request := common.SnapshotRequest{
+		ID:    int(atomic.AddInt64(&lastSnapshotID, 1)),
+		Reply: make(chan common.SnapshotReply, len(commandChannels)),
+	}
+
+	if debug {
+		fmt.Printf("Take Snapshot %d\n", request.ID)
+	}
 
-	// Ask for all the sequencers for their config.
 	command := common.Command{
 		Action: common.ReadConfig,
 		Args: []common.Arg{
-			{Name: "X", Value: X},
-			{Name: "Y", Value: Y},
+			{Name: "Snapshot", Value: request},
 		},
 	}
 
-	for _, seq := range sequences {
-		seq <- command
+	deadline := time.Now().Add(timeout)
+	errs := make([]error, len(commandChannels))
+
+	// Ask all the sequencers for their config.
+	asked := 0
+	for sequenceNumber, commandChannel := range commandChannels {
+		select {
+		case commandChannel <- command:
+			asked++
+		case <-time.After(time.Until(deadline)):
+			errs[sequenceNumber] = fmt.Errorf("sequence %d is busy", sequenceNumber+1)
+		}
 	}
-}
 
-func WaitForConfig(replyChannel chan common.Sequence) common.Sequence {
-	sequence := common.Sequence{}
-	select {
-	case sequence = <-replyChannel:
-		break
-	case <-time.After(500 * time.Millisecond):
-		break
+	// Wait for their replies.
+	config := make([]common.Sequence, len(commandChannels))
+	replied := make([]bool, len(commandChannels))
+	for received := 0; received < asked; {
+		select {
+		case reply := <-request.Reply:
+			if reply.ID != request.ID || reply.SequenceNumber < 0 || reply.SequenceNumber >= len(config) || replied[reply.SequenceNumber] {
+				fmt.Printf("error: unexpected snapshot reply %d from sequence %d\n", reply.ID, reply.SequenceNumber+1)
+				continue
+			}
+			config[reply.SequenceNumber] = reply.Sequence
+			replied[reply.SequenceNumber] = true
+			received++
+		case <-time.After(time.Until(deadline)):
+			received = asked
+		}
+	}
+
+	for sequenceNumber := range commandChannels {
+		if !replied[sequenceNumber] && errs[sequenceNumber] == nil {
+			errs[sequenceNumber] = fmt.Errorf("sequence %d didn't reply", sequenceNumber+1)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
 	}
-	return sequence
+	return config, nil
 }
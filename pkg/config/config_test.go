@@ -0,0 +1,214 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights config tests.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/project"
+)
+
+// fakeSequence answers snapshot requests like a sequence, unless told to ignore them.
+func fakeSequence(sequenceNumber int, commandChannel chan common.Command, reply bool) {
+	for command := range commandChannel {
+		if command.Action == common.ReadConfig && reply {
+			request := command.Args[0].Value.(common.SnapshotRequest)
+			request.Reply <- common.SnapshotReply{
+				ID:             request.ID,
+				SequenceNumber: sequenceNumber,
+				Sequence:       common.Sequence{Number: sequenceNumber, Label: "seq"},
+			}
+		}
+	}
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []bool
+		busy    int
+		wantErr []string
+	}{
+		{
+			name:    "all sequences reply",
+			replies: []bool{true, true, true},
+			busy:    -1,
+		},
+		{
+			name:    "a sequence doesn't reply",
+			replies: []bool{true, false, true},
+			busy:    -1,
+			wantErr: []string{"sequence 2 didn't reply"},
+		},
+		{
+			name:    "a sequence is busy",
+			replies: []bool{true, true, true},
+			busy:    2,
+			wantErr: []string{"sequence 3 is busy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commandChannels := []chan common.Command{}
+			for sequenceNumber, reply := range tt.replies {
+				commandChannel := make(chan common.Command)
+				commandChannels = append(commandChannels, commandChannel)
+				if sequenceNumber != tt.busy {
+					go fakeSequence(sequenceNumber, commandChannel, reply)
+				}
+				defer close(commandChannel)
+			}
+
+			got, err := TakeSnapshot(commandChannels, 50*time.Millisecond)
+
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("TakeSnapshot() error = %v", err)
+				}
+				for sequenceNumber, sequence := range got {
+					if sequence.Number != sequenceNumber || sequence.Label != "seq" {
+						t.Errorf("TakeSnapshot() sequence %d = %+v", sequenceNumber, sequence)
+					}
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("TakeSnapshot() want error")
+			}
+			if got != nil {
+				t.Errorf("TakeSnapshot() returned a snapshot with an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("TakeSnapshot() error = %v, want %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPresetFiles(t *testing.T) {
+	defer project.SetCurrent(project.Current())
+	project.SetCurrent(t.TempDir())
+
+	id := "2.5"
+
+	// A preset that was never saved.
+	if _, err := LoadConfig(id); err == nil {
+		t.Errorf("LoadConfig() of a missing preset want error")
+	}
+	if _, found, err := LoadProgrammerConfig(id); found || err != nil {
+		t.Errorf("LoadProgrammerConfig() of a missing preset = %v %v, want false nil", found, err)
+	}
+
+	config := []common.Sequence{{Number: 1, Label: "seq"}}
+	programmer := []common.ProgrammerValue{{Channel: "Pan", Value: 10}}
+	submasters := []common.SubmasterValue{{Level: 5}}
+
+	if err := SaveConfig(config, id); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if err := SaveProgrammerConfig(programmer, id); err != nil {
+		t.Fatalf("SaveProgrammerConfig() error = %v", err)
+	}
+	if err := SaveSubmasterConfig(submasters, id); err != nil {
+		t.Fatalf("SaveSubmasterConfig() error = %v", err)
+	}
+
+	gotConfig, err := LoadConfig(id)
+	if err != nil || len(gotConfig) != 1 || gotConfig[0].Label != "seq" {
+		t.Errorf("LoadConfig() = %v %v", gotConfig, err)
+	}
+	gotProgrammer, found, err := LoadProgrammerConfig(id)
+	if err != nil || !found || !reflect.DeepEqual(gotProgrammer, programmer) {
+		t.Errorf("LoadProgrammerConfig() = %v %v %v, want %v", gotProgrammer, found, err, programmer)
+	}
+	gotSubmasters, found, err := LoadSubmasterConfig(id)
+	if err != nil || !found || !reflect.DeepEqual(gotSubmasters, submasters) {
+		t.Errorf("LoadSubmasterConfig() = %v %v %v, want %v", gotSubmasters, found, err, submasters)
+	}
+
+	// An empty programmer removes the values saved before.
+	if err := SaveProgrammerConfig(nil, id); err != nil {
+		t.Fatalf("SaveProgrammerConfig() error = %v", err)
+	}
+	if _, err := os.Stat(PresetFilename(PROGRAMMER_PREFIX, id)); err == nil {
+		t.Errorf("SaveProgrammerConfig() of an empty programmer kept the file")
+	}
+
+	for _, deleteFile := range []func(string) error{DeleteConfig, DeleteProgrammerConfig, DeleteSubmasterConfig} {
+		if err := deleteFile(id); err != nil {
+			t.Errorf("delete error = %v", err)
+		}
+	}
+	for _, prefix := range []string{CONFIG_PREFIX, PROGRAMMER_PREFIX, SUBMASTERS_PREFIX} {
+		if _, err := os.Stat(PresetFilename(prefix, id)); err == nil {
+			t.Errorf("%s file not deleted", prefix)
+		}
+	}
+}
+
+func TestAskToSaveConfig(t *testing.T) {
+	defer project.SetCurrent(project.Current())
+	project.SetCurrent(t.TempDir())
+
+	id := "1.1"
+
+	commandChannels := []chan common.Command{}
+	for sequenceNumber := 0; sequenceNumber < 2; sequenceNumber++ {
+		commandChannel := make(chan common.Command)
+		commandChannels = append(commandChannels, commandChannel)
+		go fakeSequence(sequenceNumber, commandChannel, true)
+		defer close(commandChannel)
+	}
+
+	programmer := []common.ProgrammerValue{{Channel: "Pan", Value: 10}}
+	submasters := []common.SubmasterValue{{Level: 5}}
+
+	err := AskToSaveConfig(commandChannels, id, programmer, submasters)
+	if err != nil {
+		t.Fatalf("AskToSaveConfig() error = %v", err)
+	}
+	for _, prefix := range []string{CONFIG_PREFIX, PROGRAMMER_PREFIX, SUBMASTERS_PREFIX} {
+		if _, err := os.Stat(PresetFilename(prefix, id)); err != nil {
+			t.Errorf("AskToSaveConfig() didn't save the %s file", prefix)
+		}
+	}
+
+	// Saved again with an empty programmer.
+	err = AskToSaveConfig(commandChannels, id, nil, submasters)
+	if err != nil {
+		t.Fatalf("AskToSaveConfig() error = %v", err)
+	}
+	if _, err := os.Stat(PresetFilename(PROGRAMMER_PREFIX, id)); err == nil {
+		t.Errorf("AskToSaveConfig() with an empty programmer kept the programmer file")
+	}
+
+	// A project that can't be written leaves nothing saved.
+	project.SetCurrent(filepath.Join(t.TempDir(), "missing"))
+	err = AskToSaveConfig(commandChannels, id, programmer, submasters)
+	if err == nil {
+		t.Errorf("AskToSaveConfig() into a missing project want error")
+	}
+}
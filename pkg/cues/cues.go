@@ -0,0 +1,312 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights cue list, an ordered list of looks stepped through
+// with GO and BACK. Each cue recalls a preset or sends commands to the
+// sequences, crossfading from the lights as they are to the new look.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cues
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+const debug = false
+
+// CueCommand is a command sent to a sequence when a cue plays.
+type CueCommand struct {
+	Sequence int    `yaml:"sequence"`        // Sequence number, numbered from 1.
+	Action   string `yaml:"action"`          // One of start, stop, speed, pattern or master.
+	Value    int    `yaml:"value,omitempty"` // Value for speed, pattern and master.
+}
+
+// Cue is one step in a cue list. Times are in seconds.
+type Cue struct {
+	Name       string       `yaml:"name"`
+	Preset     string       `yaml:"preset,omitempty"`     // ID of the preset to recall, empty for none.
+	Commands   []CueCommand `yaml:"commands,omitempty"`   // Commands sent after the preset is recalled.
+	Delay      float64      `yaml:"delay,omitempty"`      // Wait after GO before the crossfade starts.
+	Fade       float64      `yaml:"fade,omitempty"`       // Time to crossfade from the lights as they are to the cue, zero snaps.
+	Follow     bool         `yaml:"follow,omitempty"`     // Go to the next cue automatically.
+	FollowTime float64      `yaml:"followtime,omitempty"` // Wait after the crossfade before following on.
+}
+
+type CueList struct {
+	Name string `yaml:"name"`
+	Cues []Cue  `yaml:"cues"`
+}
+
+// CopyCueLists makes a deep copy of the cue lists, so a copy kept to spot changes isn't edited with the original.
+// Empty lists stay nil so the copy compares equal to the original.
+func CopyCueLists(lists []CueList) []CueList {
+	if lists == nil {
+		return nil
+	}
+	copied := []CueList{}
+	for _, list := range lists {
+		copied = append(copied, copyCueList(list))
+	}
+	return copied
+}
+
+func copyCueList(list CueList) CueList {
+	newList := CueList{Name: list.Name}
+	if list.Cues != nil {
+		newList.Cues = []Cue{}
+	}
+	for _, cue := range list.Cues {
+		newCue := cue
+		if cue.Commands != nil {
+			newCue.Commands = append([]CueCommand{}, cue.Commands...)
+		}
+		newList.Cues = append(newList.Cues, newCue)
+	}
+	return newList
+}
+
+// Command turns a cue command into the command sent to the sequence.
+func (c CueCommand) Command() (common.Command, error) {
+	switch strings.ToLower(c.Action) {
+	case "start":
+		return common.Command{Action: common.Start}, nil
+	case "stop":
+		return common.Command{Action: common.Stop}, nil
+	case "speed":
+		return common.Command{Action: common.UpdateSpeed, Args: []common.Arg{{Name: "Speed", Value: c.Value}}}, nil
+	case "pattern":
+		return common.Command{Action: common.UpdatePattern, Args: []common.Arg{{Name: "Pattern", Value: c.Value}}}, nil
+	case "master":
+		return common.Command{Action: common.Master, Args: []common.Arg{{Name: "Master", Value: c.Value}}}, nil
+	}
+	return common.Command{}, fmt.Errorf("unknown cue action %q", c.Action)
+}
+
+// FirstCueList returns the first of a project's cue lists, the one played when the project is loaded.
+func FirstCueList(lists []CueList) CueList {
+	if len(lists) == 0 {
+		return CueList{}
+	}
+	return lists[0]
+}
+
+// ParseCueCommands reads cue commands written one per line as "sequence action value",
+// the value is only needed for speed, pattern and master. For example "2 speed 7".
+func ParseCueCommands(text string) ([]CueCommand, error) {
+	commands := []CueCommand{}
+	for lineNumber, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: want sequence action value", lineNumber+1)
+		}
+		command := CueCommand{Action: strings.ToLower(fields[1])}
+		var err error
+		command.Sequence, err = strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad sequence number %q", lineNumber+1, fields[0])
+		}
+		if len(fields) == 3 {
+			command.Value, err = strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad value %q", lineNumber+1, fields[2])
+			}
+		}
+		if _, err := command.Command(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+		}
+		commands = append(commands, command)
+	}
+	return commands, nil
+}
+
+// FormatCueCommands writes cue commands one per line, the reverse of ParseCueCommands.
+func FormatCueCommands(commands []CueCommand) string {
+	lines := []string{}
+	for _, command := range commands {
+		switch command.Action {
+		case "start", "stop":
+			lines = append(lines, fmt.Sprintf("%d %s", command.Sequence, command.Action))
+		default:
+			lines = append(lines, fmt.Sprintf("%d %s %d", command.Sequence, command.Action, command.Value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PresetID returns the ID of the preset a cue recalls. Cues written before presets had IDs
+// give the preset's pad as "X,Y", those presets were given the ID "X.Y".
+func (c Cue) PresetID() string {
+	id := strings.TrimSpace(c.Preset)
+	parts := strings.Split(id, ",")
+	if len(parts) == 2 {
+		return strings.TrimSpace(parts[0]) + "." + strings.TrimSpace(parts[1])
+	}
+	return id
+}
+
+// Player steps through a cue list. Only one transition runs at a time, pressing GO or BACK
+// during a transition abandons it, the new cue's crossfade starts from the lights as they are.
+type Player struct {
+	mutex      sync.Mutex
+	list       CueList
+	current    int // The cue played last, -1 before the first GO.
+	generation int // Bumped for every transition, a transition stops when it is no longer the latest.
+	cancel     chan bool
+	recall     func(number int, cue Cue)
+}
+
+// NewPlayer creates a cue player. recall is called to make each cue's change and start its crossfade,
+// number is the cue's position in the list numbered from 0.
+func NewPlayer(recall func(number int, cue Cue)) *Player {
+	return &Player{
+		current: -1,
+		cancel:  make(chan bool),
+		recall:  recall,
+	}
+}
+
+// SetList replaces the cue list with a copy and stops any transition.
+func (p *Player) SetList(list CueList) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stop()
+	p.list = copyCueList(list)
+	p.current = -1
+}
+
+// UpdateList replaces the cue list after it has been edited, without moving from the current cue.
+func (p *Player) UpdateList(list CueList) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.list = copyCueList(list)
+	if p.current >= len(list.Cues) {
+		p.current = len(list.Cues) - 1
+	}
+}
+
+// List returns the cue list being played.
+func (p *Player) List() CueList {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.list
+}
+
+// Current returns the number of the cue played last, -1 before the first GO.
+func (p *Player) Current() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.current
+}
+
+// Go plays the next cue in the list. Returns the number of the cue played.
+func (p *Player) Go() (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	next := p.current + 1
+	if next >= len(p.list.Cues) {
+		return p.current, fmt.Errorf("end of cue list %s", p.list.Name)
+	}
+	p.start(next, true)
+	return next, nil
+}
+
+// Back plays the previous cue in the list, without its delay and without following on.
+// Returns the number of the cue played.
+func (p *Player) Back() (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	previous := p.current - 1
+	if previous < 0 {
+		return p.current, fmt.Errorf("start of cue list %s", p.list.Name)
+	}
+	p.start(previous, false)
+	return previous, nil
+}
+
+// start begins the transition to a cue. Called with the mutex held.
+func (p *Player) start(number int, forward bool) {
+	p.stop()
+	p.current = number
+	cue := p.list.Cues[number]
+
+	if debug {
+		fmt.Printf("cue %d %s forward %t\n", number+1, cue.Name, forward)
+	}
+
+	go p.play(p.generation, p.cancel, number, cue, forward)
+}
+
+// stop abandons any running transition. Called with the mutex held.
+func (p *Player) stop() {
+	close(p.cancel)
+	p.cancel = make(chan bool)
+	p.generation++
+}
+
+// play runs the transition to a cue, the delay then the change with its crossfade,
+// then follows on to the next cue if asked to.
+func (p *Player) play(generation int, cancel chan bool, number int, cue Cue, forward bool) {
+
+	if forward && !wait(seconds(cue.Delay), cancel) {
+		return
+	}
+
+	if !p.isLatest(generation) {
+		return
+	}
+	p.recall(number, cue)
+
+	if forward && cue.Follow {
+		// Let the crossfade finish before following on.
+		if !wait(cue.FadeTime()+seconds(cue.FollowTime), cancel) {
+			return
+		}
+		if p.isLatest(generation) {
+			p.Go()
+		}
+	}
+}
+
+func (p *Player) isLatest(generation int) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return generation == p.generation
+}
+
+// wait waits for the given time, returns false if cancelled first.
+// Time spent frozen doesn't count, so a cue carries on from where it was.
+func wait(waitTime time.Duration, cancel chan bool) bool {
+	return common.FreezableWait(waitTime, cancel)
+}
+
+// FadeTime returns the time to crossfade to a cue.
+func (c Cue) FadeTime() time.Duration {
+	return seconds(c.Fade)
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
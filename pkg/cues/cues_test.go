@@ -0,0 +1,299 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights cue list tests.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cues
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// testPlayer records the cues recalled.
+type testPlayer struct {
+	mutex    sync.Mutex
+	recalled []int
+	player   *Player
+}
+
+func newTestPlayer(list CueList) *testPlayer {
+	tp := &testPlayer{}
+	tp.player = NewPlayer(func(number int, cue Cue) {
+		tp.mutex.Lock()
+		tp.recalled = append(tp.recalled, number)
+		tp.mutex.Unlock()
+	})
+	tp.player.SetList(list)
+	return tp
+}
+
+func (tp *testPlayer) result() []int {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+	return append([]int{}, tp.recalled...)
+}
+
+func TestPlayerGoAndBack(t *testing.T) {
+
+	tp := newTestPlayer(CueList{
+		Name: "Show",
+		Cues: []Cue{
+			{Name: "One"},
+			{Name: "Two", Fade: 0.01},
+		},
+	})
+
+	// BACK before the first cue has nowhere to go.
+	if _, err := tp.player.Back(); err == nil {
+		t.Errorf("Back() before the first cue want error")
+	}
+
+	number, err := tp.player.Go()
+	if err != nil || number != 0 {
+		t.Fatalf("Go() = %d, %v, want 0", number, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	number, err = tp.player.Go()
+	if err != nil || number != 1 {
+		t.Fatalf("Go() = %d, %v, want 1", number, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// GO after the last cue stays on the last cue.
+	number, err = tp.player.Go()
+	if err == nil || number != 1 {
+		t.Errorf("Go() at the end = %d, %v, want 1 and an error", number, err)
+	}
+
+	number, err = tp.player.Back()
+	if err != nil || number != 0 {
+		t.Fatalf("Back() = %d, %v, want 0", number, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	recalled := tp.result()
+	if !reflect.DeepEqual(recalled, []int{0, 1, 0}) {
+		t.Errorf("recalled %v, want [0 1 0]", recalled)
+	}
+}
+
+func TestPlayerFollow(t *testing.T) {
+
+	tp := newTestPlayer(CueList{
+		Name: "Show",
+		Cues: []Cue{
+			{Name: "One", Follow: true, FollowTime: 0.005},
+			{Name: "Two", Delay: 0.005, Follow: true},
+			{Name: "Three"},
+		},
+	})
+
+	tp.player.Go()
+	time.Sleep(50 * time.Millisecond)
+
+	recalled := tp.result()
+	if !reflect.DeepEqual(recalled, []int{0, 1, 2}) {
+		t.Errorf("recalled %v, want [0 1 2]", recalled)
+	}
+	if tp.player.Current() != 2 {
+		t.Errorf("Current() = %d, want 2", tp.player.Current())
+	}
+}
+
+func TestPlayerGoDuringDelay(t *testing.T) {
+
+	tp := newTestPlayer(CueList{
+		Name: "Show",
+		Cues: []Cue{
+			{Name: "One", Delay: 1},
+			{Name: "Two"},
+		},
+	})
+
+	// The second GO abandons cue one while it is still waiting.
+	tp.player.Go()
+	tp.player.Go()
+	time.Sleep(20 * time.Millisecond)
+
+	recalled := tp.result()
+	if !reflect.DeepEqual(recalled, []int{1}) {
+		t.Errorf("recalled %v, want [1]", recalled)
+	}
+}
+
+func TestPlayerFollowWaitsForFade(t *testing.T) {
+
+	tp := newTestPlayer(CueList{
+		Name: "Show",
+		Cues: []Cue{
+			{Name: "One", Fade: 0.05, Follow: true},
+			{Name: "Two"},
+		},
+	})
+
+	// Cue two follows on once cue one's crossfade has finished.
+	tp.player.Go()
+	time.Sleep(20 * time.Millisecond)
+	if recalled := tp.result(); !reflect.DeepEqual(recalled, []int{0}) {
+		t.Errorf("recalled %v during the fade, want [0]", recalled)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if recalled := tp.result(); !reflect.DeepEqual(recalled, []int{0, 1}) {
+		t.Errorf("recalled %v after the fade, want [0 1]", recalled)
+	}
+}
+
+func TestCueCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command CueCommand
+		want    common.Command
+		wantErr bool
+	}{
+		{
+			name:    "start",
+			command: CueCommand{Sequence: 1, Action: "Start"},
+			want:    common.Command{Action: common.Start},
+		},
+		{
+			name:    "speed",
+			command: CueCommand{Sequence: 1, Action: "speed", Value: 7},
+			want:    common.Command{Action: common.UpdateSpeed, Args: []common.Arg{{Name: "Speed", Value: 7}}},
+		},
+		{
+			name:    "unknown",
+			command: CueCommand{Sequence: 1, Action: "jump"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.command.Command()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Command() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Command() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetID(t *testing.T) {
+	tests := []struct {
+		preset string
+		want   string
+	}{
+		{preset: "12", want: "12"},
+		{preset: " 3.5 ", want: "3.5"},
+		{preset: "2,5", want: "2.5"},
+		{preset: " 7, 6", want: "7.6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			if got := (Cue{Name: "Test", Preset: tt.preset}).PresetID(); got != tt.want {
+				t.Errorf("PresetID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyCueLists(t *testing.T) {
+
+	lists := []CueList{
+		{Name: "Show", Cues: []Cue{
+			{Name: "One", Commands: []CueCommand{{Sequence: 1, Action: "start"}}},
+			{Name: "Two"},
+		}},
+		{Name: "Empty"},
+	}
+
+	copied := CopyCueLists(lists)
+	if !reflect.DeepEqual(copied, lists) {
+		t.Fatalf("CopyCueLists() = %+v, want %+v", copied, lists)
+	}
+
+	// Editing the original doesn't change the copy.
+	lists[0].Cues[0].Fade = 2
+	lists[0].Cues[0].Commands[0].Action = "stop"
+	if copied[0].Cues[0].Fade != 0 || copied[0].Cues[0].Commands[0].Action != "start" {
+		t.Errorf("CopyCueLists() copy changed with the original %+v", copied[0].Cues[0])
+	}
+
+	if CopyCueLists(nil) != nil {
+		t.Errorf("CopyCueLists(nil) want nil")
+	}
+}
+
+func TestParseCueCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []CueCommand
+		wantErr bool
+	}{
+		{
+			name: "commands",
+			text: "1 start\n\n2 Speed 7\n 3 stop ",
+			want: []CueCommand{
+				{Sequence: 1, Action: "start"},
+				{Sequence: 2, Action: "speed", Value: 7},
+				{Sequence: 3, Action: "stop"},
+			},
+		},
+		{
+			name: "empty",
+			text: "",
+			want: []CueCommand{},
+		},
+		{
+			name:    "bad sequence",
+			text:    "one start",
+			wantErr: true,
+		},
+		{
+			name:    "bad action",
+			text:    "1 jump",
+			wantErr: true,
+		},
+		{
+			name:    "too many fields",
+			text:    "1 speed 7 8",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCueCommands(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCueCommands() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCueCommands() = %+v, want %+v", got, tt.want)
+			}
+			if !tt.wantErr {
+				again, _ := ParseCueCommands(FormatCueCommands(got))
+				if !reflect.DeepEqual(again, got) {
+					t.Errorf("FormatCueCommands() didn't read back, got %+v", again)
+				}
+			}
+		})
+	}
+}
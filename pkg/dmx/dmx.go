@@ -54,11 +54,16 @@ func NewDmXController() (*ft232.DMXController, *usbdmx.ControllerConfig, error)
 	// flickering in fixtures. Check the specification of your fixtures and controller
 	go func(c *ft232.DMXController) {
 		for {
-			if err := controller.Render(); err != nil {
+			// Build this frame from the output stage.
+			frame := GetFrame(time.Now())
+			for index, value := range frame {
+				c.SetChannel(int16(index+1), value)
+			}
+			if err := c.Render(); err != nil {
 				log.Fatalf("Failed to render output: %s", err)
 			}
 			// DMX refresh rate.
-			time.Sleep(30 * time.Millisecond)
+			time.Sleep(DMX_REFRESH)
 		}
 	}(&controller)
 
@@ -0,0 +1,492 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights dmx output stage, it holds the values requested for
+// every DMX channel and builds each frame sent to the dmx interface.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dmx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const DMX_CHANNELS = 512
+
+// DMX refresh rate.
+const DMX_REFRESH = 30 * time.Millisecond
+
+// Software strobe rates, a strobe speed of 0 flashes at the slowest rate and 255 at the fastest.
+const MIN_SOFTWARE_STROBE_HZ = 1.0
+const MAX_SOFTWARE_STROBE_HZ = 15.0
+
+type softwareStrobe struct {
+	Speed    int       // Strobe speed 0-255.
+	OffValue byte      // The value sent when the strobe is off, reversed dimmers are off at 255.
+	Start    time.Time // When the strobe started, so every channel in a fixture flashes together.
+}
+
+// The output level scales every dimmable channel, used to fade the lights between cues.
+const MAX_OUTPUT_LEVEL = 255
+
+// A crossfade blends the frame being sent when it started into the live frame.
+type crossfade struct {
+	From     [DMX_CHANNELS]byte // The frame when the crossfade started.
+	Start    time.Time
+	Duration time.Duration
+}
+
+// A blackout fades the dimmable channels to black, and back up again when it's released.
+type blackout struct {
+	From     int // Blackout level when the fade started, full is no blackout.
+	To       int
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Default merge priority for sequences and switches.
+const DEFAULT_PRIORITY = 0
+
+// A layer holds the values written by one source, a sequence or a switch.
+// Sources write into their own layer and the layers are merged on every frame.
+type layer struct {
+	Priority int              // Layers with a higher priority win, whatever the channel type.
+	Values   map[int16]byte   // Channel values written by this source.
+	Changed  map[int16]uint64 // When each value last changed, used for latest takes precedence.
+}
+
+var outputLock sync.Mutex
+var outputLayers = make(map[string]*layer)
+var outputPriorities = make(map[string]int)
+var outputChanges uint64
+var outputIntensity = make(map[int16]byte)
+var outputStrobes = make(map[int16]softwareStrobe)
+var outputDimmable = make(map[int16]byte)
+var outputLevel = MAX_OUTPUT_LEVEL
+var outputFadeable = make(map[int16]bool)
+var outputCrossfade *crossfade
+var outputFrozen *[DMX_CHANNELS]byte // The frame held by a freeze.
+var outputSubmasters = make(map[int16]int)
+var outputFrozenAt time.Time
+var outputBlackout *blackout
+
+// SequenceSource names the output layer used by a sequence.
+func SequenceSource(sequenceNumber int) string {
+	return fmt.Sprintf("sequence%d", sequenceNumber)
+}
+
+// SwitchSource names the output layer used by a switch.
+func SwitchSource(switchNumber int) string {
+	return fmt.Sprintf("switch%d", switchNumber)
+}
+
+// SetSourcePriority sets the merge priority for a source.
+// Where sources share a channel the one with the highest priority wins.
+func SetSourcePriority(source string, priority int) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	outputPriorities[source] = priority
+	if layer, ok := outputLayers[source]; ok {
+		layer.Priority = priority
+	}
+}
+
+// SourcePriority returns the merge priority set for a source.
+func SourcePriority(source string) int {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	return outputPriorities[source]
+}
+
+// SetIntensityChannel marks a channel as an intensity channel, a master or dimmer.
+// Intensity channels merge highest takes precedence, offValue is the value which turns the
+// channel off, 255 for reversed dimmers.
+func SetIntensityChannel(index int16, offValue byte) {
+	if index < 1 || index > DMX_CHANNELS {
+		return
+	}
+	outputLock.Lock()
+	outputIntensity[index] = offValue
+	outputLock.Unlock()
+}
+
+// ClearIntensityChannels forgets all the intensity channels, used before the fixtures are reloaded.
+func ClearIntensityChannels() {
+	outputLock.Lock()
+	outputIntensity = make(map[int16]byte)
+	outputLock.Unlock()
+}
+
+// SetDimmableChannel marks a channel which is dimmed by the output level, the master, dimmer or
+// color channels of a fixture. offValue is the value which turns the channel off.
+func SetDimmableChannel(index int16, offValue byte) {
+	if index < 1 || index > DMX_CHANNELS {
+		return
+	}
+	outputLock.Lock()
+	outputDimmable[index] = offValue
+	outputLock.Unlock()
+}
+
+// ClearDimmableChannels forgets all the dimmable channels, used before the fixtures are reloaded.
+func ClearDimmableChannels() {
+	outputLock.Lock()
+	outputDimmable = make(map[int16]byte)
+	outputLock.Unlock()
+}
+
+// SetFadeChannel marks a channel which is blended during a crossfade, the intensity, color,
+// pan and tilt channels. Every other channel switches to its new value at the end of the crossfade.
+func SetFadeChannel(index int16) {
+	if index < 1 || index > DMX_CHANNELS {
+		return
+	}
+	outputLock.Lock()
+	outputFadeable[index] = true
+	outputLock.Unlock()
+}
+
+// ClearFadeChannels forgets all the crossfade channels, used before the fixtures are reloaded.
+func ClearFadeChannels() {
+	outputLock.Lock()
+	outputFadeable = make(map[int16]bool)
+	outputLock.Unlock()
+}
+
+// StartCrossfade fades from what is being sent now to whatever the sources send over the given time.
+// A time of zero snaps straight to the new values.
+func StartCrossfade(duration time.Duration, now time.Time) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	// Starting during a crossfade carries on from where it had got to.
+	from := applyCrossfade(mergeLayers(), now)
+	if duration <= 0 {
+		outputCrossfade = nil
+		return
+	}
+	outputCrossfade = &crossfade{From: from, Start: now, Duration: duration}
+}
+
+// SetOutputLevel sets the level of all the dimmable channels, 0 is dark and 255 is full.
+func SetOutputLevel(level int) {
+	if level < 0 {
+		level = 0
+	}
+	if level > MAX_OUTPUT_LEVEL {
+		level = MAX_OUTPUT_LEVEL
+	}
+	outputLock.Lock()
+	outputLevel = level
+	outputLock.Unlock()
+}
+
+// OutputLevel returns the level of the dimmable channels.
+func OutputLevel() int {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	return outputLevel
+}
+
+// StartBlackout fades every dimmable channel to black over the given time, a time of zero is a dead blackout.
+// Starting during a release fades down from where the release had got to.
+func StartBlackout(duration time.Duration, now time.Time) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	startBlackoutFade(0, duration, now)
+}
+
+// ReleaseBlackout fades the dimmable channels back up over the given time, a time of zero snaps back.
+func ReleaseBlackout(duration time.Duration, now time.Time) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if outputBlackout == nil {
+		return
+	}
+	startBlackoutFade(MAX_OUTPUT_LEVEL, duration, now)
+}
+
+// BlackoutFading returns true while a blackout is still fading down to black.
+func BlackoutFading(now time.Time) bool {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	return outputBlackout != nil && outputBlackout.To == 0 && blackoutLevel(now) > 0
+}
+
+// startBlackoutFade starts the blackout fading from its level now. Called with the output lock held.
+func startBlackoutFade(to int, duration time.Duration, now time.Time) {
+	outputBlackout = &blackout{From: blackoutLevel(now), To: to, Start: now, Duration: duration}
+}
+
+// blackoutLevel returns the level the blackout leaves the dimmable channels at, full when there's no blackout.
+// Called with the output lock held.
+func blackoutLevel(now time.Time) int {
+	if outputBlackout == nil {
+		return MAX_OUTPUT_LEVEL
+	}
+	elapsed := now.Sub(outputBlackout.Start)
+	if elapsed >= outputBlackout.Duration {
+		level := outputBlackout.To
+		// Fully released, so the blackout is over.
+		if level == MAX_OUTPUT_LEVEL {
+			outputBlackout = nil
+		}
+		return level
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return outputBlackout.From + (outputBlackout.To-outputBlackout.From)*int(elapsed)/int(outputBlackout.Duration)
+}
+
+// SetSubmasterLevels sets the submaster level of each dimmable channel, 0 is dark and 255 is full.
+// Submasters multiply with the output level, channels without a level are left at full.
+func SetSubmasterLevels(levels map[int16]int) {
+	submasters := make(map[int16]int)
+	for index, level := range levels {
+		if index < 1 || index > DMX_CHANNELS || level >= MAX_OUTPUT_LEVEL {
+			continue
+		}
+		if level < 0 {
+			level = 0
+		}
+		submasters[index] = level
+	}
+	outputLock.Lock()
+	outputSubmasters = submasters
+	outputLock.Unlock()
+}
+
+// SetChannel records the value a source requests for a DMX channel, channels are numbered from 1 to 512.
+// The merged value is sent to the dmx interface on the next frame.
+func SetChannel(source string, index int16, data byte) {
+	if index < 1 || index > DMX_CHANNELS {
+		return
+	}
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	sourceLayer, ok := outputLayers[source]
+	if !ok {
+		sourceLayer = &layer{
+			Priority: outputPriorities[source],
+			Values:   make(map[int16]byte),
+			Changed:  make(map[int16]uint64),
+		}
+		outputLayers[source] = sourceLayer
+	}
+
+	// Only a change counts as the latest value, so a sequence repeating
+	// the same value on every step doesn't take a channel back from a switch.
+	if value, set := sourceLayer.Values[index]; !set || value != data {
+		outputChanges++
+		sourceLayer.Changed[index] = outputChanges
+	}
+	sourceLayer.Values[index] = data
+}
+
+// ReleaseSource removes a source's layer so it no longer takes part in the merge.
+func ReleaseSource(source string) {
+	outputLock.Lock()
+	delete(outputLayers, source)
+	outputLock.Unlock()
+}
+
+// StartSoftwareStrobe flashes a channel at a rate set by the strobe speed.
+// Used for fixtures that don't have a strobe channel of their own.
+func StartSoftwareStrobe(index int16, speed int, offValue byte, start time.Time) {
+	if index < 1 || index > DMX_CHANNELS {
+		return
+	}
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	// Keep the original start time so a speed change doesn't restart the flash.
+	if strobe, running := outputStrobes[index]; running {
+		start = strobe.Start
+	}
+	outputStrobes[index] = softwareStrobe{Speed: speed, OffValue: offValue, Start: start}
+}
+
+// StopSoftwareStrobe stops a channel flashing.
+func StopSoftwareStrobe(index int16) {
+	outputLock.Lock()
+	delete(outputStrobes, index)
+	outputLock.Unlock()
+}
+
+// FreezeOutput holds the frame being sent now until the output is released.
+// The sources can carry on writing to their layers while the frame is held.
+func FreezeOutput(now time.Time) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if outputFrozen != nil {
+		return
+	}
+	frame := buildFrame(now)
+	outputFrozen = &frame
+	outputFrozenAt = now
+}
+
+// ReleaseOutput goes back to sending the merged layers. Crossfades and software strobes
+// carry on from where they were held.
+func ReleaseOutput(now time.Time) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if outputFrozen == nil {
+		return
+	}
+	held := now.Sub(outputFrozenAt)
+	if outputCrossfade != nil {
+		outputCrossfade.Start = outputCrossfade.Start.Add(held)
+	}
+	if outputBlackout != nil {
+		outputBlackout.Start = outputBlackout.Start.Add(held)
+	}
+	for index, strobe := range outputStrobes {
+		strobe.Start = strobe.Start.Add(held)
+		outputStrobes[index] = strobe
+	}
+	outputFrozen = nil
+}
+
+// GetFrame builds the frame to send at the given time by merging the layers.
+func GetFrame(now time.Time) [DMX_CHANNELS]byte {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	if outputFrozen != nil {
+		return *outputFrozen
+	}
+	return buildFrame(now)
+}
+
+// buildFrame merges the layers then applies any crossfade, the output level, the blackout, the submasters and the strobes.
+// Called with the output lock held.
+func buildFrame(now time.Time) [DMX_CHANNELS]byte {
+	frame := applyCrossfade(mergeLayers(), now)
+	outputBlackoutLevel := blackoutLevel(now)
+	for index, offValue := range outputDimmable {
+		level := outputLevel * outputBlackoutLevel / MAX_OUTPUT_LEVEL
+		if submaster, ok := outputSubmasters[index]; ok {
+			level = level * submaster / MAX_OUTPUT_LEVEL
+		}
+		if level < MAX_OUTPUT_LEVEL {
+			frame[index-1] = scaleIntensity(frame[index-1], offValue, level)
+		}
+	}
+	for index, strobe := range outputStrobes {
+		if !strobeIsOn(strobe, now) {
+			frame[index-1] = strobe.OffValue
+		}
+	}
+	return frame
+}
+
+// mergeLayers works out the value for each channel from the layers that have written to it.
+// The highest priority wins. At the same priority intensity channels take the brightest value
+// and every other channel takes the value that changed last.
+func mergeLayers() [DMX_CHANNELS]byte {
+	var frame [DMX_CHANNELS]byte
+	var set [DMX_CHANNELS]bool
+	var priority [DMX_CHANNELS]int
+	var changed [DMX_CHANNELS]uint64
+
+	for _, sourceLayer := range outputLayers {
+		for index, value := range sourceLayer.Values {
+			channel := index - 1
+			if set[channel] && sourceLayer.Priority < priority[channel] {
+				continue
+			}
+			if set[channel] && sourceLayer.Priority == priority[channel] {
+				if offValue, isIntensity := outputIntensity[index]; isIntensity {
+					if Intensity(value, offValue) <= Intensity(frame[channel], offValue) {
+						continue
+					}
+				} else if sourceLayer.Changed[index] <= changed[channel] {
+					continue
+				}
+			}
+			frame[channel] = value
+			set[channel] = true
+			priority[channel] = sourceLayer.Priority
+			changed[channel] = sourceLayer.Changed[index]
+		}
+	}
+	return frame
+}
+
+// Intensity returns how bright a value is, reversed dimmers get brighter as the value falls.
+func Intensity(value byte, offValue byte) int {
+	if offValue > value {
+		return int(offValue) - int(value)
+	}
+	return int(value) - int(offValue)
+}
+
+// applyCrossfade blends the merged frame with the frame the crossfade started from.
+// Called with the output lock held.
+func applyCrossfade(frame [DMX_CHANNELS]byte, now time.Time) [DMX_CHANNELS]byte {
+	if outputCrossfade == nil {
+		return frame
+	}
+	elapsed := now.Sub(outputCrossfade.Start)
+	if elapsed >= outputCrossfade.Duration {
+		outputCrossfade = nil
+		return frame
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	progress := float64(elapsed) / float64(outputCrossfade.Duration)
+	for channel := range frame {
+		from := float64(outputCrossfade.From[channel])
+		if outputFadeable[int16(channel+1)] {
+			to := float64(frame[channel])
+			frame[channel] = byte(from + (to-from)*progress)
+		} else {
+			frame[channel] = byte(from)
+		}
+	}
+	return frame
+}
+
+// scaleIntensity dims a value towards its off value by the given level.
+func scaleIntensity(value byte, offValue byte, level int) byte {
+	intensity := Intensity(value, offValue) * level / MAX_OUTPUT_LEVEL
+	if offValue > value {
+		return offValue - byte(intensity)
+	}
+	return offValue + byte(intensity)
+}
+
+// StrobePeriod returns the time for one on and off flash at the given strobe speed.
+func StrobePeriod(speed int) time.Duration {
+	if speed < 0 {
+		speed = 0
+	}
+	if speed > 255 {
+		speed = 255
+	}
+	hz := MIN_SOFTWARE_STROBE_HZ + (MAX_SOFTWARE_STROBE_HZ-MIN_SOFTWARE_STROBE_HZ)*float64(speed)/255
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// strobeIsOn is true for the first half of each strobe period.
+func strobeIsOn(strobe softwareStrobe, now time.Time) bool {
+	period := StrobePeriod(strobe.Speed)
+	elapsed := now.Sub(strobe.Start) % period
+	return elapsed < period/2
+}
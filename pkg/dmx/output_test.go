@@ -0,0 +1,476 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights dmx output stage test code.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dmx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStrobePeriod(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		speed int
+		want  time.Duration
+	}{
+		{
+			name:  "slowest",
+			speed: 0,
+			want:  time.Second,
+		},
+		{
+			name:  "fastest",
+			speed: 255,
+			want:  time.Second / 15,
+		},
+		{
+			name:  "out of range",
+			speed: 1000,
+			want:  time.Second / 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrobePeriod(tt.speed); got != tt.want {
+				t.Errorf("StrobePeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFrame(t *testing.T) {
+
+	start := time.Now()
+
+	SetChannel("test", 1, 200)
+	SetChannel("test", 2, 100)
+	SetChannel("test", 3, 0)
+	defer ReleaseSource("test")
+	StartSoftwareStrobe(2, 0, 0, start)
+	StartSoftwareStrobe(3, 0, 255, start)
+	defer StopSoftwareStrobe(2)
+	defer StopSoftwareStrobe(3)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []byte
+	}{
+		{
+			name: "strobe on",
+			now:  start.Add(100 * time.Millisecond),
+			want: []byte{200, 100, 0},
+		},
+		{
+			name: "strobe off",
+			now:  start.Add(600 * time.Millisecond),
+			want: []byte{200, 0, 255},
+		},
+		{
+			name: "strobe on again",
+			now:  start.Add(1100 * time.Millisecond),
+			want: []byte{200, 100, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := GetFrame(tt.now)
+			for index, want := range tt.want {
+				if frame[index] != want {
+					t.Errorf("GetFrame() channel %d = %v, want %v", index+1, frame[index], want)
+				}
+			}
+		})
+	}
+
+	// Out of range channels are ignored.
+	SetChannel("test", 0, 1)
+	SetChannel("test", DMX_CHANNELS+1, 1)
+}
+
+func TestMergeLayers(t *testing.T) {
+
+	// Channel 1 is a dimmer, channel 2 a reversed dimmer and channel 3 a colour.
+	SetIntensityChannel(1, 0)
+	SetIntensityChannel(2, 255)
+	defer ClearIntensityChannels()
+
+	type write struct {
+		source string
+		index  int16
+		data   byte
+	}
+
+	tests := []struct {
+		name       string
+		priorities map[string]int
+		writes     []write
+		want       []byte
+	}{
+		{
+			name: "dimmer highest takes precedence",
+			writes: []write{
+				{source: "sequence0", index: 1, data: 200},
+				{source: "switch1", index: 1, data: 100},
+			},
+			want: []byte{200, 0, 0},
+		},
+		{
+			name: "reversed dimmer lowest value is brightest",
+			writes: []write{
+				{source: "sequence0", index: 2, data: 50},
+				{source: "switch1", index: 2, data: 255},
+			},
+			want: []byte{0, 50, 0},
+		},
+		{
+			name: "colour latest takes precedence",
+			writes: []write{
+				{source: "sequence0", index: 3, data: 10},
+				{source: "switch1", index: 3, data: 20},
+			},
+			want: []byte{0, 0, 20},
+		},
+		{
+			name: "repeating the same value is not a change",
+			writes: []write{
+				{source: "sequence0", index: 3, data: 10},
+				{source: "switch1", index: 3, data: 20},
+				{source: "sequence0", index: 3, data: 10},
+			},
+			want: []byte{0, 0, 20},
+		},
+		{
+			name: "colour change takes the channel back",
+			writes: []write{
+				{source: "sequence0", index: 3, data: 10},
+				{source: "switch1", index: 3, data: 20},
+				{source: "sequence0", index: 3, data: 30},
+			},
+			want: []byte{0, 0, 30},
+		},
+		{
+			name:       "higher priority wins even when dimmer",
+			priorities: map[string]int{"switch1": 1},
+			writes: []write{
+				{source: "sequence0", index: 1, data: 200},
+				{source: "switch1", index: 1, data: 100},
+				{source: "sequence0", index: 3, data: 10},
+				{source: "switch1", index: 3, data: 20},
+				{source: "sequence0", index: 3, data: 30},
+			},
+			want: []byte{100, 0, 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for source, priority := range tt.priorities {
+				SetSourcePriority(source, priority)
+			}
+			for _, w := range tt.writes {
+				SetChannel(w.source, w.index, w.data)
+			}
+			frame := GetFrame(time.Now())
+			for index, want := range tt.want {
+				if frame[index] != want {
+					t.Errorf("GetFrame() channel %d = %v, want %v", index+1, frame[index], want)
+				}
+			}
+			for _, w := range tt.writes {
+				ReleaseSource(w.source)
+			}
+			for source := range tt.priorities {
+				SetSourcePriority(source, DEFAULT_PRIORITY)
+			}
+		})
+	}
+}
+
+func TestOutputLevel(t *testing.T) {
+
+	SetChannel("test", 1, 200)
+	SetChannel("test", 2, 55)
+	SetChannel("test", 3, 100)
+	defer ReleaseSource("test")
+	SetDimmableChannel(1, 0)
+	SetDimmableChannel(2, 255)
+	defer ClearDimmableChannels()
+	defer SetOutputLevel(MAX_OUTPUT_LEVEL)
+
+	tests := []struct {
+		name  string
+		level int
+		want  []byte
+	}{
+		{
+			name:  "full",
+			level: MAX_OUTPUT_LEVEL,
+			want:  []byte{200, 55, 100},
+		},
+		{
+			name:  "half, reversed dimmer dims upwards and the undimmable channel is left alone",
+			level: 128,
+			want:  []byte{100, 155, 100},
+		},
+		{
+			name:  "dark",
+			level: 0,
+			want:  []byte{0, 255, 100},
+		},
+		{
+			name:  "out of range is full",
+			level: 300,
+			want:  []byte{200, 55, 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOutputLevel(tt.level)
+			frame := GetFrame(time.Now())
+			for index, want := range tt.want {
+				if frame[index] != want {
+					t.Errorf("GetFrame() channel %d = %v, want %v", index+1, frame[index], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmasterLevels(t *testing.T) {
+
+	SetChannel("test", 1, 200)
+	SetChannel("test", 2, 55)
+	SetChannel("test", 3, 100)
+	defer ReleaseSource("test")
+	SetDimmableChannel(1, 0)
+	SetDimmableChannel(2, 255)
+	SetDimmableChannel(3, 0)
+	defer ClearDimmableChannels()
+	defer SetOutputLevel(MAX_OUTPUT_LEVEL)
+	defer SetSubmasterLevels(nil)
+
+	tests := []struct {
+		name   string
+		level  int
+		levels map[int16]int
+		want   []byte
+	}{
+		{
+			name:   "no submasters",
+			level:  MAX_OUTPUT_LEVEL,
+			levels: nil,
+			want:   []byte{200, 55, 100},
+		},
+		{
+			name:   "half on a dimmer and a reversed dimmer, the channel without a level is left at full",
+			level:  MAX_OUTPUT_LEVEL,
+			levels: map[int16]int{1: 128, 2: 128},
+			want:   []byte{100, 155, 100},
+		},
+		{
+			name:   "submaster multiplies with the output level",
+			level:  128,
+			levels: map[int16]int{1: 128},
+			want:   []byte{50, 155, 50},
+		},
+		{
+			name:   "dark",
+			level:  MAX_OUTPUT_LEVEL,
+			levels: map[int16]int{1: 0, 2: 0, 3: -10},
+			want:   []byte{0, 255, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOutputLevel(tt.level)
+			SetSubmasterLevels(tt.levels)
+			frame := GetFrame(time.Now())
+			for index, want := range tt.want {
+				if frame[index] != want {
+					t.Errorf("GetFrame() channel %d = %v, want %v", index+1, frame[index], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCrossfade(t *testing.T) {
+
+	start := time.Now()
+
+	SetChannel("test", 1, 0)
+	SetChannel("test", 2, 200)
+	SetChannel("test", 3, 1)
+	defer ReleaseSource("test")
+	SetFadeChannel(1)
+	SetFadeChannel(2)
+	defer ClearFadeChannels()
+
+	// Fade to the new look over a second.
+	StartCrossfade(time.Second, start)
+	SetChannel("test", 1, 200)
+	SetChannel("test", 2, 0)
+	SetChannel("test", 3, 5)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []byte
+	}{
+		{
+			name: "start",
+			now:  start,
+			want: []byte{0, 200, 1},
+		},
+		{
+			name: "halfway, the gobo channel waits for the end",
+			now:  start.Add(500 * time.Millisecond),
+			want: []byte{100, 100, 1},
+		},
+		{
+			name: "end",
+			now:  start.Add(time.Second),
+			want: []byte{200, 0, 5},
+		},
+		{
+			name: "after the end the live values are sent",
+			now:  start.Add(100 * time.Millisecond),
+			want: []byte{200, 0, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := GetFrame(tt.now)
+			for index, want := range tt.want {
+				if frame[index] != want {
+					t.Errorf("GetFrame() channel %d = %v, want %v", index+1, frame[index], want)
+				}
+			}
+		})
+	}
+
+	// A crossfade of no time snaps.
+	StartCrossfade(0, start)
+	SetChannel("test", 1, 50)
+	if frame := GetFrame(start); frame[0] != 50 {
+		t.Errorf("GetFrame() after snap channel 1 = %v, want 50", frame[0])
+	}
+}
+
+func TestFreezeOutput(t *testing.T) {
+
+	start := time.Now()
+
+	SetChannel("test", 1, 0)
+	defer ReleaseSource("test")
+	SetFadeChannel(1)
+	defer ClearFadeChannels()
+
+	// Freeze half way through a crossfade.
+	StartCrossfade(time.Second, start)
+	SetChannel("test", 1, 200)
+	FreezeOutput(start.Add(500 * time.Millisecond))
+
+	// The sources carry on but the frame is held.
+	SetChannel("test", 1, 250)
+	if frame := GetFrame(start.Add(5 * time.Second)); frame[0] != 100 {
+		t.Errorf("GetFrame() frozen = %v, want 100", frame[0])
+	}
+
+	// After ten seconds frozen the crossfade carries on from half way.
+	ReleaseOutput(start.Add(10500 * time.Millisecond))
+	if frame := GetFrame(start.Add(10750 * time.Millisecond)); frame[0] != 187 {
+		t.Errorf("GetFrame() released = %v, want 187", frame[0])
+	}
+	StartCrossfade(0, start)
+}
+
+func TestBlackout(t *testing.T) {
+
+	start := time.Now()
+
+	SetChannel("test", 1, 200)
+	SetChannel("test", 2, 55)
+	defer ReleaseSource("test")
+	SetDimmableChannel(1, 0)
+	SetDimmableChannel(2, 255)
+	defer ClearDimmableChannels()
+
+	tests := []struct {
+		name   string
+		action func()
+		at     time.Duration
+		want   []byte
+	}{
+		{
+			name:   "half way through a one second fade to black, a reversed dimmer fades up to its off value",
+			action: func() { StartBlackout(time.Second, start) },
+			at:     500 * time.Millisecond,
+			want:   []byte{100, 155},
+		},
+		{
+			name:   "black at the end of the fade",
+			action: func() {},
+			at:     time.Second,
+			want:   []byte{0, 255},
+		},
+		{
+			name:   "half way through a two second release",
+			action: func() { ReleaseBlackout(2*time.Second, start.Add(2*time.Second)) },
+			at:     3 * time.Second,
+			want:   []byte{99, 156},
+		},
+		{
+			name:   "a blackout during the release fades down from where it had got to",
+			action: func() { StartBlackout(time.Second, start.Add(3*time.Second)) },
+			at:     3500 * time.Millisecond,
+			want:   []byte{50, 205},
+		},
+		{
+			name:   "a dead blackout is black straight away",
+			action: func() { StartBlackout(0, start.Add(4*time.Second)) },
+			at:     4 * time.Second,
+			want:   []byte{0, 255},
+		},
+		{
+			name:   "released",
+			action: func() { ReleaseBlackout(0, start.Add(5*time.Second)) },
+			at:     5 * time.Second,
+			want:   []byte{200, 55},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action()
+			frame := GetFrame(start.Add(tt.at))
+			if got := frame[0:2]; got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Errorf("GetFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if BlackoutFading(start.Add(6 * time.Second)) {
+		t.Errorf("BlackoutFading() after the release = true, want false")
+	}
+	StartBlackout(time.Second, start)
+	if !BlackoutFading(start.Add(500 * time.Millisecond)) {
+		t.Errorf("BlackoutFading() half way down = false, want true")
+	}
+	ReleaseBlackout(0, start)
+	GetFrame(start)
+}
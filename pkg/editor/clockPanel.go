@@ -0,0 +1,174 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the master clock panel, used to set the clock's tempo and source
+// and the rate each sequence follows it at.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package editor
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dhowlett99/dmxlights/pkg/clock"
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/pad"
+)
+
+// Sequences not following the clock run at their own speed.
+const CLOCK_OWN_SPEED = "Own speed"
+
+// Shown when MIDI clock isn't being read from any port.
+const CLOCK_NO_MIDI_INPUT = "No MIDI input"
+
+func NewClockPanel(w fyne.Window, sequences []*common.Sequence, commandChannels []chan common.Command, updateChannels []chan common.Sequence) (modal *widget.PopUp) {
+
+	if debug {
+		fmt.Printf("NewClockPanel\n")
+	}
+
+	// Title.
+	title := widget.NewLabel("Master Clock")
+	title.TextStyle = fyne.TextStyle{
+		Bold: true,
+	}
+
+	// The tempo, set here for the internal clock, or shown as it's followed.
+	bpmLabel := widget.NewLabel(fmt.Sprintf("%.1f BPM", clock.BPM()))
+	bpmSlider := widget.NewSlider(clock.MIN_BPM, clock.MAX_BPM)
+	bpmSlider.Step = 1
+	bpmSlider.Value = clock.BPM()
+	bpmSlider.OnChanged = func(value float64) {
+		clock.SetBPM(value)
+		bpmLabel.SetText(fmt.Sprintf("%.1f BPM", value))
+	}
+
+	// Where the clock gets its tempo from. Selected is set directly, SetSelected would
+	// call OnChanged and changing the source starts the taps and MIDI clock again.
+	sourceSelect := widget.NewSelect(clock.Sources, func(value string) {
+		clock.SetSource(value)
+	})
+	sourceSelect.Selected = clock.Source()
+
+	// The MIDI port MIDI clock is read from.
+	midiInputs := []string{CLOCK_NO_MIDI_INPUT}
+	ports, err := pad.ClockInputs()
+	if err != nil {
+		fmt.Printf("error: %s\n", err.Error())
+	}
+	midiInputs = append(midiInputs, ports...)
+	midiInputSelect := widget.NewSelect(midiInputs, nil)
+	midiInputSelect.Selected = pad.ClockInput()
+	if midiInputSelect.Selected == "" {
+		midiInputSelect.Selected = CLOCK_NO_MIDI_INPUT
+	}
+	midiInputSelect.OnChanged = func(value string) {
+		if value == CLOCK_NO_MIDI_INPUT {
+			value = ""
+		}
+		err := pad.SetClockInput(value)
+		if err != nil {
+			fmt.Printf("error: %s\n", err.Error())
+			midiInputSelect.Selected = CLOCK_NO_MIDI_INPUT
+			midiInputSelect.Refresh()
+		}
+	}
+
+	// Each tap is a downbeat, the taps set the tempo.
+	buttonTap := widget.NewButton("Tap", func() {
+		clock.Tap(time.Now())
+		sourceSelect.Selected = clock.Source()
+		sourceSelect.Refresh()
+		bpmSlider.SetValue(clock.BPM())
+		bpmLabel.SetText(fmt.Sprintf("%.1f BPM", clock.BPM()))
+	})
+
+	// Each sequence can follow the clock at its own ratio.
+	ratioLabels := []string{CLOCK_OWN_SPEED}
+	for _, ratio := range clock.Ratios {
+		ratioLabels = append(ratioLabels, ratio.Label)
+	}
+	sequenceRatios := container.NewVBox()
+	for _, sequence := range sequences {
+		if sequence.Type == "switch" {
+			continue
+		}
+		sequenceNumber := sequence.Number
+
+		// Our copy of the sequence may be out of date, ask the sequence how it's following the clock.
+		sequences[sequenceNumber] = common.RefreshSequence(sequenceNumber, commandChannels, updateChannels)
+
+		// Selected is set before OnChanged is wired up, so opening the panel doesn't send anything.
+		ratioSelect := widget.NewSelect(ratioLabels, nil)
+		ratioSelect.Selected = clockRatioLabel(*sequences[sequenceNumber])
+		ratioSelect.OnChanged = func(value string) {
+			cmd := common.Command{
+				Action: common.UpdateClock,
+				Args: []common.Arg{
+					{Name: "ClockSync", Value: false},
+					{Name: "ClockMultiplier", Value: 1},
+					{Name: "ClockDivider", Value: 1},
+				},
+			}
+			for _, ratio := range clock.Ratios {
+				if ratio.Label == value {
+					cmd.Args[0].Value = true
+					cmd.Args[1].Value = ratio.Multiplier
+					cmd.Args[2].Value = ratio.Divider
+				}
+			}
+			common.SendCommandToSequence(sequenceNumber, cmd, commandChannels)
+
+			// Keep our copy of the sequence up to date.
+			sequences[sequenceNumber] = common.RefreshSequence(sequenceNumber, commandChannels, updateChannels)
+		}
+		sequenceRatios.Add(container.NewGridWithColumns(2, widget.NewLabel(sequence.Name), ratioSelect))
+	}
+
+	buttonClose := widget.NewButton("Close", func() {
+		modal.Hide()
+	})
+
+	// Layout of the clock panel.
+	modal = widget.NewModalPopUp(
+		container.NewVBox(
+			title,
+			container.NewGridWithColumns(3, sourceSelect, bpmLabel, buttonTap),
+			container.NewGridWithColumns(2, widget.NewLabel("MIDI clock from"), midiInputSelect),
+			bpmSlider,
+			widget.NewLabel("Sequences step every"),
+			sequenceRatios,
+			widget.NewLabel("Save a preset to record the sequence ratios with it."),
+			container.NewHBox(layout.NewSpacer(), buttonClose),
+		),
+		w.Canvas(),
+	)
+	return modal
+}
+
+// clockRatioLabel returns the label for the ratio a sequence is following the clock at.
+func clockRatioLabel(sequence common.Sequence) string {
+	if sequence.ClockSync {
+		for _, ratio := range clock.Ratios {
+			if ratio.Multiplier == sequence.ClockMultiplier && ratio.Divider == sequence.ClockDivider {
+				return ratio.Label
+			}
+		}
+	}
+	return CLOCK_OWN_SPEED
+}
@@ -0,0 +1,290 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights cues panel, it edits the project's cue lists and
+// has GO and BACK buttons to step through them.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+func NewCuesPanel(w fyne.Window, cuePlayer *cues.Player, fixtures *fixture.Fixtures) (modal *widget.PopUp) {
+
+	if debug {
+		fmt.Printf("NewCuesPanel\n")
+	}
+
+	// Title.
+	title := widget.NewLabel("Cue Lists")
+	title.TextStyle = fyne.TextStyle{
+		Bold: true,
+	}
+
+	status := widget.NewLabel("")
+
+	// Start with the cue list being played.
+	listNumber := findCueList(cuePlayer.List().Name, fixtures)
+	selectedCue := -1
+
+	// The fields of the selected cue.
+	nameEntry := widget.NewEntry()
+	presetEntry := widget.NewEntry()
+	presetEntry.SetPlaceHolder("Preset ID")
+	delayEntry := widget.NewEntry()
+	fadeEntry := widget.NewEntry()
+	followCheck := widget.NewCheck("Follow", nil)
+	followTimeEntry := widget.NewEntry()
+	commandsEntry := widget.NewMultiLineEntry()
+	commandsEntry.SetPlaceHolder("sequence action value\n1 start\n2 speed 7")
+
+	// The cues in the selected list.
+	cueList := widget.NewList(
+		func() int {
+			if listNumber < 0 {
+				return 0
+			}
+			return len(fixtures.CueLists[listNumber].Cues)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			cue := fixtures.CueLists[listNumber].Cues[id]
+			marker := "  "
+			if fixtures.CueLists[listNumber].Name == cuePlayer.List().Name && id == cuePlayer.Current() {
+				marker = "> "
+			}
+			item.(*widget.Label).SetText(fmt.Sprintf("%s%d %s", marker, id+1, cue.Name))
+		},
+	)
+	cueList.OnSelected = func(id widget.ListItemID) {
+		selectedCue = id
+		cue := fixtures.CueLists[listNumber].Cues[id]
+		nameEntry.SetText(cue.Name)
+		presetEntry.SetText(cue.Preset)
+		delayEntry.SetText(formatSeconds(cue.Delay))
+		fadeEntry.SetText(formatSeconds(cue.Fade))
+		followCheck.SetChecked(cue.Follow)
+		followTimeEntry.SetText(formatSeconds(cue.FollowTime))
+		commandsEntry.SetText(cues.FormatCueCommands(cue.Commands))
+	}
+
+	// Pass any edit on to the player if it's playing this list.
+	listChanged := func() {
+		cueList.Refresh()
+		if fixtures.CueLists[listNumber].Name == cuePlayer.List().Name {
+			cuePlayer.UpdateList(fixtures.CueLists[listNumber])
+		}
+	}
+
+	// Choosing a list makes it the one GO and BACK play.
+	listSelect := widget.NewSelect(cueListNames(fixtures), func(name string) {
+		listNumber = findCueList(name, fixtures)
+		selectedCue = -1
+		cueList.UnselectAll()
+		if listNumber >= 0 && name != cuePlayer.List().Name {
+			cuePlayer.SetList(fixtures.CueLists[listNumber])
+		}
+		cueList.Refresh()
+	})
+	listSelect.PlaceHolder = "Select Cue List"
+	if listNumber >= 0 {
+		listSelect.Selected = fixtures.CueLists[listNumber].Name
+	}
+
+	newListEntry := widget.NewEntry()
+	newListEntry.SetPlaceHolder("New list name")
+	buttonAddList := widget.NewButton("Add List", func() {
+		name := strings.TrimSpace(newListEntry.Text)
+		if name == "" || findCueList(name, fixtures) >= 0 {
+			status.SetText("Cue list needs a new name")
+			return
+		}
+		fixtures.CueLists = append(fixtures.CueLists, cues.CueList{Name: name, Cues: []cues.Cue{}})
+		newListEntry.SetText("")
+		listSelect.Options = cueListNames(fixtures)
+		listSelect.SetSelected(name)
+	})
+
+	// Read the cue fields, reports any that don't make sense.
+	readCue := func() (cues.Cue, error) {
+		cue := cues.Cue{
+			Name:   strings.TrimSpace(nameEntry.Text),
+			Preset: strings.TrimSpace(presetEntry.Text),
+			Follow: followCheck.Checked,
+		}
+		var err error
+		if cue.Delay, err = parseSeconds("Delay", delayEntry.Text); err != nil {
+			return cue, err
+		}
+		if cue.Fade, err = parseSeconds("Fade", fadeEntry.Text); err != nil {
+			return cue, err
+		}
+		if cue.FollowTime, err = parseSeconds("Follow Time", followTimeEntry.Text); err != nil {
+			return cue, err
+		}
+		commands, err := cues.ParseCueCommands(commandsEntry.Text)
+		if err != nil {
+			return cue, fmt.Errorf("commands %w", err)
+		}
+		if len(commands) > 0 {
+			cue.Commands = commands
+		}
+		return cue, nil
+	}
+
+	buttonAddCue := widget.NewButton("Add Cue", func() {
+		if listNumber < 0 {
+			status.SetText("Select a cue list first")
+			return
+		}
+		cue, err := readCue()
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+		if cue.Name == "" {
+			cue.Name = fmt.Sprintf("Cue %d", len(fixtures.CueLists[listNumber].Cues)+1)
+		}
+		fixtures.CueLists[listNumber].Cues = append(fixtures.CueLists[listNumber].Cues, cue)
+		status.SetText("")
+		listChanged()
+	})
+
+	buttonUpdateCue := widget.NewButton("Update Cue", func() {
+		if listNumber < 0 || selectedCue < 0 {
+			status.SetText("Select a cue first")
+			return
+		}
+		cue, err := readCue()
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+		fixtures.CueLists[listNumber].Cues[selectedCue] = cue
+		status.SetText("")
+		listChanged()
+	})
+
+	buttonDeleteCue := widget.NewButton("Delete Cue", func() {
+		if listNumber < 0 || selectedCue < 0 {
+			status.SetText("Select a cue first")
+			return
+		}
+		list := fixtures.CueLists[listNumber].Cues
+		fixtures.CueLists[listNumber].Cues = append(list[:selectedCue], list[selectedCue+1:]...)
+		selectedCue = -1
+		cueList.UnselectAll()
+		listChanged()
+	})
+
+	buttonBack := widget.NewButton("BACK", func() {
+		if _, err := cuePlayer.Back(); err != nil {
+			status.SetText(err.Error())
+			return
+		}
+		status.SetText("")
+		cueList.Refresh()
+	})
+
+	buttonGo := widget.NewButton("GO", func() {
+		if _, err := cuePlayer.Go(); err != nil {
+			status.SetText(err.Error())
+			return
+		}
+		status.SetText("")
+		cueList.Refresh()
+	})
+	buttonGo.Importance = widget.HighImportance
+
+	buttonClose := widget.NewButton("Close", func() {
+		modal.Hide()
+	})
+
+	cueForm := container.New(layout.NewFormLayout(),
+		widget.NewLabel("Name"), nameEntry,
+		widget.NewLabel("Preset"), presetEntry,
+		widget.NewLabel("Delay"), delayEntry,
+		widget.NewLabel("Fade"), fadeEntry,
+		followCheck, followTimeEntry,
+		widget.NewLabel("Commands"), commandsEntry,
+	)
+
+	scrollableCues := container.NewVScroll(cueList)
+	scrollableCues.SetMinSize(fyne.Size{Height: 350, Width: 250})
+
+	// Layout of the cues panel.
+	modal = widget.NewModalPopUp(
+		container.NewVBox(
+			title,
+			container.NewHBox(listSelect, newListEntry, buttonAddList),
+			container.NewBorder(nil, nil, scrollableCues, nil, cueForm),
+			container.NewHBox(buttonAddCue, buttonUpdateCue, buttonDeleteCue, layout.NewSpacer(), buttonBack, buttonGo),
+			status,
+			widget.NewLabel("Times are in seconds. Save the project to keep the cue lists."),
+			container.NewHBox(layout.NewSpacer(), buttonClose),
+		),
+		w.Canvas(),
+	)
+	return modal
+}
+
+// findCueList returns the index of a cue list by name, -1 if there isn't one.
+func findCueList(name string, fixtures *fixture.Fixtures) int {
+	for index, list := range fixtures.CueLists {
+		if list.Name == name {
+			return index
+		}
+	}
+	return -1
+}
+
+func cueListNames(fixtures *fixture.Fixtures) []string {
+	names := []string{}
+	for _, list := range fixtures.CueLists {
+		names = append(names, list.Name)
+	}
+	return names
+}
+
+func formatSeconds(seconds float64) string {
+	if seconds == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
+
+// parseSeconds reads a time in seconds, empty is no time.
+func parseSeconds(name string, text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(text, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("%s should be a time in seconds", name)
+	}
+	return seconds, nil
+}
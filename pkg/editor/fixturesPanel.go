@@ -64,6 +64,9 @@ type FixturesPanel struct {
 const RECTANGLE = 0
 const TEXT = 1
 
+// Fixture numbers offered in the editor, four launchpad pages of fixtures.
+const MAX_FIXTURE_NUMBER = 4 * common.FIXTURES_PER_PAGE
+
 const (
 	FIXTURE_ID int = iota
 	FIXTURE_TYPE
@@ -152,7 +155,10 @@ func NewFixturePanel(sequences []*common.Sequence, w fyne.Window, groupConfig *f
 
 	// Populate group options from the available sequence labels.
 	fp.GroupOptions = getGroupOptions(groupConfig)
-	fp.NumberOptions = []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	fp.NumberOptions = []string{}
+	for number := 1; number <= MAX_FIXTURE_NUMBER; number++ {
+		fp.NumberOptions = append(fp.NumberOptions, strconv.Itoa(number))
+	}
 	fp.TypeOptions = []string{"rgb", "scanner", "switch", "projector"}
 
 	// Storage for error flags for each fixture.
@@ -220,6 +226,13 @@ func NewFixturePanel(sequences []*common.Sequence, w fyne.Window, groupConfig *f
 		newItem.MultiFixtureDevice = f.MultiFixtureDevice
 		newItem.NumberSubFixtures = f.NumberSubFixtures
 		newItem.UseFixture = f.UseFixture
+		newItem.ColorMix = f.ColorMix
+		newItem.Calibration = f.Calibration
+		newItem.Limits = f.Limits
+		newItem.Priority = f.Priority
+		newItem.Advance = f.Advance
+		newItem.Interlock = f.Interlock
+		newItem.Default = f.Default
 		fp.FixtureList = append(fp.FixtureList, newItem)
 	}
 
@@ -620,6 +633,7 @@ func NewFixturePanel(sequences []*common.Sequence, w fyne.Window, groupConfig *f
 
 		// Insert updated fixture into fixtures.
 		fixtures.Fixtures = fp.FixtureList
+		fixture.RegisterMergeChannels(fixtures)
 
 		// Clear switch positions to their first positions.
 		for _, seq := range sequences {
@@ -1161,6 +1175,13 @@ func makeNewFixture(data [][]string, i widget.TableCellID, field int, value stri
 	newFixture.MultiFixtureDevice = fixtureList[i.Row].MultiFixtureDevice
 	newFixture.NumberSubFixtures = fixtureList[i.Row].NumberSubFixtures
 	newFixture.UseFixture = fixtureList[i.Row].UseFixture
+	newFixture.ColorMix = fixtureList[i.Row].ColorMix
+	newFixture.Calibration = fixtureList[i.Row].Calibration
+	newFixture.Limits = fixtureList[i.Row].Limits
+	newFixture.Priority = fixtureList[i.Row].Priority
+	newFixture.Advance = fixtureList[i.Row].Advance
+	newFixture.Interlock = fixtureList[i.Row].Interlock
+	newFixture.Default = fixtureList[i.Row].Default
 
 	// Now setup the new selected value.
 	switch {
@@ -1273,6 +1294,13 @@ func removeEmptyActions(fixtureList []fixture.Fixture) []fixture.Fixture {
 		newFixture.MultiFixtureDevice = f.MultiFixtureDevice
 		newFixture.NumberSubFixtures = f.NumberSubFixtures
 		newFixture.UseFixture = f.UseFixture
+		newFixture.ColorMix = f.ColorMix
+		newFixture.Calibration = f.Calibration
+		newFixture.Limits = f.Limits
+		newFixture.Priority = f.Priority
+		newFixture.Advance = f.Advance
+		newFixture.Interlock = f.Interlock
+		newFixture.Default = f.Default
 
 		newStates := []fixture.State{}
 
@@ -1294,6 +1322,7 @@ func removeEmptyActions(fixtureList []fixture.Fixture) []fixture.Fixture {
 			newState.Actions = newActions
 			newState.Settings = state.Settings
 			newState.Flash = state.Flash
+			newState.Requires = state.Requires
 
 			newStates = append(newStates, newState)
 
@@ -0,0 +1,135 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights programmer panel, it shows a fader for every
+// channel of the fixtures selected in the programmer and can record
+// their values as a new switch state.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package editor
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+func NewProgrammerPanel(w fyne.Window, programmer *fixture.Programmer, sequences []*common.Sequence, fixtures *fixture.Fixtures, commandChannels []chan common.Command) (modal *widget.PopUp) {
+
+	if debug {
+		fmt.Printf("NewProgrammerPanel\n")
+	}
+
+	// Title.
+	title := widget.NewLabel("Programmer")
+	title.TextStyle = fyne.TextStyle{
+		Bold: true,
+	}
+
+	// Say which fixtures the faders control.
+	selectedLabel := widget.NewLabel("Select fixtures from a sequence in programmer mode")
+	selected := programmer.Selected()
+	if len(selected) > 0 {
+		fixtureNames := ""
+		for _, thisFixture := range selected {
+			fixtureNames = fixtureNames + fmt.Sprintf(" S%d.F%d", thisFixture.Sequence+1, thisFixture.Fixture+1)
+		}
+		selectedLabel.SetText("Fixtures" + fixtureNames)
+	}
+
+	// A fader for every channel the selected fixtures have.
+	faders := container.NewHBox()
+	for _, channelName := range programmer.ChannelNames(fixtures) {
+		channelName := channelName
+
+		value, _ := programmer.GetChannel(channelName)
+		valueLabel := widget.NewLabel(fmt.Sprintf("%d", value))
+
+		fader := widget.NewSlider(0, 255)
+		fader.Orientation = widget.Vertical
+		fader.Step = 1
+		fader.Value = float64(value)
+		fader.OnChanged = func(value float64) {
+			programmer.SetChannel(channelName, byte(value), fixtures)
+			valueLabel.SetText(fmt.Sprintf("%d", int(value)))
+		}
+
+		faders.Add(container.NewBorder(valueLabel, widget.NewLabel(channelName), nil, nil, fader))
+	}
+	scrollableFaders := container.NewHScroll(faders)
+	scrollableFaders.SetMinSize(fyne.Size{Height: 350, Width: 700})
+
+	// Record the programmer as a new state on a switch.
+	recordStatus := widget.NewLabel("")
+	switchLabels := []string{}
+	for _, thisFixture := range fixtures.Fixtures {
+		if thisFixture.Type == "switch" && thisFixture.UseFixture != "" {
+			switchLabels = append(switchLabels, thisFixture.Label)
+		}
+	}
+	selectedSwitch := ""
+	switchSelect := widget.NewSelect(switchLabels, func(value string) {
+		selectedSwitch = value
+	})
+	switchSelect.PlaceHolder = "Select Switch"
+	recordButton := widget.NewButton("Record Switch State", func() {
+		stateNumber, err := programmer.RecordSwitchState(selectedSwitch, fixtures)
+		if err != nil {
+			recordStatus.SetText(err.Error())
+			return
+		}
+		recordStatus.SetText(fmt.Sprintf("Recorded state %d on %s", stateNumber, selectedSwitch))
+
+		// Tell the switch sequence about its new state.
+		for _, seq := range sequences {
+			if seq.Type == "switch" {
+				cmd := common.Command{
+					Action: common.ResetAllSwitchPositions,
+					Args: []common.Arg{
+						{Name: "Fixtures", Value: fixtures},
+					},
+				}
+				common.SendCommandToSequence(seq.Number, cmd, commandChannels)
+			}
+		}
+	})
+
+	// Clear hands the fixtures back to the sequences.
+	buttonClear := widget.NewButton("Clear", func() {
+		programmer.Clear()
+		modal.Hide()
+	})
+
+	buttonClose := widget.NewButton("Close", func() {
+		modal.Hide()
+	})
+
+	// Layout of the programmer panel.
+	modal = widget.NewModalPopUp(
+		container.NewVBox(
+			title,
+			selectedLabel,
+			scrollableFaders,
+			container.NewHBox(switchSelect, recordButton, recordStatus),
+			widget.NewLabel("Save a preset to record the programmer with it."),
+			container.NewHBox(layout.NewSpacer(), buttonClear, buttonClose),
+		),
+		w.Canvas(),
+	)
+	return modal
+}
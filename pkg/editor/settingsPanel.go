@@ -31,7 +31,7 @@ import (
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
 )
 
-const TABLE_HEIGHT int = 7
+const TABLE_HEIGHT int = 10
 const MAX_NUMBER_SETTINGS = 512
 
 type SettingsPanel struct {
@@ -60,6 +60,9 @@ const (
 	SETTING_CHANNEL
 	SETTING_VALUE
 	SETTING_SELECT_VALUE
+	SETTING_DELAY
+	SETTING_FADE_IN
+	SETTING_FADE_OUT
 	SETTING_DELETE
 	SETTING_ADD
 )
@@ -70,6 +73,9 @@ const (
 	COLUMN_CHANNEL
 	COLUMN_VALUE
 	COLUMN_SELECT_VALUE
+	COLUMN_DELAY
+	COLUMN_FADE_IN
+	COLUMN_FADE_OUT
 	COLUMN_DELETE
 	COLUMN_ADD
 )
@@ -111,8 +117,14 @@ func headerSettingsUpdate(id widget.TableCellID, o fyne.CanvasObject) {
 	case 4:
 		header.SetText("Select")
 	case 5:
-		header.SetText("-")
+		header.SetText("Delay")
 	case 6:
+		header.SetText("Fade In")
+	case 7:
+		header.SetText("Fade Out")
+	case 8:
+		header.SetText("-")
+	case 9:
 		header.SetText("+")
 	}
 }
@@ -132,9 +144,11 @@ func headerSettingsUpdateWithOutChannel(id widget.TableCellID, o fyne.CanvasObje
 		header.SetText("Value")
 	case 4:
 		header.SetText("Select")
-	case 5:
+	case 5, 6, 7:
+		header.SetText("")
+	case 8:
 		header.SetText("-")
-	case 6:
+	case 9:
 		header.SetText("+")
 	}
 }
@@ -220,6 +234,15 @@ func NewSettingsPanel(w fyne.Window, channelPanel bool, SettingsList []fixture.S
 				// SETTING_SELECT_VALUE
 				widget.NewSelect(st.SelectedValueOptions, func(value string) {}),
 
+				// SETTING_DELAY
+				widget.NewEntry(),
+
+				// SETTING_FADE_IN
+				widget.NewEntry(),
+
+				// SETTING_FADE_OUT
+				widget.NewEntry(),
+
 				// SETTING_DELETE
 				widget.NewButton("-", func() {}),
 
@@ -439,6 +462,44 @@ func NewSettingsPanel(w fyne.Window, channelPanel bool, SettingsList []fixture.S
 				}
 			}
 
+			// Show and Edit the times a switch state setting waits and fades for.
+			if i.Col == SETTING_DELAY || i.Col == SETTING_FADE_IN || i.Col == SETTING_FADE_OUT {
+				showSettingsField(i.Col, channelPanel, o)
+				column := i.Col
+				o.(*fyne.Container).Objects[column].(*widget.Entry).OnChanged = nil
+				o.(*fyne.Container).Objects[column].(*widget.Entry).SetText(data[i.Row][column])
+				o.(*fyne.Container).Objects[column].(*widget.Entry).OnChanged = func(settingTime string) {
+
+					// Check the time entered.
+					err := checkSettingTime(settingTime)
+					if err != nil {
+						popupErrorPanel.Content.(*fyne.Container).Objects[TITLE].(*widget.Label).Text = "Time Entry Error"
+						popupErrorPanel.Content.(*fyne.Container).Objects[MESSAGE].(*widget.Label).Text = err.Error()
+						popupErrorPanel.Content.(*fyne.Container).Objects[REPORT].(*widget.Label).Text = strings.Join(reports, "\n")
+						popupErrorPanel.Show()
+						// Disable the save button.
+						buttonSave.Disable()
+						return
+					}
+					// Enable the save button.
+					buttonSave.Enable()
+
+					newSetting := makeNewSetting(st.SettingsList, i.Row)
+					switch column {
+					case SETTING_DELAY:
+						newSetting.Delay = settingTime
+					case SETTING_FADE_IN:
+						newSetting.FadeIn = settingTime
+					case SETTING_FADE_OUT:
+						newSetting.FadeOut = settingTime
+					}
+					st.SettingsList = updateSettingsItem(st.SettingsList, newSetting.Number, newSetting)
+					data = makeSettingsArray(st.SettingsList)
+					st.UpdateSettings = true
+					st.UpdateThisChannel = st.CurrentChannel - 1
+				}
+			}
+
 			// Show the Delete Setting Button.
 			if i.Col == SETTING_DELETE {
 				showSettingsField(SETTING_DELETE, channelPanel, o)
@@ -504,12 +565,19 @@ func NewSettingsPanel(w fyne.Window, channelPanel bool, SettingsList []fixture.S
 	st.SettingsPanel.SetColumnWidth(COLUMN_CHANNEL, 90)      // Channel
 	st.SettingsPanel.SetColumnWidth(COLUMN_VALUE, 50)        // Value
 	st.SettingsPanel.SetColumnWidth(COLUMN_SELECT_VALUE, 90) // Select Value
+	st.SettingsPanel.SetColumnWidth(COLUMN_DELAY, 60)        // Delay
+	st.SettingsPanel.SetColumnWidth(COLUMN_FADE_IN, 60)      // Fade In
+	st.SettingsPanel.SetColumnWidth(COLUMN_FADE_OUT, 60)     // Fade Out
 	st.SettingsPanel.SetColumnWidth(COLUMN_DELETE, 20)       // Delete
 	st.SettingsPanel.SetColumnWidth(COLUMN_ADD, 20)          // Add
 
 	// If we creating channel hide the channel selection box.
+	// Fade times are only for switch state settings.
 	if channelPanel {
-		st.SettingsPanel.SetColumnWidth(COLUMN_CHANNEL, 0) // Channel
+		st.SettingsPanel.SetColumnWidth(COLUMN_CHANNEL, 0)  // Channel
+		st.SettingsPanel.SetColumnWidth(COLUMN_DELAY, 0)    // Delay
+		st.SettingsPanel.SetColumnWidth(COLUMN_FADE_IN, 0)  // Fade In
+		st.SettingsPanel.SetColumnWidth(COLUMN_FADE_OUT, 0) // Fade Out
 	}
 
 	return &st
@@ -613,6 +681,10 @@ func makeNewSetting(settingList []fixture.Setting, row int) fixture.Setting {
 	newSetting.Channel = settingList[row].Channel
 	newSetting.Value = settingList[row].Value
 	newSetting.SelectedValue = settingList[row].SelectedValue
+	newSetting.RGB = settingList[row].RGB
+	newSetting.Delay = settingList[row].Delay
+	newSetting.FadeIn = settingList[row].FadeIn
+	newSetting.FadeOut = settingList[row].FadeOut
 	return newSetting
 }
 
@@ -653,6 +725,9 @@ func makeSettingsArray(settings []fixture.Setting) [][]string {
 		newSetting = append(newSetting, setting.Channel)
 		newSetting = append(newSetting, setting.Value)
 		newSetting = append(newSetting, setting.SelectedValue)
+		newSetting = append(newSetting, setting.Delay)
+		newSetting = append(newSetting, setting.FadeIn)
+		newSetting = append(newSetting, setting.FadeOut)
 		newSetting = append(newSetting, "")
 		newSetting = append(newSetting, "-")
 		newSetting = append(newSetting, "+")
@@ -682,6 +757,8 @@ func showSettingsField(field int, channelPanel bool, o fyne.CanvasObject) {
 		o.(*fyne.Container).Objects[SETTING_VALUE].(*fyne.Container).Objects[RECTANGLE].(*canvas.Rectangle).Hidden = false
 	case field == SETTING_SELECT_VALUE:
 		o.(*fyne.Container).Objects[SETTING_SELECT_VALUE].(*widget.Select).Hidden = false
+	case (field == SETTING_DELAY || field == SETTING_FADE_IN || field == SETTING_FADE_OUT) && !channelPanel:
+		o.(*fyne.Container).Objects[field].(*widget.Entry).Hidden = false
 	case field == SETTING_DELETE:
 		o.(*fyne.Container).Objects[SETTING_DELETE].(*widget.Button).Hidden = false
 	case field == SETTING_ADD:
@@ -700,6 +777,9 @@ func hideAllSettingsFields(o fyne.CanvasObject) {
 	o.(*fyne.Container).Objects[SETTING_VALUE].(*fyne.Container).Objects[TEXT].(*widget.Entry).Hidden = true
 	o.(*fyne.Container).Objects[SETTING_VALUE].(*fyne.Container).Objects[RECTANGLE].(*canvas.Rectangle).Hidden = true
 	o.(*fyne.Container).Objects[SETTING_SELECT_VALUE].(*widget.Select).Hidden = true
+	o.(*fyne.Container).Objects[SETTING_DELAY].(*widget.Entry).Hidden = true
+	o.(*fyne.Container).Objects[SETTING_FADE_IN].(*widget.Entry).Hidden = true
+	o.(*fyne.Container).Objects[SETTING_FADE_OUT].(*widget.Entry).Hidden = true
 	o.(*fyne.Container).Objects[SETTING_DELETE].(*widget.Button).Hidden = true
 	o.(*fyne.Container).Objects[SETTING_ADD].(*widget.Button).Hidden = true
 }
@@ -443,6 +443,7 @@ func NewStatePanel(statesList []fixture.State, ap *ActionPanel, st *SettingsPane
 					newState.Label = value
 					newState.ButtonColor = sp.StatesList[thisState.Row].ButtonColor
 					newState.Flash = sp.StatesList[thisState.Row].Flash
+					newState.Requires = sp.StatesList[thisState.Row].Requires
 					newState.Settings = sp.StatesList[thisState.Row].Settings
 					newState.Actions = sp.StatesList[thisState.Row].Actions
 					sp.StatesList = updateStateItem(sp.StatesList, sp.StatesList[thisState.Row].Number, newState)
@@ -468,6 +469,7 @@ func NewStatePanel(statesList []fixture.State, ap *ActionPanel, st *SettingsPane
 					newState.Label = sp.StatesList[thisState.Row].Label
 					newState.ButtonColor = value
 					newState.Flash = sp.StatesList[thisState.Row].Flash
+					newState.Requires = sp.StatesList[thisState.Row].Requires
 					newState.Settings = sp.StatesList[thisState.Row].Settings
 					newState.Actions = sp.StatesList[thisState.Row].Actions
 					sp.StatesList = updateStateItem(sp.StatesList, sp.StatesList[thisState.Row].Number, newState)
@@ -672,6 +674,17 @@ func populateSettingList(statesList []fixture.State, stateNumber int16) (setting
 	return settingsList
 }
 
+// checkSettingTime checks the delay and fade times given to a state's setting.
+func checkSettingTime(value string) error {
+
+	if debug {
+		fmt.Printf("checkSettingTime %s\n", value)
+	}
+
+	_, err := fixture.ParseSettingTime(value)
+	return err
+}
+
 func createSettingList() (settingsList []fixture.Setting) {
 
 	if debug {
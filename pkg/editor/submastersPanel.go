@@ -0,0 +1,109 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights submasters panel, it shows a fader for every
+// fixture group and every fixture to dim them on top of the masters.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package editor
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dhowlett99/dmxlights/pkg/fixture"
+)
+
+func NewSubmastersPanel(w fyne.Window, groups *fixture.Groups, fixtures *fixture.Fixtures) (modal *widget.PopUp) {
+
+	if debug {
+		fmt.Printf("NewSubmastersPanel\n")
+	}
+
+	// Title.
+	title := widget.NewLabel("Submasters")
+	title.TextStyle = fyne.TextStyle{
+		Bold: true,
+	}
+
+	// A fader for every group.
+	groupFaders := container.NewHBox()
+	for _, group := range groups.Groups {
+		groupNumber, err := strconv.Atoi(group.Number)
+		if err != nil {
+			fmt.Printf("error: group %s has a bad number %s\n", group.Name, group.Number)
+			continue
+		}
+		groupFaders.Add(newSubmasterFader(group.Name, fixture.GroupSubmaster(groupNumber), func(level int) {
+			fixture.SetGroupSubmaster(groupNumber, level)
+		}))
+	}
+	scrollableGroupFaders := container.NewHScroll(groupFaders)
+	scrollableGroupFaders.SetMinSize(fyne.Size{Height: 200, Width: 700})
+
+	// And a fader for every fixture.
+	fixtureFaders := container.NewHBox()
+	for _, thisFixture := range fixtures.Fixtures {
+		if thisFixture.Type == "switch" {
+			continue
+		}
+		sequenceNumber := thisFixture.Group - 1
+		fixtureNumber := thisFixture.Number - 1
+		label := fmt.Sprintf("S%d.F%d", thisFixture.Group, thisFixture.Number)
+		fixtureFaders.Add(newSubmasterFader(label, fixture.FixtureSubmaster(sequenceNumber, fixtureNumber), func(level int) {
+			fixture.SetFixtureSubmaster(sequenceNumber, fixtureNumber, level)
+		}))
+	}
+	scrollableFixtureFaders := container.NewHScroll(fixtureFaders)
+	scrollableFixtureFaders.SetMinSize(fyne.Size{Height: 200, Width: 700})
+
+	buttonClose := widget.NewButton("Close", func() {
+		modal.Hide()
+	})
+
+	// Layout of the submasters panel.
+	modal = widget.NewModalPopUp(
+		container.NewVBox(
+			title,
+			widget.NewLabel("Groups"),
+			scrollableGroupFaders,
+			widget.NewLabel("Fixtures"),
+			scrollableFixtureFaders,
+			widget.NewLabel("Save a preset to record the submasters with it."),
+			container.NewHBox(layout.NewSpacer(), buttonClose),
+		),
+		w.Canvas(),
+	)
+	return modal
+}
+
+// newSubmasterFader makes a fader showing a submaster's level as a percentage.
+func newSubmasterFader(label string, level int, setLevel func(level int)) fyne.CanvasObject {
+
+	valueLabel := widget.NewLabel(fmt.Sprintf("%d%%", level*100/fixture.SUBMASTER_FULL))
+
+	fader := widget.NewSlider(0, fixture.SUBMASTER_FULL)
+	fader.Orientation = widget.Vertical
+	fader.Step = 1
+	fader.Value = float64(level)
+	fader.OnChanged = func(value float64) {
+		setLevel(int(value))
+		valueLabel.SetText(fmt.Sprintf("%d%%", int(value)*100/fixture.SUBMASTER_FULL))
+	}
+
+	return container.NewBorder(valueLabel, widget.NewLabel(label), nil, nil, fader)
+}
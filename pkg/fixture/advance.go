@@ -0,0 +1,133 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights switch advance, it steps a switch through its
+// states on a timer, on the beat or in a given order.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// How long a state is held when no hold time is given.
+const DEFAULT_ADVANCE_HOLD = 10 * time.Second
+
+// The shortest hold, stops a mistyped hold time flooding the fixtures.
+const MIN_ADVANCE_HOLD = 100 * time.Millisecond
+
+// How many beats a state is held for in beat mode when not given.
+const DEFAULT_ADVANCE_BEATS = 4
+
+// Advance steps a switch through its states on its own.
+// Timer mode steps through every state holding each for Hold, beat mode steps through every
+// state holding each for Beats beats on the switch's sound trigger and order mode steps
+// through the states in Order, each held for its own time.
+type Advance struct {
+	Mode  string        `yaml:"mode"`
+	Hold  string        `yaml:"hold,omitempty"`  // Timer mode, e.g. 30s or 2m.
+	Beats int           `yaml:"beats,omitempty"` // Beat mode.
+	Order []AdvanceStep `yaml:"order,omitempty"` // Order mode.
+}
+
+// AdvanceStep is a state in an advance order and how long it's held.
+type AdvanceStep struct {
+	State int16  `yaml:"state"` // The state's number.
+	Hold  string `yaml:"hold,omitempty"`
+}
+
+// SwitchAdvance works out the positions a switch steps through from its advance config.
+// Returns nil if the switch doesn't advance on its own.
+func (advance *Advance) SwitchAdvance(states []State) (*common.SwitchAdvance, error) {
+
+	if advance == nil {
+		return nil, nil
+	}
+
+	switchAdvance := &common.SwitchAdvance{
+		Mode: advance.Mode,
+	}
+
+	switch advance.Mode {
+	case common.SWITCH_ADVANCE_TIMER:
+		hold, err := advanceHold(advance.Hold)
+		if err != nil {
+			return nil, err
+		}
+		for position := range states {
+			switchAdvance.Steps = append(switchAdvance.Steps, common.AdvanceStep{Position: position, Hold: hold})
+		}
+
+	case common.SWITCH_ADVANCE_BEAT:
+		switchAdvance.Beats = advance.Beats
+		if switchAdvance.Beats <= 0 {
+			switchAdvance.Beats = DEFAULT_ADVANCE_BEATS
+		}
+		for position := range states {
+			switchAdvance.Steps = append(switchAdvance.Steps, common.AdvanceStep{Position: position})
+		}
+
+	case common.SWITCH_ADVANCE_ORDER:
+		for _, step := range advance.Order {
+			position := findStatePosition(states, step.State)
+			if position < 0 {
+				return nil, fmt.Errorf("advance order has state %d which the switch doesn't have", step.State)
+			}
+			hold, err := advanceHold(step.Hold)
+			if err != nil {
+				return nil, err
+			}
+			switchAdvance.Steps = append(switchAdvance.Steps, common.AdvanceStep{Position: position, Hold: hold})
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown advance mode %q, want timer, beat or order", advance.Mode)
+	}
+
+	if len(switchAdvance.Steps) < 2 {
+		return nil, fmt.Errorf("advance needs at least two states to step through")
+	}
+
+	return switchAdvance, nil
+}
+
+// advanceHold reads a hold time such as 30s or 1m30s.
+func advanceHold(hold string) (time.Duration, error) {
+
+	if hold == "" {
+		return DEFAULT_ADVANCE_HOLD, nil
+	}
+
+	duration, err := time.ParseDuration(hold)
+	if err != nil {
+		return 0, fmt.Errorf("advance hold time %q should look like 30s or 2m", hold)
+	}
+	if duration < MIN_ADVANCE_HOLD {
+		return 0, fmt.Errorf("advance hold time %q is shorter than %s", hold, MIN_ADVANCE_HOLD)
+	}
+	return duration, nil
+}
+
+// findStatePosition finds a switch position from its state number, returns -1 if not found.
+func findStatePosition(states []State, number int16) int {
+	for position, state := range states {
+		if state.Number == number {
+			return position
+		}
+	}
+	return -1
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights fixture calibration, it applies dimmer curves, gamma,
+// white balance and output limits to the values sent to a fixture.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"math"
+)
+
+// Dimmer curves.
+const CURVE_LINEAR = "linear"
+const CURVE_SQUARE = "square"
+const CURVE_SCURVE = "scurve"
+const CURVE_LUT = "lut"
+
+// Calibration allows fixtures of different brands to be matched.
+// Curve is applied to the master dimmer, gamma and gain to the color channels,
+// Min and Max clamp the output of both.
+type Calibration struct {
+	Curve string       `yaml:"curve,omitempty"` // linear, square, scurve or lut.
+	LUT   []int        `yaml:"lut,omitempty"`   // Custom curve, evenly spaced output values from 0 to 255.
+	Gamma float64      `yaml:"gamma,omitempty"` // Gamma for the color channels, 0 or 1 is linear.
+	Gain  *EmitterGain `yaml:"gain,omitempty"`  // Per emitter gain for white balance.
+	Min   int          `yaml:"min,omitempty"`   // Minimum output, so LEDs don't flicker when nearly off.
+	Max   int          `yaml:"max,omitempty"`   // Maximum output, 0 means no limit.
+}
+
+// EmitterGain is a percentage (0-100) for each emitter, not set means 100%.
+type EmitterGain struct {
+	Red   *int `yaml:"red,omitempty"`
+	Green *int `yaml:"green,omitempty"`
+	Blue  *int `yaml:"blue,omitempty"`
+	White *int `yaml:"white,omitempty"`
+	Amber *int `yaml:"amber,omitempty"`
+	UV    *int `yaml:"uv,omitempty"`
+}
+
+// calibrateDimmer applies the fixtures dimmer curve and output limits to a master dimmer value.
+func calibrateDimmer(value int, calibration *Calibration) int {
+
+	if calibration == nil {
+		return value
+	}
+
+	value = applyCurve(value, calibration.Curve, calibration.LUT)
+
+	return clampOutput(value, calibration)
+}
+
+// calibrateColor applies the fixtures white balance gain, gamma and output limits to a color channel value.
+// emitter is one of red, green, blue, white, amber or uv.
+func calibrateColor(value int, emitter string, calibration *Calibration) int {
+
+	if calibration == nil {
+		return value
+	}
+
+	if calibration.Gain != nil {
+		gain := findEmitterGain(emitter, calibration.Gain)
+		if gain != nil {
+			value = value * *gain / 100
+		}
+	}
+
+	if calibration.Gamma > 0 && calibration.Gamma != 1 {
+		value = int(math.Round(255 * math.Pow(float64(value)/255, calibration.Gamma)))
+	}
+
+	return clampOutput(value, calibration)
+}
+
+func findEmitterGain(emitter string, gain *EmitterGain) *int {
+	switch emitter {
+	case "red":
+		return gain.Red
+	case "green":
+		return gain.Green
+	case "blue":
+		return gain.Blue
+	case "white":
+		return gain.White
+	case "amber":
+		return gain.Amber
+	case "uv":
+		return gain.UV
+	}
+	return nil
+}
+
+// applyCurve maps a 0-255 value through the named dimmer curve.
+func applyCurve(value int, curve string, lut []int) int {
+
+	value = limitValue(value)
+
+	switch curve {
+	case CURVE_SQUARE:
+		return value * value / 255
+
+	case CURVE_SCURVE:
+		return int(math.Round(255 * (1 - math.Cos(math.Pi*float64(value)/255)) / 2))
+
+	case CURVE_LUT:
+		if len(lut) < 2 {
+			return value
+		}
+		// Interpolate between the two nearest entries in the table.
+		position := float64(value) * float64(len(lut)-1) / 255
+		lower := int(position)
+		if lower >= len(lut)-1 {
+			return limitValue(lut[len(lut)-1])
+		}
+		fraction := position - float64(lower)
+		return limitValue(int(math.Round(float64(lut[lower]) + fraction*float64(lut[lower+1]-lut[lower]))))
+	}
+
+	// Linear.
+	return value
+}
+
+// clampOutput applies the minimum and maximum output limits, a value of zero stays at zero so the fixture can still turn off.
+func clampOutput(value int, calibration *Calibration) int {
+
+	value = limitValue(value)
+
+	if value > 0 && value < calibration.Min {
+		value = calibration.Min
+	}
+	if calibration.Max > 0 && value > calibration.Max {
+		value = calibration.Max
+	}
+	return value
+}
+
+// limitValue keeps a value within the DMX range.
+func limitValue(value int) int {
+	if value < 0 {
+		return 0
+	}
+	if value > 255 {
+		return 255
+	}
+	return value
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights color mixer, it derives the white, amber and UV
+// emitter values from a requested RGB color.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// Default mixing percentages used when a fixture's colormix config doesn't give one.
+const DEFAULT_MIX_WHITE = 100
+const DEFAULT_MIX_AMBER = 100
+const DEFAULT_MIX_UV = 0
+
+// Amber is roughly full red with three quarters green.
+const AMBER_GREEN_RATIO = 0.75
+
+// ColorMix configures how a fixture derives its white, amber and UV emitters
+// from the RGB colors produced by the patterns and color picker.
+// Each value is a percentage (0-100) of the available color moved to that emitter.
+// Fixtures without a colormix config aren't mixed, so existing fixture files light as they always have.
+type ColorMix struct {
+	Disabled bool `yaml:"disabled,omitempty"`
+	White    *int `yaml:"white,omitempty"`
+	Amber    *int `yaml:"amber,omitempty"`
+	UV       *int `yaml:"uv,omitempty"`
+}
+
+// Emitters describes which extra emitters a fixture has.
+type Emitters struct {
+	HasWhite bool
+	HasAmber bool
+	HasUV    bool
+}
+
+// findEmitters looks through the fixtures channels to find which extra emitters it has.
+func findEmitters(fixture *Fixture) Emitters {
+	emitters := Emitters{}
+	for _, channel := range fixture.Channels {
+		if strings.Contains(channel.Name, "White") {
+			emitters.HasWhite = true
+		}
+		if strings.Contains(channel.Name, "Amber") {
+			emitters.HasAmber = true
+		}
+		if strings.Contains(channel.Name, "UV") {
+			emitters.HasUV = true
+		}
+	}
+	return emitters
+}
+
+// emittersKey identifies a fixture in the emitters lookup.
+type emittersKey struct {
+	group  int
+	number int
+}
+
+// fixtureEmitters remembers which extra emitters each fixture has, worked out when the fixtures are loaded
+// so the channels don't have to be searched every time a fixture is lit.
+var fixtureEmitters = make(map[emittersKey]Emitters)
+var fixtureEmittersLock sync.RWMutex
+
+// registerEmitters works out the extra emitters of every fixture.
+func registerEmitters(fixtures *Fixtures) {
+	emitters := make(map[emittersKey]Emitters)
+	for _, fixture := range fixtures.Fixtures {
+		emitters[emittersKey{group: fixture.Group, number: fixture.Number}] = findEmitters(&fixture)
+	}
+
+	fixtureEmittersLock.Lock()
+	defer fixtureEmittersLock.Unlock()
+	fixtureEmitters = emitters
+}
+
+// getEmitters returns the extra emitters a fixture has, looking through its channels if it wasn't registered.
+func getEmitters(fixture *Fixture) Emitters {
+	fixtureEmittersLock.RLock()
+	emitters, ok := fixtureEmitters[emittersKey{group: fixture.Group, number: fixture.Number}]
+	fixtureEmittersLock.RUnlock()
+	if !ok {
+		return findEmitters(fixture)
+	}
+	return emitters
+}
+
+// MixColor takes a requested color and returns the color to send to a fixture
+// with the given emitters, moving the white, amber and UV content of the RGB
+// color to the dedicated emitters.
+// Colors which already specify a W, A or UV value are left untouched, as are
+// fixtures with no extra emitters, no colormix config or with mixing disabled.
+func MixColor(color common.Color, emitters Emitters, mix *ColorMix) common.Color {
+
+	if mix == nil || mix.Disabled {
+		return color
+	}
+
+	// The color has been set explicitly, so leave it alone.
+	if color.W != 0 || color.A != 0 || color.UV != 0 {
+		return color
+	}
+
+	white := DEFAULT_MIX_WHITE
+	amber := DEFAULT_MIX_AMBER
+	uv := DEFAULT_MIX_UV
+	if mix.White != nil {
+		white = *mix.White
+	}
+	if mix.Amber != nil {
+		amber = *mix.Amber
+	}
+	if mix.UV != nil {
+		uv = *mix.UV
+	}
+
+	mixed := color
+
+	// White extraction, the part common to all three colors can be made by the white emitter.
+	if emitters.HasWhite && white > 0 {
+		w := minInt(mixed.R, minInt(mixed.G, mixed.B)) * white / 100
+		mixed.W = w
+		mixed.R = mixed.R - w
+		mixed.G = mixed.G - w
+		mixed.B = mixed.B - w
+	}
+
+	// Amber for warm hues, the part of red with a matching amount of green.
+	if emitters.HasAmber && amber > 0 {
+		a := minInt(mixed.R, int(float64(mixed.G)/AMBER_GREEN_RATIO)) * amber / 100
+		mixed.A = a
+		mixed.R = mixed.R - a
+		mixed.G = mixed.G - int(float64(a)*AMBER_GREEN_RATIO)
+	}
+
+	// UV adds to the blue and purple hues, it doesn't replace them.
+	if emitters.HasUV && uv > 0 {
+		mixed.UV = mixed.B * uv / 100
+	}
+
+	if debug {
+		fmt.Printf("MixColor %+v -> %+v\n", color, mixed)
+	}
+
+	return mixed
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
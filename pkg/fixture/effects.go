@@ -0,0 +1,257 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights mini sequencer effects, a rainbow, a fade loop
+// through the action colors, a candle flicker and a breathing pulse.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// Action modes played by playEffect.
+const ACTION_RAINBOW = "Rainbow"
+const ACTION_FADE_LOOP = "Fade Loop"
+const ACTION_FLICKER = "Flicker"
+const ACTION_PULSE = "Pulse"
+
+// How often an effect updates its fixture.
+const EFFECT_FRAME = 25 * time.Millisecond
+
+// An effect goes round once in this many chase steps at the action's speed.
+const EFFECT_CYCLE_STEPS = 16
+
+// A flickering fixture picks a new level this many times a cycle.
+const EFFECT_FLICKER_STEPS = 64
+
+// On the beat a rainbow moves on this much of the way round.
+const EFFECT_BEAT_STEP = 1.0 / 8
+
+// A pulse started by a beat lasts this long.
+const EFFECT_BEAT_PULSE = 500 * time.Millisecond
+
+// IsEffectMode returns true for the action modes played by playEffect.
+func IsEffectMode(mode string) bool {
+	return mode == ACTION_RAINBOW || mode == ACTION_FADE_LOOP || mode == ACTION_FLICKER || mode == ACTION_PULSE
+}
+
+// playEffect plays an effect mode until told to stop. Speed sets how long the effect takes to
+// go round, or with music each beat moves it on. Fade sets how softly it changes and Size how
+// long a pulse stays on or how deep a flicker goes. play sends a color and a brightness.
+func playEffect(mode string, cfg ActionConfig, musicTrigger chan common.Command, stop chan bool, play func(color common.Color, brightness int)) {
+
+	cycle := effectCycle(cfg.Speed)
+
+	phase := 0.0
+	if cfg.MusicTrigger && mode == ACTION_PULSE {
+		// Dark until the first beat.
+		phase = 1
+	}
+
+	var level int
+	var target int
+	var sinceFlicker time.Duration
+
+	for {
+		beat := false
+		select {
+		case <-stop:
+			play(common.Black, 0)
+			return
+		case <-musicTrigger:
+			beat = true
+		case <-time.After(EFFECT_FRAME):
+		}
+
+		// Hold the effect while frozen.
+		if common.IsFrozen() {
+			select {
+			case <-stop:
+				play(common.Black, 0)
+				return
+			case <-common.FreezeReleased():
+			}
+		}
+
+		if mode == ACTION_FLICKER {
+			sinceFlicker += EFFECT_FRAME
+			if beat {
+				// Flare up then die back down.
+				level = common.MAX_DMX_BRIGHTNESS
+				target = flickerLevel(cfg.Size, 0)
+			} else if !cfg.MusicTrigger && sinceFlicker >= cycle/EFFECT_FLICKER_STEPS {
+				target = flickerLevel(cfg.Size, rand.Float64())
+				sinceFlicker = 0
+			}
+			level = smoothLevel(level, target, cfg.Fade)
+			play(firstColor(cfg.Colors, common.Orange), level)
+			continue
+		}
+
+		phase = effectStep(mode, phase, EFFECT_FRAME, cycle, cfg.MusicTrigger, beat, len(cfg.Colors))
+		color, level := effectColor(mode, phase, cfg.Colors, cfg.Fade, cfg.Size)
+		play(color, level)
+	}
+}
+
+// effectCycle is how long an effect takes to go round at a chase speed.
+func effectCycle(speed time.Duration) time.Duration {
+	return speed * EFFECT_CYCLE_STEPS
+}
+
+// effectStep moves an effect on by a frame, or with music only on the beat.
+// The phase goes from 0 to 1 round the effect.
+func effectStep(mode string, phase float64, frame time.Duration, cycle time.Duration, music bool, beat bool, numberColors int) float64 {
+
+	if !music {
+		phase += float64(frame) / float64(cycle)
+		return phase - math.Floor(phase)
+	}
+
+	switch mode {
+	case ACTION_PULSE:
+		// A beat starts a pulse which runs its course and stops.
+		if beat {
+			return 0
+		}
+		return math.Min(1, phase+float64(frame)/float64(EFFECT_BEAT_PULSE))
+
+	case ACTION_FADE_LOOP:
+		// A beat moves on to the next color.
+		if beat && numberColors > 0 {
+			next := math.Floor(phase*float64(numberColors)+1) / float64(numberColors)
+			return next - math.Floor(next)
+		}
+
+	default:
+		if beat {
+			phase += EFFECT_BEAT_STEP
+			return phase - math.Floor(phase)
+		}
+	}
+	return phase
+}
+
+// effectColor works out the color and brightness of an effect at a point round it.
+func effectColor(mode string, phase float64, colors []common.Color, fade int, size int) (common.Color, int) {
+
+	switch mode {
+	case ACTION_RAINBOW:
+		return hueColor(phase * 360), common.MAX_DMX_BRIGHTNESS
+
+	case ACTION_FADE_LOOP:
+		if len(colors) == 0 {
+			return hueColor(phase * 360), common.MAX_DMX_BRIGHTNESS
+		}
+		// Each color holds then cross fades into the next, a softer fade spends longer fading.
+		slot := phase * float64(len(colors))
+		this := int(slot) % len(colors)
+		next := (this + 1) % len(colors)
+		fading := fadeFraction(fade)
+		mix := (slot - math.Floor(slot) - (1 - fading)) / fading
+		return mixColors(colors[this], colors[next], math.Max(0, mix)), common.MAX_DMX_BRIGHTNESS
+
+	case ACTION_PULSE:
+		// A pulse is on for part of the cycle set by the size, a sharper fade has a sharper peak.
+		on := pulseFraction(size)
+		if phase >= on {
+			return firstColor(colors, common.White), 0
+		}
+		level := math.Sin(math.Pi * phase / on)
+		level = math.Pow(level, float64(fade)/float64(FADE_NORMAL))
+		return firstColor(colors, common.White), int(level * common.MAX_DMX_BRIGHTNESS)
+	}
+
+	return common.Black, 0
+}
+
+// fadeFraction is how much of a color's time in a fade loop is spent fading into the next.
+func fadeFraction(fade int) float64 {
+	return math.Max(0.1, 1-float64(fade-FADE_SOFT)/float64(FADE_SHARP))
+}
+
+// pulseFraction is how much of a cycle a pulse is on for.
+func pulseFraction(size int) float64 {
+	return math.Min(1, math.Max(0.1, float64(size)/float64(SIZE_MEDIUM)*0.5))
+}
+
+// flickerLevel picks a flicker brightness, a bigger size flickers deeper.
+// random goes from 0 to 1.
+func flickerLevel(size int, random float64) int {
+	depth := math.Min(1, 0.2+float64(size)/float64(SIZE_LONG)*0.6)
+	return int((1 - depth + depth*random) * common.MAX_DMX_BRIGHTNESS)
+}
+
+// smoothLevel moves a brightness towards its target, a sharp fade gets there straight away.
+func smoothLevel(level int, target int, fade int) int {
+	if fade >= FADE_SHARP {
+		return target
+	}
+	step := (target - level) * fade / FADE_SHARP
+	if step == 0 {
+		return target
+	}
+	return level + step
+}
+
+// hueColor returns a fully saturated color round the color wheel, hue in degrees.
+func hueColor(hue float64) common.Color {
+
+	hue = math.Mod(hue, 360)
+	section := hue / 60
+	rising := int((section - math.Floor(section)) * common.MAX_DMX_BRIGHTNESS)
+	falling := common.MAX_DMX_BRIGHTNESS - rising
+
+	switch int(section) {
+	case 0:
+		return common.Color{R: common.MAX_DMX_BRIGHTNESS, G: rising}
+	case 1:
+		return common.Color{R: falling, G: common.MAX_DMX_BRIGHTNESS}
+	case 2:
+		return common.Color{G: common.MAX_DMX_BRIGHTNESS, B: rising}
+	case 3:
+		return common.Color{G: falling, B: common.MAX_DMX_BRIGHTNESS}
+	case 4:
+		return common.Color{R: rising, B: common.MAX_DMX_BRIGHTNESS}
+	default:
+		return common.Color{R: common.MAX_DMX_BRIGHTNESS, B: falling}
+	}
+}
+
+// mixColors mixes two colors, mix goes from 0 all from to 1 all to.
+func mixColors(from common.Color, to common.Color, mix float64) common.Color {
+	blend := func(a int, b int) int {
+		return int(math.Round(float64(a) + (float64(b)-float64(a))*mix))
+	}
+	return common.Color{
+		R:  blend(from.R, to.R),
+		G:  blend(from.G, to.G),
+		B:  blend(from.B, to.B),
+		W:  blend(from.W, to.W),
+		A:  blend(from.A, to.A),
+		UV: blend(from.UV, to.UV),
+	}
+}
+
+func firstColor(colors []common.Color, otherwise common.Color) common.Color {
+	if len(colors) == 0 {
+		return otherwise
+	}
+	return colors[0]
+}
@@ -24,12 +24,15 @@ import (
 	"io"
 	"math"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
 	"github.com/dhowlett99/dmxlights/pkg/sound"
 	"github.com/go-yaml/yaml"
 	"github.com/oliread/usbdmx/ft232"
@@ -39,7 +42,8 @@ const debug = false
 const dmxDebug = false
 
 type Fixtures struct {
-	Fixtures []Fixture `yaml:"fixtures"`
+	Fixtures []Fixture      `yaml:"fixtures"`
+	CueLists []cues.CueList `yaml:"cuelists,omitempty"` // Cue lists are saved with the project.
 }
 
 type Groups struct {
@@ -54,14 +58,15 @@ type Color struct {
 }
 
 type State struct {
-	Name        string    `yaml:"name"`
-	Number      int16     `yaml:"number"`
-	Label       string    `yaml:"label"`
-	ButtonColor string    `yaml:"buttoncolor"`
-	Master      int       `yaml:"master"`
-	Actions     []Action  `yaml:"actions,omitempty"`
-	Settings    []Setting `yaml:"settings,omitempty"`
-	Flash       bool      `yaml:"flash"`
+	Name        string        `yaml:"name"`
+	Number      int16         `yaml:"number"`
+	Label       string        `yaml:"label"`
+	ButtonColor string        `yaml:"buttoncolor"`
+	Master      int           `yaml:"master"`
+	Actions     []Action      `yaml:"actions,omitempty"`
+	Settings    []Setting     `yaml:"settings,omitempty"`
+	Flash       bool          `yaml:"flash"`
+	Requires    []Requirement `yaml:"requires,omitempty"` // Other switches that must be set before a switch moves to this state.
 }
 
 type Action struct {
@@ -111,19 +116,26 @@ type ActionConfig struct {
 }
 
 type Fixture struct {
-	ID                 int       `yaml:"id"`
-	Name               string    `yaml:"name"`
-	Label              string    `yaml:"label,omitempty"`
-	Number             int       `yaml:"number"`
-	Description        string    `yaml:"description"`
-	Type               string    `yaml:"type"`
-	Group              int       `yaml:"group"`
-	Address            int16     `yaml:"address"`
-	Channels           []Channel `yaml:"channels"`
-	States             []State   `yaml:"states,omitempty"`
-	MultiFixtureDevice bool      `yaml:"-"` // Calulated internally.
-	NumberSubFixtures  int       `yaml:"-"` // Calulated internally.
-	UseFixture         string    `yaml:"use_fixture,omitempty"`
+	ID                 int          `yaml:"id"`
+	Name               string       `yaml:"name"`
+	Label              string       `yaml:"label,omitempty"`
+	Number             int          `yaml:"number"`
+	Description        string       `yaml:"description"`
+	Type               string       `yaml:"type"`
+	Group              int          `yaml:"group"`
+	Address            int16        `yaml:"address"`
+	Channels           []Channel    `yaml:"channels"`
+	States             []State      `yaml:"states,omitempty"`
+	MultiFixtureDevice bool         `yaml:"-"` // Calulated internally.
+	NumberSubFixtures  int          `yaml:"-"` // Calulated internally.
+	UseFixture         string       `yaml:"use_fixture,omitempty"`
+	ColorMix           *ColorMix    `yaml:"colormix,omitempty"`    // Optional control of white, amber and UV mixing.
+	Calibration        *Calibration `yaml:"calibration,omitempty"` // Optional dimmer curve, gamma and white balance.
+	Limits             *Limits      `yaml:"limits,omitempty"`      // Optional scanner orientation, range and keep out zones.
+	Priority           int          `yaml:"priority,omitempty"`    // Switch merge priority, the highest priority wins shared channels.
+	Advance            *Advance     `yaml:"advance,omitempty"`     // Optional switch auto advance through its states.
+	Interlock          string       `yaml:"interlock,omitempty"`   // Switches in the same interlock group are never on together.
+	Default            *int16       `yaml:"default,omitempty"`     // The state number a switch powers on in, its first state if not set.
 }
 
 type Group struct {
@@ -145,6 +157,10 @@ type Setting struct {
 	Channel       string `yaml:"channel,omitempty"`
 	Value         string `yaml:"value"`
 	SelectedValue string `yaml:"selectedvalue"`
+	RGB           *Color `yaml:"rgb,omitempty"`     // Optional RGB approximation of a color wheel slot.
+	Delay         string `yaml:"delay,omitempty"`   // Optional wait before a switch state setting starts, e.g. 500ms or 2s.
+	FadeIn        string `yaml:"fadein,omitempty"`  // Optional time a switch state setting takes to fade in.
+	FadeOut       string `yaml:"fadeout,omitempty"` // Optional time a switch state setting takes to fade out when the switch moves on.
 }
 
 type Channel struct {
@@ -213,17 +229,17 @@ func SaveFixturesWriter(writer fyne.URIWriteCloser, fixtures *Fixtures) error {
 func LoadFixtures(filename string) (fixtures *Fixtures, err error) {
 
 	if debug {
-		fmt.Printf("LoadFixtures from file %s\n", "projects/"+filename)
+		fmt.Printf("LoadFixtures from file %s\n", filename)
 	}
 
 	// Open the fixtures yaml file.
-	_, err = os.OpenFile("projects/"+filename, os.O_RDONLY, 0644)
+	_, err = os.OpenFile(filename, os.O_RDONLY, 0644)
 	if err != nil {
 		return nil, err
 	}
 
 	// Reads the fixtures yaml file.
-	data, err := os.ReadFile("projects/" + filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -232,11 +248,11 @@ func LoadFixtures(filename string) (fixtures *Fixtures, err error) {
 	fixtures = &Fixtures{}
 	err = yaml.Unmarshal(data, fixtures)
 	if err != nil {
-		return nil, errors.New("error: unmarshalling file: " + "projects/" + filename + err.Error())
+		return nil, errors.New("error: unmarshalling file: " + filename + err.Error())
 	}
 
 	if len(fixtures.Fixtures) == 0 {
-		return nil, errors.New("error: unmarshalling file: " + "projects/" + filename + " error: fixtures are empty")
+		return nil, errors.New("error: unmarshalling file: " + filename + " error: fixtures are empty")
 	}
 
 	return fixtures, nil
@@ -288,13 +304,13 @@ func SaveFixtures(filename string, fixtures *Fixtures) error {
 	// Marshal the fixtures data into a yaml data structure.
 	data, err := yaml.Marshal(fixtures)
 	if err != nil {
-		return errors.New("error: marshalling file: " + "projects/" + filename + err.Error())
+		return errors.New("error: marshalling file: " + filename + err.Error())
 	}
 
 	// Write the fixtures.yaml file.
 	err = os.WriteFile(filename, data, 0644)
 	if err != nil {
-		return errors.New("error: writing file: " + "projects/" + filename + err.Error())
+		return errors.New("error: writing file: " + filename + err.Error())
 	}
 
 	// Fixtures file saved, no errors.
@@ -377,6 +393,11 @@ func FixtureReceiver(
 		// Wait for first step
 		cmd := <-fixtureStepChannel
 
+		// Fixtures on another page of the launchpad still play, they just aren't shown.
+		if !common.FixtureOnPage(myFixtureNumber, cmd.FixturePage) {
+			cmd.Hidden = true
+		}
+
 		switch {
 		case cmd.Type == "lastColor":
 			if debug {
@@ -475,7 +496,7 @@ func clear(fixtureNumber int, cmd common.FixtureCommand, stopFadeDown chan bool,
 	case <-time.After(100 * time.Millisecond):
 	}
 
-	return MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, cmd.ScannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+	return MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, cmd.ScannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 }
 
 // Start Flood.
@@ -484,25 +505,22 @@ func startFlood(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixtures
 		fmt.Printf("Fixture:%d Set RGB Flood\n", fixtureNumber)
 	}
 
-	// TODO find sequence numbers from config.
-	if cmd.SequenceNumber == 4 {
-		cmd.SequenceNumber = 2
-	}
+	cmd.SequenceNumber = common.GetDisplaySequenceNumber(cmd.SequenceNumber)
 
 	pan := 128
 	tilt := 128
 	shutter := FindShutter(fixtureNumber, cmd.SequenceNumber, "Open", fixtures)
 	gobo := FindGobo(fixtureNumber, cmd.SequenceNumber, "White", fixtures)
-	scannerColor := FindColor(fixtureNumber, cmd.SequenceNumber, "White", fixtures)
+	scannerColor := FindWheelColor(fixtureNumber, cmd.SequenceNumber, common.White, fixtures)
 	rotate := 0
 	program := 0
 
 	if !cmd.Hidden {
-		common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, common.White, cmd.Master, eventsForLaunchpad, guiButtons)
-		common.LabelButton(fixtureNumber, cmd.SequenceNumber, "", guiButtons)
+		common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, common.White, cmd.Master, eventsForLaunchpad, guiButtons)
+		common.LabelButton(common.FixtureColumn(fixtureNumber), common.SequenceRow(cmd.SequenceNumber), "", guiButtons)
 	}
 
-	return MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, common.White, pan, tilt, shutter, rotate, program, gobo, scannerColor, fixtures, false, cmd.Master, cmd.Master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+	return MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.White, pan, tilt, shutter, rotate, program, gobo, scannerColor, fixtures, false, cmd.Master, cmd.Master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 
 }
 
@@ -513,16 +531,13 @@ func stopFlood(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixtures,
 		fmt.Printf("Fixture:%d Set Stop RGB Flood\n", fixtureNumber)
 	}
 
-	// TODO find sequence numbers from config.
-	if cmd.SequenceNumber == 4 {
-		cmd.SequenceNumber = 2
-	}
+	cmd.SequenceNumber = common.GetDisplaySequenceNumber(cmd.SequenceNumber)
 
 	if !cmd.Hidden {
-		common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, common.Black, 0, eventsForLaunchpad, guiButtons)
-		common.LabelButton(fixtureNumber, cmd.SequenceNumber, "", guiButtons)
+		common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, common.Black, 0, eventsForLaunchpad, guiButtons)
+		common.LabelButton(common.FixtureColumn(fixtureNumber), common.SequenceRow(cmd.SequenceNumber), "", guiButtons)
 	}
-	return MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, cmd.Blackout, 0, 0, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+	return MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, cmd.Blackout, 0, 0, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 }
 
 // Switch On Static Scene.
@@ -537,10 +552,7 @@ func setStaticOn(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixture
 			fmt.Printf("%d: Fixture:%d RGB Switch Static On - Trying to Set RGB Static Master=%d\n", cmd.SequenceNumber, fixtureNumber, cmd.Master)
 		}
 
-		// TODO find sequence numbers from config.
-		if cmd.SequenceNumber == 4 {
-			cmd.SequenceNumber = 2
-		}
+		cmd.SequenceNumber = common.GetDisplaySequenceNumber(cmd.SequenceNumber)
 
 		lamp := cmd.RGBStaticColors[fixtureNumber]
 
@@ -548,9 +560,9 @@ func setStaticOn(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixture
 		if !cmd.Hidden {
 			if lamp.Flash {
 				onColor := common.Color{R: lamp.Color.R, G: lamp.Color.G, B: lamp.Color.B}
-				common.FlashLight(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, onColor, common.Black, eventsForLaunchpad, guiButtons)
+				common.FlashLight(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, onColor, common.Black, eventsForLaunchpad, guiButtons)
 			} else {
-				common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, lamp.Color, cmd.Master, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, lamp.Color, cmd.Master, eventsForLaunchpad, guiButtons)
 			}
 		}
 
@@ -558,10 +570,10 @@ func setStaticOn(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixture
 		color := common.GetColorNameByRGB(lamp.Color)
 		// Find a suitable gobo based on the requested static lamp color.
 		scannerGobo := FindGobo(fixtureNumber, cmd.SequenceNumber, color, fixtures)
-		// Find a suitable color wheel settin based on the requested static lamp color.
-		scannerColor := FindColor(fixtureNumber, cmd.SequenceNumber, color, fixtures)
+		// Find the nearest color wheel setting to the requested static lamp color.
+		scannerColor := FindWheelColor(fixtureNumber, cmd.SequenceNumber, lamp.Color, fixtures)
 
-		return MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, lamp.Color, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+		return MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, lamp.Color, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 	}
 
 	return common.LastColor{}
@@ -577,9 +589,7 @@ func fadeUpStatic(fixtureNumber int, cmd common.FixtureCommand, lastColor common
 	sequence := common.Sequence{}
 	sequence.Type = cmd.Type
 
-	if cmd.SequenceNumber == 4 {
-		cmd.SequenceNumber = 2
-	}
+	cmd.SequenceNumber = common.GetDisplaySequenceNumber(cmd.SequenceNumber)
 
 	// Stop any running fade ups.
 	select {
@@ -612,8 +622,8 @@ func fadeUpStatic(fixtureNumber int, cmd common.FixtureCommand, lastColor common
 					color := common.GetColorNameByRGB(lastColor.RGBColor)
 					// Find a suitable gobo based on the requested static lamp color.
 					scannerGobo := FindGobo(fixtureNumber, cmd.SequenceNumber, color, fixtures)
-					// Find a suitable color wheel settin based on the requested static lamp color.
-					scannerColor := FindColor(fixtureNumber, cmd.SequenceNumber, color, fixtures)
+					// Find the nearest color wheel setting to the requested static lamp color.
+					scannerColor := FindWheelColor(fixtureNumber, cmd.SequenceNumber, lastColor.RGBColor, fixtures)
 
 					// Listen for stop command.
 					select {
@@ -622,17 +632,23 @@ func fadeUpStatic(fixtureNumber int, cmd common.FixtureCommand, lastColor common
 					case <-time.After(10 * time.Millisecond):
 					}
 					if !cmd.Hidden {
-						common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+						common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
 					}
 					if cmd.Label == "chaser" {
 						// If we are a RGB chaser used as a shutter chasser apply fade values to the scanner's master dimmer channel because
 						// scanners doesn't have a rgb color mixing capability so the wheel has to be faded using the master.
 						master = int(float64(cmd.Master) / 100 * (float64(fade) / 2.55))
 					}
-					MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, lastColor.RGBColor, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+					MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, lastColor.RGBColor, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 
 					// Control how long the fade take with the speed control.
 					time.Sleep((5 * time.Millisecond) * (time.Duration(cmd.RGBFade)))
+					// Hold the fade while frozen, still listening for stop commands.
+					select {
+					case <-stopFadeDown:
+						return
+					case <-common.FreezeReleased():
+					}
 				}
 				// Fade down complete, set lastColor to empty in the fixture.
 				command := common.FixtureCommand{
@@ -651,15 +667,15 @@ func fadeUpStatic(fixtureNumber int, cmd common.FixtureCommand, lastColor common
 				color := common.GetColorNameByRGB(lamp.Color)
 				// Find a suitable gobo based on the requested static lamp color.
 				scannerGobo := FindGobo(fixtureNumber, cmd.SequenceNumber, color, fixtures)
-				// Find a suitable color wheel settin based on the requested static lamp color.
-				scannerColor := FindColor(fixtureNumber, cmd.SequenceNumber, color, fixtures)
+				// Find the nearest color wheel setting to the requested static lamp color.
+				scannerColor := FindWheelColor(fixtureNumber, cmd.SequenceNumber, lamp.Color, fixtures)
 
 				// Fade up fixture.
 				for _, fade := range fadeUpValues {
 					// Listen for stop command.
 					select {
 					case <-stopFadeUp:
-						lastColor = MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+						lastColor = MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 						return
 					case <-time.After(10 * time.Millisecond):
 					}
@@ -669,12 +685,19 @@ func fadeUpStatic(fixtureNumber int, cmd common.FixtureCommand, lastColor common
 						master = int(float64(cmd.Master) / 100 * (float64(fade) / 2.55))
 					}
 					if !cmd.Hidden {
-						common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, lamp.Color, fade, eventsForLaunchpad, guiButtons)
+						common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, lamp.Color, fade, eventsForLaunchpad, guiButtons)
 					}
-					lastColor = MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, lamp.Color, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+					lastColor = MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, lamp.Color, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 
 					// Control how long the fade take with the speed control.
 					time.Sleep((5 * time.Millisecond) * (time.Duration(cmd.RGBFade)))
+					// Hold the fade while frozen, still listening for stop commands.
+					select {
+					case <-stopFadeUp:
+						lastColor = MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+						return
+					case <-common.FreezeReleased():
+					}
 				}
 				// Fade up complete, set lastColor up in the fixture.
 				command := common.FixtureCommand{
@@ -720,8 +743,8 @@ func staticOff(fixtureNumber int, cmd common.FixtureCommand, lastColor common.La
 				color := common.GetColorNameByRGB(lastColor.RGBColor)
 				// Find a suitable gobo based on the requested static lamp color.
 				scannerGobo := FindGobo(fixtureNumber, cmd.SequenceNumber, color, fixtures)
-				// Find a suitable color wheel settin based on the requested static lamp color.
-				scannerColor := FindColor(fixtureNumber, cmd.SequenceNumber, color, fixtures)
+				// Find the nearest color wheel setting to the requested static lamp color.
+				scannerColor := FindWheelColor(fixtureNumber, cmd.SequenceNumber, lastColor.RGBColor, fixtures)
 
 				// Listen for stop commands.
 				select {
@@ -731,16 +754,26 @@ func staticOff(fixtureNumber int, cmd common.FixtureCommand, lastColor common.La
 					return
 				case <-time.After(10 * time.Millisecond):
 				}
-				common.LightLamp(common.Button{X: fixtureNumber, Y: sequenceNumber}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+				if common.FixtureOnPage(fixtureNumber, cmd.FixturePage) {
+					common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(sequenceNumber)}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+				}
 				if cmd.Label == "chaser" {
 					// If we are a RGB chaser used as a shutter chasser apply fade values to the scanner's master dimmer channel because
 					// scanners doesn't have a rgb color mixing capability so the wheel has to be faded using the master.
 					master = int(float64(cmd.Master) / 100 * (float64(fade) / 2.55))
 				}
-				MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, lastColor.RGBColor, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+				MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, lastColor.RGBColor, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, fade, master, 0, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 
 				// Control how long the fade take with the speed control.
 				time.Sleep((5 * time.Millisecond) * (time.Duration(cmd.RGBFade)))
+				// Hold the fade while frozen, still listening for stop commands.
+				select {
+				case <-stopFadeDown:
+					return
+				case <-stopFadeUp:
+					return
+				case <-common.FreezeReleased():
+				}
 			}
 			// Fade down complete, set lastColor to empty in the fixture.
 			command := common.FixtureCommand{
@@ -779,7 +812,7 @@ func playRGB(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixtures, e
 		if cmd.Label == "chaser" {
 			scannerFixturesSequenceNumber := common.GlobalScannerSequenceNumber // Scanner sequence number from config.
 			if !cmd.Hidden {
-				common.LightLamp(common.Button{X: fixtureNumber, Y: scannerFixturesSequenceNumber}, fixture.Color, fixture.Brightness, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(scannerFixturesSequenceNumber)}, fixture.Color, fixture.Brightness, eventsForLaunchpad, guiButtons)
 			}
 
 			// Fixture brightness is sent as master in this case because a shutter chaser is controlling a scanner lamp.
@@ -793,15 +826,15 @@ func playRGB(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixtures, e
 
 			// Find a suitable gobo based on the requested chaser lamp color.
 			scannerGobo := FindGobo(fixtureNumber, scannerFixturesSequenceNumber, color, fixtures)
-			// Find a suitable color wheel setting based on the requested static lamp color.
-			scannerColor := FindColor(fixtureNumber, scannerFixturesSequenceNumber, color, fixtures)
+			// Find the nearest color wheel setting to the requested chaser lamp color.
+			scannerColor := FindWheelColor(fixtureNumber, scannerFixturesSequenceNumber, fixture.BaseColor, fixtures)
 
-			lastColor = MapFixtures(true, cmd.ScannerChaser, scannerFixturesSequenceNumber, fixtureNumber, fixture.Color, 0, 0, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, cmd.Master, fixture.Brightness, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(dmx.SequenceSource(scannerFixturesSequenceNumber), true, cmd.ScannerChaser, scannerFixturesSequenceNumber, fixtureNumber, fixture.Color, 0, 0, 0, 0, 0, scannerGobo, scannerColor, fixtures, cmd.Blackout, cmd.Master, fixture.Brightness, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 		} else {
 			if !cmd.Hidden {
-				common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, fixture.Color, cmd.Master, eventsForLaunchpad, guiButtons)
+				common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, fixture.Color, cmd.Master, eventsForLaunchpad, guiButtons)
 			}
-			lastColor = MapFixtures(false, cmd.ScannerChaser, cmd.SequenceNumber, fixtureNumber, fixture.Color, 0, 0, 0, 0, 0, cmd.ScannerGobo, cmd.ScannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, cmd.ScannerChaser, cmd.SequenceNumber, fixtureNumber, fixture.Color, 0, 0, 0, 0, 0, cmd.ScannerGobo, cmd.ScannerColor, fixtures, cmd.Blackout, cmd.Master, cmd.Master, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 		}
 	}
 
@@ -831,7 +864,7 @@ func playScanner(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixture
 		// at this stage.
 		scannerBrightness := int(math.Round((float64(fixture.Brightness) / 100) * (float64(cmd.Master) / 2.55)))
 		// Tell the scanner what to do.
-		lastColor = MapFixtures(false, cmd.ScannerChaser, cmd.SequenceNumber, fixtureNumber, fixture.ScannerColor, fixture.Pan, fixture.Tilt,
+		lastColor = mapFixtures(true, dmx.SequenceSource(cmd.SequenceNumber), false, cmd.ScannerChaser, cmd.SequenceNumber, fixtureNumber, fixture.ScannerColor, fixture.Pan, fixture.Tilt,
 			fixture.Shutter, cmd.Rotate, cmd.Program, cmd.ScannerGobo, cmd.ScannerColor, fixtures, cmd.Blackout, cmd.Master, scannerBrightness, cmd.Music, cmd.Strobe, cmd.StrobeSpeed, dmxController, dmxInterfacePresent)
 
 		// Scannner is rotating, work out what to do with the launchpad lamps.
@@ -841,14 +874,14 @@ func playScanner(fixtureNumber int, cmd common.FixtureCommand, fixtures *Fixture
 			if howOftern != 0 {
 				if cmd.Step%howOftern == 0 {
 					// We're not in chase mode so use the color generated in the pattern generator.common.
-					common.LightLamp(common.Button{X: fixtureNumber, Y: cmd.SequenceNumber}, fixture.Color, cmd.Master, eventsForLaunchpad, guiButtons)
-					common.LabelButton(fixtureNumber, cmd.SequenceNumber, "", guiButtons)
+					common.LightLamp(common.Button{X: common.FixtureColumn(fixtureNumber), Y: common.SequenceRow(cmd.SequenceNumber)}, fixture.Color, cmd.Master, eventsForLaunchpad, guiButtons)
+					common.LabelButton(common.FixtureColumn(fixtureNumber), common.SequenceRow(cmd.SequenceNumber), "", guiButtons)
 				}
 			}
 		}
 	} else {
 		// This scanner is disabled, shut it off.
-		lastColor = MapFixtures(false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+		lastColor = MapFixtures(dmx.SequenceSource(cmd.SequenceNumber), false, false, cmd.SequenceNumber, fixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixtures, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 	}
 
 	return lastColor
@@ -859,6 +892,8 @@ func MapFixturesColorOnly(sequenceNumber, selectedFixture, selectedColor int, dm
 		fmt.Printf("MapFixturesColorOnly Sequence %d Fixture %d Gobo %d \n", sequenceNumber, selectedFixture, selectedColor)
 	}
 
+	source := dmx.SequenceSource(sequenceNumber)
+
 	for _, fixture := range fixtures.Fixtures {
 		// Match only this fixture.
 		if fixture.Group-1 == sequenceNumber {
@@ -872,7 +907,7 @@ func MapFixturesColorOnly(sequenceNumber, selectedFixture, selectedColor int, dm
 							for _, setting := range channel.Settings {
 								if setting.Number-1 == selectedColor {
 									v, _ := strconv.ParseFloat(setting.Value, 32)
-									SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+									SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 								}
 							}
 						}
@@ -1063,6 +1098,8 @@ func MapFixturesGoboOnly(sequenceNumber, selectedFixture, selectedGobo int, fixt
 		fmt.Printf("MapFixturesGoboOnly Sequence %d Fixture %d Gobo %d \n", sequenceNumber, selectedFixture, selectedGobo)
 	}
 
+	source := dmx.SequenceSource(sequenceNumber)
+
 	for _, fixture := range fixtures.Fixtures {
 		// Match only this sequence.
 		if fixture.Group-1 == sequenceNumber {
@@ -1073,7 +1110,7 @@ func MapFixturesGoboOnly(sequenceNumber, selectedFixture, selectedGobo int, fixt
 						for _, setting := range channel.Settings {
 							if setting.Number == selectedGobo {
 								v, _ := strconv.Atoi(setting.Value)
-								SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 							}
 						}
 					}
@@ -1085,7 +1122,24 @@ func MapFixturesGoboOnly(sequenceNumber, selectedFixture, selectedGobo int, fixt
 
 // When want to light a DMX fixture we need for find it in our fuxture.yaml configuration file.
 // This function maps the requested fixture into a DMX address.
-func MapFixtures(chaser bool, hadShutterChase bool,
+// The source names the output layer the values are written into.
+func MapFixtures(source string, chaser bool, hadShutterChase bool,
+	mySequenceNumber int,
+	displayFixture int,
+	color common.Color,
+	pan int, tilt int, shutter int, rotate int, program int, selectedGobo int, scannerColor int,
+	fixtures *Fixtures, blackout bool, brightness int, master int, music int, strobe bool, strobeSpeed int,
+	dmxController *ft232.DMXController, dmxInterfacePresent bool) (lastColor common.LastColor) {
+
+	return mapFixtures(false, source, chaser, hadShutterChase, mySequenceNumber, displayFixture, color,
+		pan, tilt, shutter, rotate, program, selectedGobo, scannerColor,
+		fixtures, blackout, brightness, master, music, strobe, strobeSpeed, dmxController, dmxInterfacePresent)
+}
+
+// mapFixtures does the work for MapFixtures.
+// Record is set when the pan and tilt are the scanners real position, so the keep out zones and the shutter
+// chaser can find out where the scanner is pointing. Static, flood, flash and switched off scanners don't record.
+func mapFixtures(record bool, source string, chaser bool, hadShutterChase bool,
 	mySequenceNumber int,
 	displayFixture int,
 	color common.Color,
@@ -1097,22 +1151,58 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 		fmt.Printf("MapFixtures Fixture No %d Sequence No %d\n", displayFixture, mySequenceNumber)
 	}
 
-	// We control the brightness of each color with the brightness value.
-	// The overall fixture brightness is set from the master value.
-	Red := (float64(color.R) / 100) * (float64(brightness) / 2.55)
-	Green := (float64(color.G) / 100) * (float64(brightness) / 2.55)
-	Blue := (float64(color.B) / 100) * (float64(brightness) / 2.55)
-	White := (float64(color.W) / 100) * (float64(brightness) / 2.55)
-	Amber := (float64(color.A) / 100) * (float64(brightness) / 2.55)
-	UV := (float64(color.UV) / 100) * (float64(brightness) / 2.55)
-
 	for _, fixture := range fixtures.Fixtures {
 		if fixture.Group == mySequenceNumber+1 {
+
+			// Mix the requested RGB color into any white, amber or UV emitters this fixture has.
+			mixed := MixColor(color, getEmitters(&fixture), fixture.ColorMix)
+
+			// We control the brightness of each color with the brightness value.
+			// The overall fixture brightness is set from the master value.
+			Red := (float64(mixed.R) / 100) * (float64(brightness) / 2.55)
+			Green := (float64(mixed.G) / 100) * (float64(brightness) / 2.55)
+			Blue := (float64(mixed.B) / 100) * (float64(brightness) / 2.55)
+			White := (float64(mixed.W) / 100) * (float64(brightness) / 2.55)
+			Amber := (float64(mixed.A) / 100) * (float64(brightness) / 2.55)
+			UV := (float64(mixed.UV) / 100) * (float64(brightness) / 2.55)
+
+			// Apply this fixtures calibration to the color and dimmer values.
+			red := calibrateColor(int(Red), "red", fixture.Calibration)
+			green := calibrateColor(int(Green), "green", fixture.Calibration)
+			blue := calibrateColor(int(Blue), "blue", fixture.Calibration)
+			white := calibrateColor(int(White), "white", fixture.Calibration)
+			amber := calibrateColor(int(Amber), "amber", fixture.Calibration)
+			uv := calibrateColor(int(UV), "uv", fixture.Calibration)
+			dimmer := calibrateDimmer(master, fixture.Calibration)
+
+			var fixturePan, fixtureTilt int
+			var blank bool
+			if fixture.Number == displayFixture+1 || fixture.MultiFixtureDevice {
+				// Apply the scanners orientation, range limits and keep out zones.
+				// The shutter chaser doesn't know where the scanner is pointing so uses the last known blanking.
+				panOffset, tiltOffset := findPanTiltOffsets(&fixture)
+				fixturePan, fixtureTilt, blank = ApplyLimits(pan+panOffset, tilt+tiltOffset, fixture.Limits)
+				if fixture.Limits != nil {
+					if chaser {
+						blank = isFixtureBlanked(fixture.Address)
+					} else if record {
+						setFixtureBlanked(fixture.Address, blank)
+						setFixturePosition(fixture.Address, fixturePan, fixtureTilt)
+					}
+				}
+
+				// Fixtures without a strobe of their own are strobed by the output stage.
+				setSoftwareStrobe(&fixture, strobe && !blackout, strobeSpeed)
+			}
+			if blank {
+				dimmer = 0
+			}
+
 			for channelNumber, channel := range fixture.Channels {
 
 				// Right of the bat if we're blacked out, set the channel to 0 and our work here is done.
 				if blackout {
-					SetChannel(fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
+					SetChannel(source, fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
 					continue
 				}
 
@@ -1121,55 +1211,51 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 					if !chaser {
 						// Scanner channels
 						if strings.Contains(channel.Name, "Pan") {
-							if channel.Offset != nil {
-								SetChannel(fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, pan+*channel.Offset)), dmxController, dmxInterfacePresent)
-							} else {
-								SetChannel(fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, pan)), dmxController, dmxInterfacePresent)
-							}
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, fixturePan)), dmxController, dmxInterfacePresent)
 						}
 						if strings.Contains(channel.Name, "Tilt") {
-							if channel.Offset != nil {
-								SetChannel(fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, tilt+*channel.Offset)), dmxController, dmxInterfacePresent)
-							}
-							SetChannel(fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, tilt)), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(limitDmxValue(channel.MaxDegrees, fixtureTilt)), dmxController, dmxInterfacePresent)
 						}
-						if strings.Contains(channel.Name, "Shutter") {
+						if strings.Contains(channel.Name, "Shutter") && blank {
+							// Inside a keep out zone so close the shutter.
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
+						} else if strings.Contains(channel.Name, "Shutter") {
 							// If we have defined settings for the shutter channel, then use them.
 							if channel.Settings != nil {
 								// Look through any settings configured for Shutter.
 								for _, s := range channel.Settings {
 									if !strobe && (s.Name == "On" || s.Name == "Open") {
 										v := calcFinalValueBasedOnConfigAndSettingValue(s.Value, shutter)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 									if strobe && strings.Contains(s.Name, "Strobe") {
 										v := calcFinalValueBasedOnConfigAndSettingValue(s.Value, strobeSpeed)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 								}
 							} else {
 								// Ok no settings. so send out the strobe speed as a 0-255 on the Shutter channel.
-								SetChannel(fixture.Address+int16(channelNumber), byte(shutter), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(shutter), dmxController, dmxInterfacePresent)
 							}
 						}
 						if strings.Contains(channel.Name, "Rotate") {
-							SetChannel(fixture.Address+int16(channelNumber), byte(rotate), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(rotate), dmxController, dmxInterfacePresent)
 						}
 						if strings.Contains(channel.Name, "Music") {
-							SetChannel(fixture.Address+int16(channelNumber), byte(music), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(music), dmxController, dmxInterfacePresent)
 						}
 						if strings.Contains(channel.Name, "Program") {
-							SetChannel(fixture.Address+int16(channelNumber), byte(program), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(program), dmxController, dmxInterfacePresent)
 						}
 						if strings.Contains(channel.Name, "ProgramSpeed") {
-							SetChannel(fixture.Address+int16(channelNumber), byte(program), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(program), dmxController, dmxInterfacePresent)
 						}
 						if !hadShutterChase {
 							if strings.Contains(channel.Name, "Gobo") {
 								for _, setting := range channel.Settings {
 									if setting.Number == selectedGobo {
 										v, _ := strconv.Atoi(setting.Value)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 								}
 							}
@@ -1179,16 +1265,16 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 								for _, setting := range channel.Settings {
 									if setting.Number-1 == scannerColor {
 										v, _ := strconv.Atoi(setting.Value)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 								}
 							}
 						}
 						if strings.Contains(channel.Name, "Strobe") {
 							if strobe {
-								SetChannel(fixture.Address+int16(channelNumber), byte(strobeSpeed), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(strobeSpeed), dmxController, dmxInterfacePresent)
 							} else {
-								SetChannel(fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
 							}
 						}
 						// Master Dimmer.
@@ -1199,14 +1285,14 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 									strings.Contains(channel.Name, "invert") ||
 									strings.Contains(channel.Name, "Invert") {
 									if debug {
-										fmt.Printf("MapFixtures: fixture %s: send ChannelName %s Address %d Value %d \n", fixture.Name, channel.Name, fixture.Address+int16(channelNumber), int(reverse_dmx(master)))
+										fmt.Printf("MapFixtures: fixture %s: send ChannelName %s Address %d Value %d \n", fixture.Name, channel.Name, fixture.Address+int16(channelNumber), int(reverse_dmx(dimmer)))
 									}
-									SetChannel(fixture.Address+int16(channelNumber), byte(reverse_dmx(master)), dmxController, dmxInterfacePresent)
+									SetChannel(source, fixture.Address+int16(channelNumber), byte(reverse_dmx(dimmer)), dmxController, dmxInterfacePresent)
 								} else {
 									if debug {
-										fmt.Printf("MapFixtures: fixture %s: send ChannelName %s Address %d Value %d \n", fixture.Name, channel.Name, fixture.Address+int16(channelNumber), master)
+										fmt.Printf("MapFixtures: fixture %s: send ChannelName %s Address %d Value %d \n", fixture.Name, channel.Name, fixture.Address+int16(channelNumber), dimmer)
 									}
-									SetChannel(fixture.Address+int16(channelNumber), byte(master), dmxController, dmxInterfacePresent)
+									SetChannel(source, fixture.Address+int16(channelNumber), byte(dimmer), dmxController, dmxInterfacePresent)
 								}
 							}
 						}
@@ -1217,29 +1303,32 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 								strings.Contains(channel.Name, "Reverse") ||
 								strings.Contains(channel.Name, "invert") ||
 								strings.Contains(channel.Name, "Invert") {
-								SetChannel(fixture.Address+int16(channelNumber), byte(reverse_dmx(master)), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(reverse_dmx(dimmer)), dmxController, dmxInterfacePresent)
 							} else {
-								SetChannel(fixture.Address+int16(channelNumber), byte(master), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(dimmer), dmxController, dmxInterfacePresent)
 							}
 						}
 						// Shutter
-						if strings.Contains(channel.Name, "Shutter") {
+						if strings.Contains(channel.Name, "Shutter") && blank {
+							// Inside a keep out zone so close the shutter.
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(0), dmxController, dmxInterfacePresent)
+						} else if strings.Contains(channel.Name, "Shutter") {
 							// If we have defined settings for the shutter channel, then use them.
 							if channel.Settings != nil {
 								// Look through any settings configured for Shutter.
 								for _, s := range channel.Settings {
 									if !strobe && (s.Name == "On" || s.Name == "Open") {
 										v := calcFinalValueBasedOnConfigAndSettingValue(s.Value, shutter)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 									if strobe && strings.Contains(s.Name, "Strobe") {
 										v := calcFinalValueBasedOnConfigAndSettingValue(s.Value, strobeSpeed)
-										SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+										SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 									}
 								}
 							} else {
 								// Ok no settings. so send out the strobe speed as a 0-255 on the Shutter channel.
-								SetChannel(fixture.Address+int16(channelNumber), byte(shutter), dmxController, dmxInterfacePresent)
+								SetChannel(source, fixture.Address+int16(channelNumber), byte(shutter), dmxController, dmxInterfacePresent)
 							}
 						}
 						// Scanner Color
@@ -1247,7 +1336,7 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 							for _, setting := range channel.Settings {
 								if setting.Number-1 == scannerColor {
 									v, _ := strconv.Atoi(setting.Value)
-									SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+									SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 								}
 							}
 						}
@@ -1256,7 +1345,7 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 							for _, setting := range channel.Settings {
 								if setting.Number == selectedGobo {
 									v, _ := strconv.Atoi(setting.Value)
-									SetChannel(fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
+									SetChannel(source, fixture.Address+int16(channelNumber), byte(v), dmxController, dmxInterfacePresent)
 								}
 							}
 						}
@@ -1266,27 +1355,27 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 					// Static value.
 					if strings.Contains(channel.Name, "Static") {
 						if channel.Value != nil {
-							SetChannel(fixture.Address+int16(channelNumber), byte(*channel.Value), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(channelNumber), byte(*channel.Value), dmxController, dmxInterfacePresent)
 						}
 					}
 					// Fixture channels.
 					if strings.Contains(channel.Name, "Red"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(Red)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(red), dmxController, dmxInterfacePresent)
 					}
 					if strings.Contains(channel.Name, "Green"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(Green)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(green), dmxController, dmxInterfacePresent)
 					}
 					if strings.Contains(channel.Name, "Blue"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(Blue)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(blue), dmxController, dmxInterfacePresent)
 					}
 					if strings.Contains(channel.Name, "White"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(White)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(white), dmxController, dmxInterfacePresent)
 					}
 					if strings.Contains(channel.Name, "Amber"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(Amber)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(amber), dmxController, dmxInterfacePresent)
 					}
 					if strings.Contains(channel.Name, "UV"+strconv.Itoa(displayFixture+1)) {
-						SetChannel(fixture.Address+int16(channelNumber), byte(int(UV)), dmxController, dmxInterfacePresent)
+						SetChannel(source, fixture.Address+int16(channelNumber), byte(uv), dmxController, dmxInterfacePresent)
 					}
 				}
 			}
@@ -1299,6 +1388,22 @@ func MapFixtures(chaser bool, hadShutterChase bool,
 	return lastColor
 }
 
+// findPanTiltOffsets returns any offsets configured on the pan and tilt channels of this fixture.
+func findPanTiltOffsets(fixture *Fixture) (panOffset int, tiltOffset int) {
+	for _, channel := range fixture.Channels {
+		if channel.Offset == nil {
+			continue
+		}
+		if strings.Contains(channel.Name, "Pan") {
+			panOffset = *channel.Offset
+		}
+		if strings.Contains(channel.Name, "Tilt") {
+			tiltOffset = *channel.Offset
+		}
+	}
+	return panOffset, tiltOffset
+}
+
 func calcFinalValueBasedOnConfigAndSettingValue(configValue string, settingValue int) (final int) {
 
 	if debug {
@@ -1329,12 +1434,14 @@ func calcFinalValueBasedOnConfigAndSettingValue(configValue string, settingValue
 	}
 }
 
-func SetChannel(index int16, data byte, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
+// SetChannel writes a value into the source's output layer.
+func SetChannel(source string, index int16, data byte, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
 	if dmxDebug {
-		fmt.Printf("DMX Debug    Channel %d Value %d\n", index, data)
+		fmt.Printf("DMX Debug    %s Channel %d Value %d\n", source, index, data)
 	}
 	if dmxInterfacePresent {
-		dmxController.SetChannel(index, data)
+		// The output stage merges the layers and sends the value on the next DMX frame.
+		dmx.SetChannel(source, index, data)
 	}
 }
 
@@ -1360,6 +1467,9 @@ func MapSwitchFixture(swiTch common.Switch,
 		fmt.Printf("MapSwitchFixture switchNumber %d, current position %d fade speed %d\n", swiTch.Number, swiTch.CurrentPosition, RGBFade)
 	}
 
+	// Each switch writes into its own output layer, so it can share fixtures with a sequence.
+	source := dmx.SwitchSource(swiTch.Number)
+
 	// We start by having the switch and its current state passed in.
 
 	// Now we find the fixture used by the switch
@@ -1391,11 +1501,13 @@ func MapSwitchFixture(swiTch common.Switch,
 
 		// If blackout, set master to off.
 		if blackout {
+			// Don't let a fading setting bring the fixture back.
+			stopSettings(source)
 			// Blackout the fixture by setting master brightness to zero.
 			if debug {
 				fmt.Printf("SetChannel %d To Value %d\n", thisFixture.Address+int16(masterChannel), 0)
 			}
-			SetChannel(thisFixture.Address+int16(masterChannel), byte(0), dmxController, dmxInterfacePresent)
+			SetChannel(source, thisFixture.Address+int16(masterChannel), byte(0), dmxController, dmxInterfacePresent)
 			return lastColor
 		}
 
@@ -1438,10 +1550,13 @@ func MapSwitchFixture(swiTch common.Switch,
 		}
 
 		// Now play any preset DMX values directly to the universe.
-		// Step through all the settings.
+		// Step through all the settings, cancelling any fades left over from the last state.
+		fader := startSettings(source)
 		for _, newSetting := range state.Settings {
-			newMiniSetter(thisFixture, newSetting, masterChannel, dmxController, master, dmxInterfacePresent)
+			newMiniSetter(source, thisFixture, newSetting, masterChannel, dmxController, master, dmxInterfacePresent, fader)
 		}
+		// And fade out what the last state set that this one doesn't.
+		fader.finish(source, dmxController, dmxInterfacePresent)
 	}
 	return lastColor
 }
@@ -1649,6 +1764,84 @@ func FindColor(myFixtureNumber int, mySequenceNumber int, color string, fixtures
 	return 0
 }
 
+// FindWheelColor takes any RGB color and returns the nearest color wheel slot for this scanner.
+// The slot is zero based, the same as the sequences ScannerColor. Returns the first slot if the
+// fixture has no color wheel settings with a known color.
+func FindWheelColor(myFixtureNumber int, mySequenceNumber int, color common.Color, fixtures *Fixtures) int {
+
+	if debug {
+		fmt.Printf("FindWheelColor\n")
+	}
+
+	for _, fixture := range fixtures.Fixtures {
+		if fixture.Group == mySequenceNumber+1 && fixture.Number == myFixtureNumber+1 {
+			wheelColor, err := FindNearestWheelColor(&fixture, color)
+			if err != nil {
+				if debug {
+					fmt.Printf("FindWheelColor %s\n", err)
+				}
+				return 0
+			}
+			return wheelColor
+		}
+	}
+	return 0
+}
+
+// GetWheelSettingColor returns the RGB approximation of a color wheel setting.
+// Uses the settings rgb value if configured otherwise tries to match the setting name to a known color.
+func GetWheelSettingColor(setting Setting) (common.Color, error) {
+	if setting.RGB != nil {
+		return common.Color{R: setting.RGB.R, G: setting.RGB.G, B: setting.RGB.B, W: setting.RGB.W}, nil
+	}
+	return common.GetRGBColorByName(setting.Name)
+}
+
+// FindNearestWheelColor takes any RGB color and returns the nearest color wheel slot
+// for this fixture. The slot is returned as a zero based index, the same as the sequences ScannerColor.
+// Returns an error if the fixture has no color wheel settings with a known color.
+func FindNearestWheelColor(fixture *Fixture, color common.Color) (int, error) {
+
+	if debug {
+		fmt.Printf("FindNearestWheelColor fixture %s color %+v\n", fixture.Name, color)
+	}
+
+	found := false
+	nearest := 0
+	shortest := math.MaxFloat64
+
+	for _, channel := range fixture.Channels {
+		if strings.Contains(channel.Name, "Color") {
+			for _, setting := range channel.Settings {
+				wheelColor, err := GetWheelSettingColor(setting)
+				if err != nil {
+					continue
+				}
+				distance := colorDistance(color, wheelColor)
+				if distance < shortest {
+					shortest = distance
+					nearest = setting.Number - 1
+					found = true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no color wheel colors found for fixture %s", fixture.Name)
+	}
+
+	return nearest, nil
+}
+
+// colorDistance returns the distance between two colors in RGB space.
+func colorDistance(a common.Color, b common.Color) float64 {
+	r := float64(a.R - b.R)
+	g := float64(a.G - b.G)
+	bl := float64(a.B - b.B)
+	return math.Sqrt(r*r + g*g + bl*bl)
+}
+
 func FindChannelNumberByName(fixture *Fixture, channelName string) (int, error) {
 
 	if debug {
@@ -1693,6 +1886,10 @@ func isThisAChannel(thisFixture Fixture, channelName string) bool {
 // returns true is they are the same.
 func CheckFixturesAreTheSame(fixtures *Fixtures, startConfig *Fixtures) (bool, string) {
 
+	if !reflect.DeepEqual(fixtures.CueLists, startConfig.CueLists) {
+		return false, "Cue lists are different"
+	}
+
 	if len(fixtures.Fixtures) != len(startConfig.Fixtures) {
 		return false, "Number of fixtures are different"
 	}
@@ -17,7 +17,12 @@
 package fixture
 
 import (
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
 )
 
 func Test_calculateMaxDMX(t *testing.T) {
@@ -332,3 +337,1230 @@ func TestCheckFixturesAreTheSame(t *testing.T) {
 		})
 	}
 }
+
+func TestMixColor(t *testing.T) {
+
+	off := 0
+	half := 50
+
+	type args struct {
+		color    common.Color
+		emitters Emitters
+		mix      *ColorMix
+	}
+	tests := []struct {
+		name string
+		args args
+		want common.Color
+	}{
+		{
+			name: "RGB only fixture is untouched",
+			args: args{
+				color:    common.Color{R: 255, G: 255, B: 255},
+				emitters: Emitters{},
+			},
+			want: common.Color{R: 255, G: 255, B: 255},
+		},
+		{
+			name: "fixture without a colormix config is untouched",
+			args: args{
+				color:    common.Color{R: 255, G: 255, B: 255},
+				emitters: Emitters{HasWhite: true, HasAmber: true},
+			},
+			want: common.Color{R: 255, G: 255, B: 255},
+		},
+		{
+			name: "white extracted from white",
+			args: args{
+				color:    common.Color{R: 255, G: 255, B: 255},
+				emitters: Emitters{HasWhite: true},
+				mix:      &ColorMix{},
+			},
+			want: common.Color{R: 0, G: 0, B: 0, W: 255},
+		},
+		{
+			name: "white extracted from light blue",
+			args: args{
+				color:    common.Color{R: 0, G: 196, B: 255},
+				emitters: Emitters{HasWhite: true},
+				mix:      &ColorMix{},
+			},
+			want: common.Color{R: 0, G: 196, B: 255},
+		},
+		{
+			name: "half white extraction",
+			args: args{
+				color:    common.Color{R: 200, G: 200, B: 200},
+				emitters: Emitters{HasWhite: true},
+				mix:      &ColorMix{White: &half},
+			},
+			want: common.Color{R: 100, G: 100, B: 100, W: 100},
+		},
+		{
+			name: "amber from orange",
+			args: args{
+				color:    common.Color{R: 255, G: 111, B: 0},
+				emitters: Emitters{HasWhite: true, HasAmber: true},
+				mix:      &ColorMix{},
+			},
+			want: common.Color{R: 107, G: 0, B: 0, A: 148},
+		},
+		{
+			name: "amber turned off",
+			args: args{
+				color:    common.Color{R: 255, G: 111, B: 0},
+				emitters: Emitters{HasWhite: true, HasAmber: true},
+				mix:      &ColorMix{Amber: &off},
+			},
+			want: common.Color{R: 255, G: 111, B: 0},
+		},
+		{
+			name: "uv from blue",
+			args: args{
+				color:    common.Color{R: 0, G: 0, B: 255},
+				emitters: Emitters{HasUV: true},
+				mix:      &ColorMix{UV: &half},
+			},
+			want: common.Color{R: 0, G: 0, B: 255, UV: 127},
+		},
+		{
+			name: "mixing disabled",
+			args: args{
+				color:    common.Color{R: 255, G: 255, B: 255},
+				emitters: Emitters{HasWhite: true, HasAmber: true},
+				mix:      &ColorMix{Disabled: true},
+			},
+			want: common.Color{R: 255, G: 255, B: 255},
+		},
+		{
+			name: "explicit white is left alone",
+			args: args{
+				color:    common.Color{R: 255, G: 255, B: 255, W: 10},
+				emitters: Emitters{HasWhite: true},
+				mix:      &ColorMix{},
+			},
+			want: common.Color{R: 255, G: 255, B: 255, W: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MixColor(tt.args.color, tt.args.emitters, tt.args.mix); got != tt.want {
+				t.Errorf("MixColor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEmitters(t *testing.T) {
+	fixtures := &Fixtures{
+		Fixtures: []Fixture{
+			{Group: 7, Number: 1, Channels: []Channel{{Name: "Red"}, {Name: "White"}}},
+			{Group: 7, Number: 2, Channels: []Channel{{Name: "Red"}, {Name: "Amber"}, {Name: "UV"}}},
+		},
+	}
+	registerEmitters(fixtures)
+
+	if got := getEmitters(&fixtures.Fixtures[0]); got != (Emitters{HasWhite: true}) {
+		t.Errorf("getEmitters() first fixture = %+v", got)
+	}
+	if got := getEmitters(&fixtures.Fixtures[1]); got != (Emitters{HasAmber: true, HasUV: true}) {
+		t.Errorf("getEmitters() second fixture = %+v", got)
+	}
+	// A fixture which wasn't registered has its channels searched.
+	if got := getEmitters(&Fixture{Group: 8, Number: 1, Channels: []Channel{{Name: "White"}}}); got != (Emitters{HasWhite: true}) {
+		t.Errorf("getEmitters() unregistered fixture = %+v", got)
+	}
+}
+
+func TestFindNearestWheelColor(t *testing.T) {
+
+	scanner := &Fixture{
+		Name: "scanner",
+		Channels: []Channel{
+			{
+				Name: "Color",
+				Settings: []Setting{
+					{Name: "White", Number: 1},
+					{Name: "Red", Number: 2},
+					{Name: "Deep Lavender", Number: 3, RGB: &Color{R: 180, G: 0, B: 220}},
+					{Name: "Green", Number: 4},
+					{Name: "Open", Number: 5},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		fixture *Fixture
+		color   common.Color
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "exact match by name",
+			fixture: scanner,
+			color:   common.Red,
+			want:    1,
+		},
+		{
+			name:    "magenta is nearest the configured rgb",
+			fixture: scanner,
+			color:   common.Magenta,
+			want:    2,
+		},
+		{
+			name:    "pink is nearest white",
+			fixture: scanner,
+			color:   common.Pink,
+			want:    0,
+		},
+		{
+			name:    "lawn green is nearest green",
+			fixture: scanner,
+			color:   common.LawnGreen,
+			want:    3,
+		},
+		{
+			name:    "no color wheel",
+			fixture: &Fixture{Name: "par", Channels: []Channel{{Name: "Red1"}}},
+			color:   common.Red,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindNearestWheelColor(tt.fixture, tt.color)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FindNearestWheelColor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FindNearestWheelColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindWheelColor(t *testing.T) {
+
+	fixtures := &Fixtures{
+		Fixtures: []Fixture{
+			{
+				Name:   "par",
+				Group:  1,
+				Number: 1,
+				Channels: []Channel{
+					{Name: "Red1"},
+				},
+			},
+			{
+				Name:   "scanner",
+				Group:  2,
+				Number: 2,
+				Channels: []Channel{
+					{
+						Name: "Color",
+						Settings: []Setting{
+							{Name: "White", Number: 1},
+							{Name: "Red", Number: 2},
+							{Name: "Deep Lavender", Number: 3, RGB: &Color{R: 180, G: 0, B: 220}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		fixtureNumber  int
+		sequenceNumber int
+		color          common.Color
+		want           int
+	}{
+		{
+			name:           "white is the first slot",
+			fixtureNumber:  1,
+			sequenceNumber: 1,
+			color:          common.White,
+			want:           0,
+		},
+		{
+			name:           "magenta moves the wheel to lavender",
+			fixtureNumber:  1,
+			sequenceNumber: 1,
+			color:          common.Magenta,
+			want:           2,
+		},
+		{
+			name:           "no color wheel",
+			fixtureNumber:  0,
+			sequenceNumber: 0,
+			color:          common.Red,
+			want:           0,
+		},
+		{
+			name:           "no such fixture",
+			fixtureNumber:  5,
+			sequenceNumber: 1,
+			color:          common.Red,
+			want:           0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindWheelColor(tt.fixtureNumber, tt.sequenceNumber, tt.color, fixtures); got != tt.want {
+				t.Errorf("FindWheelColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalibrateDimmer(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		value       int
+		calibration *Calibration
+		want        int
+	}{
+		{
+			name:  "no calibration",
+			value: 100,
+			want:  100,
+		},
+		{
+			name:        "linear",
+			value:       100,
+			calibration: &Calibration{Curve: CURVE_LINEAR},
+			want:        100,
+		},
+		{
+			name:        "square",
+			value:       128,
+			calibration: &Calibration{Curve: CURVE_SQUARE},
+			want:        64,
+		},
+		{
+			name:        "s-curve mid point",
+			value:       128,
+			calibration: &Calibration{Curve: CURVE_SCURVE},
+			want:        128,
+		},
+		{
+			name:        "s-curve full",
+			value:       255,
+			calibration: &Calibration{Curve: CURVE_SCURVE},
+			want:        255,
+		},
+		{
+			name:        "lookup table interpolated",
+			value:       64,
+			calibration: &Calibration{Curve: CURVE_LUT, LUT: []int{0, 10, 100, 200, 255}},
+			want:        10,
+		},
+		{
+			name:        "lookup table full",
+			value:       255,
+			calibration: &Calibration{Curve: CURVE_LUT, LUT: []int{0, 10, 100, 200, 255}},
+			want:        255,
+		},
+		{
+			name:        "minimum clamp",
+			value:       2,
+			calibration: &Calibration{Min: 10},
+			want:        10,
+		},
+		{
+			name:        "off stays off",
+			value:       0,
+			calibration: &Calibration{Min: 10},
+			want:        0,
+		},
+		{
+			name:        "maximum clamp",
+			value:       255,
+			calibration: &Calibration{Max: 200},
+			want:        200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calibrateDimmer(tt.value, tt.calibration); got != tt.want {
+				t.Errorf("calibrateDimmer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalibrateColor(t *testing.T) {
+
+	half := 50
+
+	tests := []struct {
+		name        string
+		value       int
+		emitter     string
+		calibration *Calibration
+		want        int
+	}{
+		{
+			name:    "no calibration",
+			value:   200,
+			emitter: "red",
+			want:    200,
+		},
+		{
+			name:        "gain on the matching emitter",
+			value:       200,
+			emitter:     "blue",
+			calibration: &Calibration{Gain: &EmitterGain{Blue: &half}},
+			want:        100,
+		},
+		{
+			name:        "gain not set for this emitter",
+			value:       200,
+			emitter:     "red",
+			calibration: &Calibration{Gain: &EmitterGain{Blue: &half}},
+			want:        200,
+		},
+		{
+			name:        "gamma",
+			value:       128,
+			emitter:     "green",
+			calibration: &Calibration{Gamma: 2.2},
+			want:        56,
+		},
+		{
+			name:        "gamma full stays full",
+			value:       255,
+			emitter:     "green",
+			calibration: &Calibration{Gamma: 2.2},
+			want:        255,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calibrateColor(tt.value, tt.emitter, tt.calibration); got != tt.want {
+				t.Errorf("calibrateColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyLimits(t *testing.T) {
+
+	low := 50
+	high := 200
+
+	tests := []struct {
+		name      string
+		pan       int
+		tilt      int
+		limits    *Limits
+		wantPan   int
+		wantTilt  int
+		wantBlank bool
+	}{
+		{
+			name:     "no limits",
+			pan:      10,
+			tilt:     20,
+			wantPan:  10,
+			wantTilt: 20,
+		},
+		{
+			name:     "upside down",
+			pan:      10,
+			tilt:     20,
+			limits:   &Limits{InvertPan: true, InvertTilt: true},
+			wantPan:  245,
+			wantTilt: 235,
+		},
+		{
+			name:     "on its side",
+			pan:      10,
+			tilt:     20,
+			limits:   &Limits{Swap: true},
+			wantPan:  20,
+			wantTilt: 10,
+		},
+		{
+			name:     "range limits",
+			pan:      10,
+			tilt:     250,
+			limits:   &Limits{PanMin: &low, TiltMax: &high},
+			wantPan:  50,
+			wantTilt: 200,
+		},
+		{
+			name:     "outside keep out zone",
+			pan:      10,
+			tilt:     10,
+			limits:   &Limits{KeepOut: []KeepOut{{PanMin: 100, PanMax: 150, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_CLAMP}}},
+			wantPan:  10,
+			wantTilt: 10,
+		},
+		{
+			name:     "clamped to nearest edge of keep out zone",
+			pan:      140,
+			tilt:     10,
+			limits:   &Limits{KeepOut: []KeepOut{{PanMin: 100, PanMax: 150, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_CLAMP}}},
+			wantPan:  151,
+			wantTilt: 10,
+		},
+		{
+			name:      "blanked inside keep out zone",
+			pan:       140,
+			tilt:      10,
+			limits:    &Limits{KeepOut: []KeepOut{{PanMin: 100, PanMax: 150, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_BLANK}}},
+			wantPan:   140,
+			wantTilt:  10,
+			wantBlank: true,
+		},
+		{
+			name:      "keep out zone covering everything blanks",
+			pan:       140,
+			tilt:      10,
+			limits:    &Limits{KeepOut: []KeepOut{{PanMin: 0, PanMax: 255, TiltMin: 0, TiltMax: 255, Action: KEEPOUT_CLAMP}}},
+			wantPan:   140,
+			wantTilt:  10,
+			wantBlank: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pan, tilt, blank := ApplyLimits(tt.pan, tt.tilt, tt.limits)
+			if pan != tt.wantPan || tilt != tt.wantTilt || blank != tt.wantBlank {
+				t.Errorf("ApplyLimits() = %v %v %v, want %v %v %v", pan, tilt, blank, tt.wantPan, tt.wantTilt, tt.wantBlank)
+			}
+		})
+	}
+}
+
+func TestLimitSettingValue(t *testing.T) {
+
+	clamp := []KeepOut{{PanMin: 100, PanMax: 150, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_CLAMP}}
+	blank := []KeepOut{{PanMin: 100, PanMax: 150, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_BLANK}}
+	everywhere := []KeepOut{{PanMin: 0, PanMax: 255, TiltMin: 0, TiltMax: 255, Action: KEEPOUT_CLAMP}}
+
+	tests := []struct {
+		name     string
+		limits   *Limits
+		position fixturePosition // Where the fixture is pointing already.
+		channel  int
+		value    int
+		want     int
+	}{
+		{
+			name:    "no limits",
+			channel: 0,
+			value:   140,
+			want:    140,
+		},
+		{
+			name:    "not a pan or tilt channel",
+			limits:  &Limits{KeepOut: clamp},
+			channel: 2,
+			value:   140,
+			want:    140,
+		},
+		{
+			name:     "pan outside the zone",
+			limits:   &Limits{KeepOut: clamp},
+			position: fixturePosition{pan: 10, tilt: 10},
+			channel:  0,
+			value:    90,
+			want:     90,
+		},
+		{
+			name:     "pan into the zone is clamped to the nearest edge",
+			limits:   &Limits{KeepOut: clamp},
+			position: fixturePosition{pan: 10, tilt: 10},
+			channel:  0,
+			value:    140,
+			want:     151,
+		},
+		{
+			name:     "pan past the zone when tilted clear of it",
+			limits:   &Limits{KeepOut: clamp},
+			position: fixturePosition{pan: 10, tilt: 100},
+			channel:  0,
+			value:    140,
+			want:     140,
+		},
+		{
+			name:     "tilt into the zone is clamped to the nearest edge",
+			limits:   &Limits{KeepOut: clamp},
+			position: fixturePosition{pan: 120, tilt: 100},
+			channel:  1,
+			value:    10,
+			want:     51,
+		},
+		{
+			name:     "blanking zones are kept out of",
+			limits:   &Limits{KeepOut: blank},
+			position: fixturePosition{pan: 10, tilt: 10},
+			channel:  0,
+			value:    110,
+			want:     99,
+		},
+		{
+			name:     "nowhere to go stays put",
+			limits:   &Limits{KeepOut: everywhere},
+			position: fixturePosition{pan: 10, tilt: 10},
+			channel:  0,
+			value:    140,
+			want:     10,
+		},
+		{
+			name:     "range limits still apply",
+			limits:   &Limits{InvertPan: true, KeepOut: clamp},
+			position: fixturePosition{pan: 10, tilt: 10},
+			channel:  0,
+			value:    110,
+			want:     151,
+		},
+	}
+	for testNumber, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := &Fixture{
+				Address:  int16(500 + testNumber*10),
+				Limits:   tt.limits,
+				Channels: []Channel{{Name: "Pan"}, {Name: "Tilt"}, {Name: "Master"}},
+			}
+			setFixturePosition(fixture.Address, tt.position.pan, tt.position.tilt)
+			if got := limitSettingValue(fixture, tt.channel, tt.value); got != tt.want {
+				t.Errorf("limitSettingValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapFixturesRecordsEachFixture(t *testing.T) {
+
+	limits := &Limits{KeepOut: []KeepOut{{PanMin: 0, PanMax: 50, TiltMin: 0, TiltMax: 50, Action: KEEPOUT_BLANK}}}
+	fixtures := &Fixtures{
+		Fixtures: []Fixture{
+			{Number: 1, Group: 1, Address: 600, Limits: limits, Channels: []Channel{{Name: "Pan"}, {Name: "Tilt"}, {Name: "Master"}}},
+			{Number: 2, Group: 1, Address: 620, Limits: limits, Channels: []Channel{{Name: "Pan"}, {Name: "Tilt"}, {Name: "Master"}}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		fixture     int
+		pan         int
+		tilt        int
+		wantBlanked bool
+	}{
+		{
+			name:        "first scanner inside the blanking zone",
+			fixture:     0,
+			pan:         10,
+			tilt:        10,
+			wantBlanked: true,
+		},
+		{
+			name:        "second scanner clear of the zone",
+			fixture:     1,
+			pan:         200,
+			tilt:        150,
+			wantBlanked: false,
+		},
+	}
+
+	// Position both scanners in the group.
+	for _, tt := range tests {
+		mapFixtures(true, "test", false, false, 0, tt.fixture, common.White, tt.pan, tt.tilt, 0, 0, 0, 0, 0, fixtures, false, 255, 255, 0, false, 0, nil, false)
+	}
+
+	// A static scanner at the mid point doesn't move the recorded positions.
+	MapFixtures("test", false, false, 0, 0, common.White, common.SCANNER_MID_POINT, common.SCANNER_MID_POINT, 0, 0, 0, 0, 0, fixtures, false, 255, 255, 0, false, 0, nil, false)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := fixtures.Fixtures[tt.fixture].Address
+			if got := getFixturePosition(address); got.pan != tt.pan || got.tilt != tt.tilt {
+				t.Errorf("getFixturePosition() = %+v, want pan %d tilt %d", got, tt.pan, tt.tilt)
+			}
+			if got := isFixtureBlanked(address); got != tt.wantBlanked {
+				t.Errorf("isFixtureBlanked() = %v, want %v", got, tt.wantBlanked)
+			}
+		})
+	}
+}
+
+func TestFindStrobeChannels(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		fixture      *Fixture
+		wantHardware bool
+		want         map[int16]byte
+	}{
+		{
+			name: "par with a strobe channel",
+			fixture: &Fixture{Address: 1, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"}, {Name: "Strobe"},
+			}},
+			wantHardware: true,
+			want:         map[int16]byte{1: 0, 2: 0, 3: 0},
+		},
+		{
+			name: "scanner with a strobe shutter setting",
+			fixture: &Fixture{Address: 10, Channels: []Channel{
+				{Name: "Pan"}, {Name: "Shutter", Settings: []Setting{{Name: "Open"}, {Name: "Strobe"}}}, {Name: "Master"},
+			}},
+			wantHardware: true,
+			want:         map[int16]byte{12: 0},
+		},
+		{
+			name: "cheap par flashes its colors",
+			fixture: &Fixture{Address: 20, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"}, {Name: "White1"}, {Name: "Program"},
+			}},
+			want: map[int16]byte{20: 0, 21: 0, 22: 0, 23: 0},
+		},
+		{
+			name: "reversed dimmer is off at full",
+			fixture: &Fixture{Address: 30, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Dimmer reverse"},
+			}},
+			want: map[int16]byte{31: 255},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasHardwareStrobe(tt.fixture); got != tt.wantHardware {
+				t.Errorf("hasHardwareStrobe() = %v, want %v", got, tt.wantHardware)
+			}
+			if got := findStrobeChannels(tt.fixture); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findStrobeChannels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindIntensityChannels(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		fixture *Fixture
+		want    map[int16]byte
+	}{
+		{
+			name: "scanner master",
+			fixture: &Fixture{Address: 10, Channels: []Channel{
+				{Name: "Pan"}, {Name: "Tilt"}, {Name: "Master"},
+			}},
+			want: map[int16]byte{12: 0},
+		},
+		{
+			name: "reversed dimmer",
+			fixture: &Fixture{Address: 30, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Dimmer inverted"},
+			}},
+			want: map[int16]byte{31: 255},
+		},
+		{
+			name: "colors only have no intensity channel",
+			fixture: &Fixture{Address: 20, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"},
+			}},
+			want: map[int16]byte{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findIntensityChannels(tt.fixture); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findIntensityChannels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindFadeChannels(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		fixture *Fixture
+		want    []int16
+	}{
+		{
+			name: "scanner",
+			fixture: &Fixture{Address: 10, Channels: []Channel{
+				{Name: "Pan"}, {Name: "PanFine"}, {Name: "Tilt"}, {Name: "Gobo"}, {Name: "Master"}, {Name: "Shutter"},
+			}},
+			want: []int16{10, 11, 12, 14},
+		},
+		{
+			name: "par",
+			fixture: &Fixture{Address: 20, Channels: []Channel{
+				{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"}, {Name: "Program"}, {Name: "Dimmer reverse"},
+			}},
+			want: []int16{20, 21, 22, 24},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findFadeChannels(tt.fixture); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findFadeChannels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgrammer(t *testing.T) {
+
+	fixtures := &Fixtures{Fixtures: []Fixture{
+		{Name: "par1", Label: "Par1", Type: "rgb", Group: 1, Number: 1, Address: 1, Channels: []Channel{
+			{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"}, {Name: "Master"},
+		}},
+		{Name: "par2", Label: "Par2", Type: "rgb", Group: 1, Number: 2, Address: 5, Channels: []Channel{
+			{Name: "Red1"}, {Name: "Green1"}, {Name: "Blue1"}, {Name: "Dimmer reverse"},
+		}},
+		{Name: "switch1", Label: "SW1", Type: "switch", Group: 4, Number: 1, UseFixture: "Par2"},
+	}}
+
+	programmer := NewProgrammer()
+	defer programmer.Clear()
+
+	// Select both pars and set them red.
+	programmer.ToggleFixture(0, 0)
+	programmer.ToggleFixture(0, 1)
+	programmer.SetChannel("Red1", 255, fixtures)
+
+	// Only the second par gets dimmed.
+	programmer.ToggleFixture(0, 0)
+	if programmer.IsSelected(0, 0) {
+		t.Errorf("IsSelected() fixture 0 still selected")
+	}
+	programmer.SetChannel("Dimmer reverse", 0, fixtures)
+
+	wantNames := []string{"Red1", "Green1", "Blue1", "Dimmer reverse"}
+	if got := programmer.ChannelNames(fixtures); !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("ChannelNames() = %v, want %v", got, wantNames)
+	}
+
+	frame := dmx.GetFrame(time.Now())
+	for address, want := range map[int16]byte{1: 255, 5: 255, 8: 0} {
+		if frame[address-1] != want {
+			t.Errorf("GetFrame() channel %d = %d, want %d", address, frame[address-1], want)
+		}
+	}
+
+	// Record the second par as a switch state, the reversed dimmer at 0 is full brightness.
+	stateNumber, err := programmer.RecordSwitchState("SW1", fixtures)
+	if err != nil {
+		t.Fatalf("RecordSwitchState() error %v", err)
+	}
+	wantSettings := []Setting{
+		{Name: "Red1", Label: "Red1", Number: 1, Channel: "Red1", Value: "255"},
+		{Name: "Dimmer reverse", Label: "Dimmer reverse", Number: 2, Channel: "Dimmer reverse", Value: "100"},
+	}
+	if stateNumber != 1 || !reflect.DeepEqual(fixtures.Fixtures[2].States[0].Settings, wantSettings) {
+		t.Errorf("RecordSwitchState() = %d %+v, want 1 %+v", stateNumber, fixtures.Fixtures[2].States[0].Settings, wantSettings)
+	}
+
+	if _, err := programmer.RecordSwitchState("SW2", fixtures); err == nil {
+		t.Errorf("RecordSwitchState() expected an error for a missing switch")
+	}
+
+	// Clearing hands the channels back.
+	programmer.Clear()
+	if programmer.Active() {
+		t.Errorf("Active() after Clear() = true")
+	}
+	frame = dmx.GetFrame(time.Now())
+	if frame[0] != 0 {
+		t.Errorf("GetFrame() after Clear() channel 1 = %d, want 0", frame[0])
+	}
+}
+
+func TestSwitchAdvance(t *testing.T) {
+	states := []State{{Number: 10}, {Number: 20}, {Number: 30}}
+
+	tests := []struct {
+		name    string
+		advance *Advance
+		want    *common.SwitchAdvance
+		wantErr bool
+	}{
+		{
+			name: "no advance",
+		},
+		{
+			name:    "timer",
+			advance: &Advance{Mode: "timer", Hold: "30s"},
+			want: &common.SwitchAdvance{Mode: "timer", Steps: []common.AdvanceStep{
+				{Position: 0, Hold: 30 * time.Second}, {Position: 1, Hold: 30 * time.Second}, {Position: 2, Hold: 30 * time.Second},
+			}},
+		},
+		{
+			name:    "beat with default beats",
+			advance: &Advance{Mode: "beat"},
+			want: &common.SwitchAdvance{Mode: "beat", Beats: DEFAULT_ADVANCE_BEATS, Steps: []common.AdvanceStep{
+				{Position: 0}, {Position: 1}, {Position: 2},
+			}},
+		},
+		{
+			name:    "order by state number",
+			advance: &Advance{Mode: "order", Order: []AdvanceStep{{State: 30, Hold: "1m"}, {State: 10}}},
+			want: &common.SwitchAdvance{Mode: "order", Steps: []common.AdvanceStep{
+				{Position: 2, Hold: time.Minute}, {Position: 0, Hold: DEFAULT_ADVANCE_HOLD},
+			}},
+		},
+		{
+			name:    "order with a missing state",
+			advance: &Advance{Mode: "order", Order: []AdvanceStep{{State: 10}, {State: 40}}},
+			wantErr: true,
+		},
+		{
+			name:    "order of one state",
+			advance: &Advance{Mode: "order", Order: []AdvanceStep{{State: 10}}},
+			wantErr: true,
+		},
+		{
+			name:    "bad hold",
+			advance: &Advance{Mode: "timer", Hold: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "hold too short",
+			advance: &Advance{Mode: "timer", Hold: "1ms"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown mode",
+			advance: &Advance{Mode: "random"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.advance.SwitchAdvance(states)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SwitchAdvance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SwitchAdvance() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultSwitchPosition(t *testing.T) {
+	on := int16(20)
+	missing := int16(40)
+	states := []State{{Number: 10}, {Number: 20}}
+
+	tests := []struct {
+		name    string
+		fixture Fixture
+		want    int
+		wantErr bool
+	}{
+		{name: "first state without a default", fixture: Fixture{States: states}, want: 0},
+		{name: "default state", fixture: Fixture{States: states, Default: &on}, want: 1},
+		{name: "missing default state", fixture: Fixture{States: states, Default: &missing}, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fixture.DefaultSwitchPosition()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DefaultSwitchPosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DefaultSwitchPosition() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectColor(t *testing.T) {
+	red := common.Color{R: 255}
+	blue := common.Color{B: 255}
+
+	tests := []struct {
+		name           string
+		mode           string
+		phase          float64
+		colors         []common.Color
+		fade           int
+		size           int
+		wantColor      common.Color
+		wantBrightness int
+	}{
+		{name: "rainbow starts red", mode: ACTION_RAINBOW, phase: 0, wantColor: common.Color{R: 255}, wantBrightness: 255},
+		{name: "rainbow a third round is green", mode: ACTION_RAINBOW, phase: 1.0 / 3, wantColor: common.Color{G: 255}, wantBrightness: 255},
+		{name: "fade loop starts on first color", mode: ACTION_FADE_LOOP, phase: 0, colors: []common.Color{red, blue}, fade: FADE_SOFT, wantColor: red, wantBrightness: 255},
+		{name: "soft fade loop half way to next color", mode: ACTION_FADE_LOOP, phase: 0.25, colors: []common.Color{red, blue}, fade: FADE_SOFT, wantColor: common.Color{R: 128, B: 128}, wantBrightness: 255},
+		{name: "sharp fade loop holds first color", mode: ACTION_FADE_LOOP, phase: 0.25, colors: []common.Color{red, blue}, fade: FADE_SHARP, wantColor: red, wantBrightness: 255},
+		{name: "pulse peaks half way through", mode: ACTION_PULSE, phase: 0.25, colors: []common.Color{blue}, fade: FADE_NORMAL, size: SIZE_MEDIUM, wantColor: blue, wantBrightness: 255},
+		{name: "pulse off after its size", mode: ACTION_PULSE, phase: 0.75, colors: []common.Color{blue}, fade: FADE_NORMAL, size: SIZE_MEDIUM, wantColor: blue, wantBrightness: 0},
+		{name: "pulse defaults to white", mode: ACTION_PULSE, phase: 0.25, fade: FADE_NORMAL, size: SIZE_MEDIUM, wantColor: common.White, wantBrightness: 255},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, brightness := effectColor(tt.mode, tt.phase, tt.colors, tt.fade, tt.size)
+			if color != tt.wantColor {
+				t.Errorf("effectColor() color = %+v, want %+v", color, tt.wantColor)
+			}
+			if brightness != tt.wantBrightness {
+				t.Errorf("effectColor() brightness = %d, want %d", brightness, tt.wantBrightness)
+			}
+		})
+	}
+}
+
+func TestEffectStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		phase        float64
+		music        bool
+		beat         bool
+		numberColors int
+		want         float64
+	}{
+		{name: "timed effect moves on a frame", mode: ACTION_RAINBOW, phase: 0, want: 0.25},
+		{name: "timed effect goes round again", mode: ACTION_RAINBOW, phase: 0.875, want: 0.125},
+		{name: "music effect waits for a beat", mode: ACTION_RAINBOW, phase: 0.5, music: true, want: 0.5},
+		{name: "music rainbow moves on a beat", mode: ACTION_RAINBOW, phase: 0.5, music: true, beat: true, want: 0.5 + EFFECT_BEAT_STEP},
+		{name: "beat starts a pulse", mode: ACTION_PULSE, phase: 1, music: true, beat: true, want: 0},
+		{name: "pulse runs its course", mode: ACTION_PULSE, phase: 0.9, music: true, want: 1},
+		{name: "beat moves fade loop to next color", mode: ACTION_FADE_LOOP, phase: 0.3, music: true, beat: true, numberColors: 4, want: 0.5},
+		{name: "beat moves fade loop back to first color", mode: ACTION_FADE_LOOP, phase: 0.8, music: true, beat: true, numberColors: 4, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := effectStep(tt.mode, tt.phase, 250*time.Millisecond, time.Second, tt.music, tt.beat, tt.numberColors)
+			if got != tt.want {
+				t.Errorf("effectStep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlicker(t *testing.T) {
+	if got := flickerLevel(SIZE_OFF, 1); got != 255 {
+		t.Errorf("flickerLevel() at the top = %d, want 255", got)
+	}
+	if shallow, deep := flickerLevel(SIZE_SHORT, 0), flickerLevel(SIZE_LONG, 0); deep >= shallow {
+		t.Errorf("flickerLevel() long size %d should flicker deeper than short %d", deep, shallow)
+	}
+	if got := smoothLevel(0, 200, FADE_SHARP); got != 200 {
+		t.Errorf("smoothLevel() sharp = %d, want 200", got)
+	}
+	if got := smoothLevel(0, 200, FADE_SOFT); got != 20 {
+		t.Errorf("smoothLevel() soft = %d, want 20", got)
+	}
+}
+
+func TestParseSettingTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "500ms", want: 500 * time.Millisecond},
+		{value: "2", want: 2 * time.Second},
+		{value: " 1.5s ", want: 1500 * time.Millisecond},
+		{value: "-1s", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSettingTime(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSettingTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSettingTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingFadeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    byte
+		to      byte
+		elapsed time.Duration
+		want    byte
+	}{
+		{name: "start of fade up", from: 0, to: 200, elapsed: 0, want: 0},
+		{name: "half way up", from: 0, to: 200, elapsed: 500 * time.Millisecond, want: 100},
+		{name: "half way down", from: 200, to: 0, elapsed: 500 * time.Millisecond, want: 100},
+		{name: "finished", from: 0, to: 200, elapsed: 2 * time.Second, want: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settingFadeValue(tt.from, tt.to, tt.elapsed, time.Second); got != tt.want {
+				t.Errorf("settingFadeValue() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingFader(t *testing.T) {
+	source := "test fader"
+	value := func(fader *settingFader, address int16) byte {
+		fader.lock.Lock()
+		defer fader.lock.Unlock()
+		return fader.values[address]
+	}
+
+	// A setting without times is set straight away.
+	fader := startSettings(source)
+	fader.play(source, 1, 255, 0, common.Setting{FadeOut: 200 * time.Millisecond}, nil, false)
+	fader.finish(source, nil, false)
+	if got := value(fader, 1); got != 255 {
+		t.Fatalf("setting without times = %d, want 255", got)
+	}
+
+	// Moving to a state which doesn't set the channel fades it out.
+	fader = startSettings(source)
+	fader.play(source, 2, 100, 0, common.Setting{Delay: time.Hour}, nil, false)
+	fader.finish(source, nil, false)
+	time.Sleep(100 * time.Millisecond)
+	if got := value(fader, 1); got == 0 || got == 255 {
+		t.Errorf("fading out channel = %d, want part way down", got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := value(fader, 1); got != 0 {
+		t.Errorf("faded out channel = %d, want 0", got)
+	}
+	if got := value(fader, 2); got != 0 {
+		t.Errorf("delayed channel = %d, want 0 until its delay is up", got)
+	}
+
+	// Moving on again cancels the delayed setting.
+	fader = startSettings(source)
+	fader.finish(source, nil, false)
+	time.Sleep(50 * time.Millisecond)
+	if got := value(fader, 2); got != 0 {
+		t.Errorf("cancelled channel = %d, want 0", got)
+	}
+
+	// Moving to a state which sets the channel without a fade in uses the last state's fade out.
+	fader = startSettings(source)
+	fader.play(source, 3, 255, 0, common.Setting{FadeOut: 200 * time.Millisecond}, nil, false)
+	fader.finish(source, nil, false)
+	fader = startSettings(source)
+	fader.play(source, 3, 0, 0, common.Setting{}, nil, false)
+	fader.finish(source, nil, false)
+	time.Sleep(100 * time.Millisecond)
+	if got := value(fader, 3); got == 0 || got == 255 {
+		t.Errorf("channel set by the next state = %d, want part way down", got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := value(fader, 3); got != 0 {
+		t.Errorf("channel set by the next state = %d, want 0", got)
+	}
+
+	// A reversed dimmer fading in for the first time starts from off at 255, not full on at 0.
+	fader = startSettings(source)
+	fader.play(source, 4, 0, 255, common.Setting{FadeIn: 200 * time.Millisecond}, nil, false)
+	fader.finish(source, nil, false)
+	time.Sleep(100 * time.Millisecond)
+	if got := value(fader, 4); got == 0 || got == 255 {
+		t.Errorf("reversed dimmer fading in = %d, want part way up", got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := value(fader, 4); got != 0 {
+		t.Errorf("reversed dimmer faded in = %d, want 0", got)
+	}
+}
+
+func TestSettingOffValue(t *testing.T) {
+	rest := int16(64)
+	thisFixture := &Fixture{Channels: []Channel{{Name: "Master"}, {Name: "Rotate", Value: &rest}}}
+	tests := []struct {
+		name    string
+		channel int
+		want    byte
+	}{
+		{name: "channel without a value", channel: 0, want: 0},
+		{name: "channel with a value", channel: 1, want: 64},
+		{name: "channel not in the fixture", channel: 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settingOffValue(thisFixture, tt.channel); got != tt.want {
+				t.Errorf("settingOffValue() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmasterChannelLevels(t *testing.T) {
+	fixtures := &Fixtures{
+		Fixtures: []Fixture{
+			{Group: 1, Number: 1, Address: 1, Channels: []Channel{{Name: "Red"}, {Name: "Green"}, {Name: "Blue"}}},
+			{Group: 1, Number: 2, Address: 4, Channels: []Channel{{Name: "Master"}, {Name: "Red"}}},
+			{Group: 2, Number: 1, Address: 10, Channels: []Channel{{Name: "Dimmer"}}},
+			{Group: 4, Number: 1, Address: 10, Channels: []Channel{{Name: "Dimmer"}}, Type: "switch"},
+		},
+	}
+	tests := []struct {
+		name          string
+		fixtureLevels map[ProgrammerFixture]int
+		groupLevels   map[int]int
+		want          map[int16]int
+	}{
+		{
+			name: "all at full",
+			want: map[int16]int{},
+		},
+		{
+			name:          "a fixture without a master is dimmed by its color channels",
+			fixtureLevels: map[ProgrammerFixture]int{{Sequence: 0, Fixture: 0}: 100},
+			want:          map[int16]int{1: 100, 2: 100, 3: 100},
+		},
+		{
+			name:          "fixture and group levels multiply",
+			fixtureLevels: map[ProgrammerFixture]int{{Sequence: 0, Fixture: 1}: 128},
+			groupLevels:   map[int]int{1: 128},
+			want:          map[int16]int{1: 128, 2: 128, 3: 128, 4: 64},
+		},
+		{
+			name:        "a shared channel takes the lower level",
+			groupLevels: map[int]int{2: 200, 4: 50},
+			want:        map[int16]int{10: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := submasterChannelLevels(fixtures, tt.fixtureLevels, tt.groupLevels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("submasterChannelLevels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSubmasters(t *testing.T) {
+	defer LoadSubmasters(nil)
+
+	SetFixtureSubmaster(0, 3, 10)
+	SetGroupSubmaster(1, 300)
+	if got := GroupSubmaster(1); got != SUBMASTER_FULL {
+		t.Errorf("GroupSubmaster() = %d, want %d", got, SUBMASTER_FULL)
+	}
+
+	values := []common.SubmasterValue{
+		{Group: 100, Level: 50},
+		{Sequence: 1, Fixture: 2, Level: 200},
+	}
+	LoadSubmasters(values)
+	if got := FixtureSubmaster(0, 3); got != SUBMASTER_FULL {
+		t.Errorf("FixtureSubmaster() not in the preset = %d, want %d", got, SUBMASTER_FULL)
+	}
+	if got := Submasters(); !reflect.DeepEqual(got, values) {
+		t.Errorf("Submasters() = %v, want %v", got, values)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights switch interlocks, switches that are never on
+// together, states that need another switch set and power on states.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+)
+
+// Requirement is a state another switch must be in before a switch can move to the state that has it.
+type Requirement struct {
+	Switch string `yaml:"switch"` // The other switch's name.
+	State  int16  `yaml:"state"`  // The other switch's state number.
+}
+
+// DefaultSwitchPosition returns the position a switch powers on in, its first state unless it has a default.
+func (fixture Fixture) DefaultSwitchPosition() (int, error) {
+
+	if fixture.Default == nil {
+		return common.SWITCH_OFF_POSITION, nil
+	}
+
+	position := findStatePosition(fixture.States, *fixture.Default)
+	if position < 0 {
+		return common.SWITCH_OFF_POSITION, fmt.Errorf("default state %d not found", *fixture.Default)
+	}
+	return position, nil
+}
+
+// SwitchRequirements copies the switches a state needs for the switch sequence.
+func (state State) SwitchRequirements() []common.Requirement {
+	var requirements []common.Requirement
+	for _, requirement := range state.Requires {
+		requirements = append(requirements, common.Requirement{Switch: requirement.Switch, State: requirement.State})
+	}
+	return requirements
+}
@@ -0,0 +1,266 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights scanner limits, it handles the orientation of a
+// scanner, its pan and tilt range and any keep out zones.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Keep out zone actions.
+const KEEPOUT_CLAMP = "clamp"
+const KEEPOUT_BLANK = "blank"
+
+// Limits describes how a scanner is hung and where it is allowed to point.
+type Limits struct {
+	InvertPan  bool      `yaml:"invertpan,omitempty"`
+	InvertTilt bool      `yaml:"inverttilt,omitempty"`
+	Swap       bool      `yaml:"swap,omitempty"` // Swap pan and tilt for fixtures rotated on their side.
+	PanMin     *int      `yaml:"panmin,omitempty"`
+	PanMax     *int      `yaml:"panmax,omitempty"`
+	TiltMin    *int      `yaml:"tiltmin,omitempty"`
+	TiltMax    *int      `yaml:"tiltmax,omitempty"`
+	KeepOut    []KeepOut `yaml:"keepout,omitempty"`
+}
+
+// KeepOut is a rectangle of pan and tilt values the beam must never enter.
+// Action is either clamp which moves the beam to the nearest edge of the zone or
+// blank which closes the shutter while the beam is inside the zone.
+type KeepOut struct {
+	Name    string `yaml:"name,omitempty"`
+	PanMin  int    `yaml:"panmin"`
+	PanMax  int    `yaml:"panmax"`
+	TiltMin int    `yaml:"tiltmin"`
+	TiltMax int    `yaml:"tiltmax"`
+	Action  string `yaml:"action"`
+}
+
+// blankedFixtures remembers which fixtures are blanked by a keep out zone, indexed by fixture address.
+// The shutter chaser doesn't know the scanners position so it has to look here.
+var blankedFixtures = make(map[int16]bool)
+var blankedFixturesLock sync.RWMutex
+
+func setFixtureBlanked(address int16, blank bool) {
+	blankedFixturesLock.Lock()
+	defer blankedFixturesLock.Unlock()
+	blankedFixtures[address] = blank
+}
+
+func isFixtureBlanked(address int16) bool {
+	blankedFixturesLock.RLock()
+	defer blankedFixturesLock.RUnlock()
+	return blankedFixtures[address]
+}
+
+// fixturePosition is the pan and tilt last sent to a fixture, after its limits were applied.
+type fixturePosition struct {
+	pan  int
+	tilt int
+}
+
+// fixturePositions remembers where each fixture is pointing, indexed by fixture address.
+// A setting only moves one axis so the keep out zones need to know where the other axis is.
+var fixturePositions = make(map[int16]fixturePosition)
+var fixturePositionsLock sync.RWMutex
+
+func setFixturePosition(address int16, pan int, tilt int) {
+	fixturePositionsLock.Lock()
+	defer fixturePositionsLock.Unlock()
+	fixturePositions[address] = fixturePosition{pan: pan, tilt: tilt}
+}
+
+func getFixturePosition(address int16) fixturePosition {
+	fixturePositionsLock.RLock()
+	defer fixturePositionsLock.RUnlock()
+	return fixturePositions[address]
+}
+
+// ApplyLimits takes the requested pan and tilt and returns the pan and tilt to send to the fixture.
+// Returns true if the beam is inside a blanking keep out zone.
+func ApplyLimits(pan int, tilt int, limits *Limits) (int, int, bool) {
+
+	if limits == nil {
+		return pan, tilt, false
+	}
+
+	if limits.Swap {
+		pan, tilt = tilt, pan
+	}
+
+	pan = limitAxis(pan, limits.InvertPan, limits.PanMin, limits.PanMax)
+	tilt = limitAxis(tilt, limits.InvertTilt, limits.TiltMin, limits.TiltMax)
+
+	var blank bool
+	for _, zone := range limits.KeepOut {
+		if !insideKeepOut(pan, tilt, zone) {
+			continue
+		}
+		if zone.Action == KEEPOUT_BLANK {
+			if debug {
+				fmt.Printf("ApplyLimits: pan %d tilt %d blanked by keep out zone %s\n", pan, tilt, zone.Name)
+			}
+			blank = true
+			continue
+		}
+		pan, tilt = moveOutOfKeepOut(pan, tilt, zone)
+		// The zone covers everything so there is nowhere to move to, blank instead.
+		if insideKeepOut(pan, tilt, zone) {
+			blank = true
+		}
+		if debug {
+			fmt.Printf("ApplyLimits: clamped to pan %d tilt %d by keep out zone %s\n", pan, tilt, zone.Name)
+		}
+	}
+
+	return pan, tilt, blank
+}
+
+// limitAxis inverts and limits the range of a single pan or tilt value.
+func limitAxis(value int, invert bool, min *int, max *int) int {
+	value = limitValue(value)
+	if invert {
+		value = 255 - value
+	}
+	if min != nil && value < *min {
+		value = *min
+	}
+	if max != nil && value > *max {
+		value = *max
+	}
+	return value
+}
+
+// limitChannel applies the pan or tilt invert and range limits to a value being sent
+// directly to a channel, used by settings which set a single channel.
+func limitChannel(channelName string, value int, limits *Limits) int {
+	if limits == nil {
+		return value
+	}
+	if strings.Contains(channelName, "Pan") {
+		return limitAxis(value, limits.InvertPan, limits.PanMin, limits.PanMax)
+	}
+	if strings.Contains(channelName, "Tilt") {
+		return limitAxis(value, limits.InvertTilt, limits.TiltMin, limits.TiltMax)
+	}
+	return value
+}
+
+// limitSettingValue applies the fixtures pan and tilt limits and keep out zones to a setting being sent to a channel number.
+func limitSettingValue(fixture *Fixture, channel int, value int) int {
+	if fixture.Limits == nil || channel < 0 || channel >= len(fixture.Channels) {
+		return value
+	}
+
+	channelName := fixture.Channels[channel].Name
+	value = limitChannel(channelName, value, fixture.Limits)
+
+	isPan := strings.Contains(channelName, "Pan")
+	isTilt := strings.Contains(channelName, "Tilt")
+	if !isPan && !isTilt {
+		return value
+	}
+
+	// Keep the beam out of the keep out zones, using the last known position of the other axis.
+	position := getFixturePosition(fixture.Address)
+	if isPan {
+		position.pan = keepAxisOut(value, position.pan, position.tilt, fixture.Limits.KeepOut, true)
+		value = position.pan
+	} else {
+		position.tilt = keepAxisOut(value, position.tilt, position.pan, fixture.Limits.KeepOut, false)
+		value = position.tilt
+	}
+	setFixturePosition(fixture.Address, position.pan, position.tilt)
+
+	return value
+}
+
+// keepAxisOut moves a single pan or tilt value to the nearest edge of any keep out zone it's inside.
+// A setting can't move the other axis or close the shutter, so blanking zones are kept out of too.
+// If there's no way out along this axis the axis stays where it was.
+func keepAxisOut(value int, last int, other int, zones []KeepOut, isPan bool) int {
+
+	for _, zone := range zones {
+
+		pan, tilt := value, other
+		min, max := zone.PanMin, zone.PanMax
+		if !isPan {
+			pan, tilt = other, value
+			min, max = zone.TiltMin, zone.TiltMax
+		}
+		if !insideKeepOut(pan, tilt, zone) {
+			continue
+		}
+
+		switch {
+		case min > 0 && (max >= 255 || value-min < max-value):
+			value = min - 1
+		case max < 255:
+			value = max + 1
+		default:
+			value = last
+		}
+
+		if debug {
+			fmt.Printf("keepAxisOut: setting moved to %d by keep out zone %s\n", value, zone.Name)
+		}
+	}
+
+	return value
+}
+
+func insideKeepOut(pan int, tilt int, zone KeepOut) bool {
+	return pan >= zone.PanMin && pan <= zone.PanMax && tilt >= zone.TiltMin && tilt <= zone.TiltMax
+}
+
+// moveOutOfKeepOut moves the position to the nearest edge outside the zone.
+func moveOutOfKeepOut(pan int, tilt int, zone KeepOut) (int, int) {
+
+	type move struct {
+		distance int
+		pan      int
+		tilt     int
+	}
+
+	moves := []move{}
+	if zone.PanMin > 0 {
+		moves = append(moves, move{distance: pan - zone.PanMin + 1, pan: zone.PanMin - 1, tilt: tilt})
+	}
+	if zone.PanMax < 255 {
+		moves = append(moves, move{distance: zone.PanMax - pan + 1, pan: zone.PanMax + 1, tilt: tilt})
+	}
+	if zone.TiltMin > 0 {
+		moves = append(moves, move{distance: tilt - zone.TiltMin + 1, pan: pan, tilt: zone.TiltMin - 1})
+	}
+	if zone.TiltMax < 255 {
+		moves = append(moves, move{distance: zone.TiltMax - tilt + 1, pan: pan, tilt: zone.TiltMax + 1})
+	}
+
+	// The zone covers everything, nowhere to go.
+	if len(moves) == 0 {
+		return pan, tilt
+	}
+
+	nearest := moves[0]
+	for _, m := range moves {
+		if m.distance < nearest.distance {
+			nearest = m
+		}
+	}
+	return nearest.pan, nearest.tilt
+}
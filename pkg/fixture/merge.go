@@ -0,0 +1,96 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This tells the dmxlights output stage how to merge the channels written
+// by sequences and switches which share the same fixtures.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// RegisterMergeChannels tells the output stage which channels are intensity channels, which
+// channels the output level and submasters dim, which channels a crossfade blends and sets the merge priority of each switch.
+// Called whenever the fixtures are loaded.
+func RegisterMergeChannels(fixtures *Fixtures) {
+
+	dmx.ClearIntensityChannels()
+	dmx.ClearDimmableChannels()
+	dmx.ClearFadeChannels()
+
+	for _, fixture := range fixtures.Fixtures {
+		for address, offValue := range findIntensityChannels(&fixture) {
+			dmx.SetIntensityChannel(address, offValue)
+		}
+		// Fixtures without a master or dimmer are dimmed using their color channels.
+		for address, offValue := range findStrobeChannels(&fixture) {
+			dmx.SetDimmableChannel(address, offValue)
+		}
+		for _, address := range findFadeChannels(&fixture) {
+			dmx.SetFadeChannel(address)
+		}
+		if fixture.Type == "switch" {
+			if debug {
+				fmt.Printf("switch %d merge priority %d\n", fixture.Number, fixture.Priority)
+			}
+			dmx.SetSourcePriority(dmx.SwitchSource(fixture.Number), fixture.Priority)
+		}
+	}
+
+	// The submasters dim the fixtures by their dimmable channels, which may have moved.
+	registerSubmasters(fixtures)
+
+	// The fixtures extra emitters are looked up every time a color is mixed.
+	registerEmitters(fixtures)
+}
+
+// findIntensityChannels returns a fixture's master and dimmer channels and the value which turns each one off.
+// Reversed dimmers are off at 255.
+func findIntensityChannels(fixture *Fixture) map[int16]byte {
+
+	channels := make(map[int16]byte)
+
+	for channelNumber, channel := range fixture.Channels {
+		if strings.Contains(channel.Name, "Master") || strings.Contains(channel.Name, "Dimmer") {
+			if strings.Contains(strings.ToLower(channel.Name), "reverse") || strings.Contains(strings.ToLower(channel.Name), "invert") {
+				channels[fixture.Address+int16(channelNumber)] = 255
+			} else {
+				channels[fixture.Address+int16(channelNumber)] = 0
+			}
+		}
+	}
+	return channels
+}
+
+// findFadeChannels returns the channels which are blended during a crossfade between presets,
+// the intensity, color, pan and tilt channels.
+func findFadeChannels(fixture *Fixture) []int16 {
+
+	intensityChannels := findIntensityChannels(fixture)
+
+	channels := []int16{}
+	for channelNumber, channel := range fixture.Channels {
+		address := fixture.Address + int16(channelNumber)
+		_, isIntensity := intensityChannels[address]
+		if isIntensity || isColorChannel(channel.Name) ||
+			strings.HasPrefix(channel.Name, "Pan") || strings.HasPrefix(channel.Name, "Tilt") {
+			channels = append(channels, address)
+		}
+	}
+	return channels
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
 	"github.com/dhowlett99/dmxlights/pkg/pattern"
 	"github.com/dhowlett99/dmxlights/pkg/position"
 	"github.com/dhowlett99/dmxlights/pkg/sound"
@@ -56,6 +57,7 @@ const STROBE_SPEED_SLOW int = 0
 // newMiniSequencer is a simple sequencer which can be attached to a switch and a single fixture to allow simple effects.
 // The miniSequenceer implements the actions attaced to a switch state.
 // Currently we support 1. Off 2. Control, ability to set programs 3. Static colors 4. Chase. soft, hard and timed or music triggered.
+// 5. Rainbow 6. Fade Loop through the colors 7. Flicker 8. Pulse, also timed or music triggered.
 // Long term objective of actions is to replace the direct value settings.
 func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 	dmxController *ft232.DMXController, fixturesConfig *Fixtures,
@@ -72,6 +74,9 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 
 	switchName := fmt.Sprintf("switch%d", swiTch.Number)
 
+	// The switch writes into its own output layer.
+	source := dmx.SwitchSource(swiTch.Number)
+
 	mySequenceNumber := fixture.Group - 1
 	myFixtureNumber := fixture.Number - 1
 
@@ -106,28 +111,28 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 		// Stop any running fade ups.
 		select {
 		case switchChannels[swiTch.Number].StopFadeUp <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running fade downs.
 		select {
 		case switchChannels[swiTch.Number].StopFadeDown <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running chases.
 		select {
 		case switchChannels[swiTch.Number].Stop <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any rotates.
 		select {
 		case switchChannels[swiTch.Number].StopRotate <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
@@ -143,16 +148,22 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 					return
 				case <-time.After(10 * time.Millisecond):
 				}
-				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
-				MapFixtures(false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, fade, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+				MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, fade, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 				// Control how long the fade take with the speed control.
 				time.Sleep((5 * time.Millisecond) * (time.Duration(cfg.Fade)))
+				// Hold the fade while frozen, still listening for stop commands.
+				select {
+				case <-switchChannels[swiTch.Number].StopFadeDown:
+					return
+				case <-common.FreezeReleased():
+				}
 			}
 			state := swiTch.States[0]
 			buttonColor, _ := common.GetRGBColorByName(state.ButtonColor)
-			common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, buttonColor, master, eventsForLaunchpad, guiButtons)
+			common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, buttonColor, master, eventsForLaunchpad, guiButtons)
 		} else {
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		}
 
 		return
@@ -180,32 +191,32 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 		// Stop any running fades.
 		select {
 		case switchChannels[swiTch.Number].StopFadeUp <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running fade downs.
 		select {
 		case switchChannels[swiTch.Number].StopFadeDown <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running chases.
 		select {
 		case switchChannels[swiTch.Number].Stop <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any rotates.
 		select {
 		case switchChannels[swiTch.Number].StopRotate <- true:
-			MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
-		//MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+		//MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 
 		// Find the program channel for this fixture.
 		programChannel, err := FindChannelNumberByName(fixture, "Program")
@@ -222,7 +233,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			if debug {
 				fmt.Printf("fixture %s: Control: send master Address %d Value %d \n", fixture.Name, fixture.Address+int16(masterChannel), master)
 			}
-			SetChannel(fixture.Address+int16(masterChannel), byte(master), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(masterChannel), byte(master), dmxController, dmxInterfacePresent)
 		}
 
 		if fixtureHasChannel(fixture, "Shutter") {
@@ -234,7 +245,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			if debug {
 				fmt.Printf("fixture %s: Control: send Shutter Address %d Value %d \n", fixture.Name, fixture.Address+int16(shutterChannel), master)
 			}
-			SetChannel(fixture.Address+int16(shutterChannel), byte(32), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(shutterChannel), byte(32), dmxController, dmxInterfacePresent)
 		}
 
 		if fixtureHasChannel(fixture, "Rotate") {
@@ -246,7 +257,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			if debug {
 				fmt.Printf("fixture %s: Control: send Rotate Address %d Value %d \n", fixture.Name, fixture.Address+int16(rotateChannel), master)
 			}
-			SetChannel(fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
 		}
 
 		if fixtureHasChannel(fixture, "Gobo") {
@@ -258,7 +269,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			if debug {
 				fmt.Printf("fixture %s: Control: send Gobo Address %d Value %d \n", fixture.Name, fixture.Address+int16(goboChannel), master)
 			}
-			SetChannel(fixture.Address+int16(goboChannel), byte(0), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(goboChannel), byte(0), dmxController, dmxInterfacePresent)
 		}
 		if fixtureHasChannel(fixture, "ProgramSpeed") {
 			// Find the program speed channel for this fixture.
@@ -270,7 +281,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 				fmt.Printf("fixture %s: Control: send ProgramSpeed Address %d Value %d \n", fixture.Name, fixture.Address+int16(programSpeedChannel), master)
 			}
 			// Now play that DMX value on the program channel of this fixture.
-			SetChannel(fixture.Address+int16(programSpeedChannel), byte(cfg.ProgramSpeed), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(programSpeedChannel), byte(cfg.ProgramSpeed), dmxController, dmxInterfacePresent)
 		}
 
 		if fixtureHasChannel(fixture, "Program") {
@@ -282,7 +293,12 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			if debug {
 				fmt.Printf("fixture %s: Control: send Program Address %d Value %d \n", fixture.Name, fixture.Address+int16(programState), master)
 			}
-			SetChannel(fixture.Address+int16(programChannel), byte(programState), dmxController, dmxInterfacePresent)
+			SetChannel(source, fixture.Address+int16(programChannel), byte(programState), dmxController, dmxInterfacePresent)
+		}
+
+		// A switch with a raised priority hands the fixture back to its sequence when it goes off.
+		if dmx.SourcePriority(source) > dmx.DEFAULT_PRIORITY {
+			dmx.ReleaseSource(source)
 		}
 
 		return
@@ -310,28 +326,28 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 		// Stop any running fades.
 		select {
 		case switchChannels[swiTch.Number].StopFadeUp <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running fade downs.
 		select {
 		case switchChannels[swiTch.Number].StopFadeDown <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running chases.
 		select {
 		case switchChannels[swiTch.Number].Stop <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any rotates.
 		select {
 		case switchChannels[swiTch.Number].StopRotate <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
@@ -358,10 +374,16 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 							return
 						case <-time.After(10 * time.Millisecond):
 						}
-						common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
-						MapFixtures(false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, fade, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+						common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+						MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, fade, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 						// Control how long the fade take with the fade speed control.
 						time.Sleep((5 * time.Millisecond) * (time.Duration(common.Reverse(cfg.Fade))))
+						// Hold the fade while frozen, still listening for stop commands.
+						select {
+						case <-switchChannels[swiTch.Number].StopFadeDown:
+							return
+						case <-common.FreezeReleased():
+						}
 					}
 					// Fade down complete, set lastColor to empty in the fixture.
 					command := common.FixtureCommand{
@@ -380,10 +402,16 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 						return
 					case <-time.After(10 * time.Millisecond):
 					}
-					common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, color, fade, eventsForLaunchpad, guiButtons)
-					MapFixtures(false, false, mySequenceNumber, myFixtureNumber, color, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, fade, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+					common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, color, fade, eventsForLaunchpad, guiButtons)
+					MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, color, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, fade, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 					// Control how long the fade take with the fade speed control.
 					time.Sleep((5 * time.Millisecond) * (time.Duration(common.Reverse(cfg.Fade))))
+					// Hold the fade while frozen, still listening for stop commands.
+					select {
+					case <-switchChannels[swiTch.Number].StopFadeUp:
+						return
+					case <-common.FreezeReleased():
+					}
 				}
 				// Fade up complete, set lastColor up in the fixture.
 				command := common.FixtureCommand{
@@ -395,30 +423,30 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 				case <-time.After(100 * time.Millisecond):
 				}
 			} else {
-				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, color, master, eventsForLaunchpad, guiButtons)
-				MapFixtures(false, false, mySequenceNumber, myFixtureNumber, color, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, color, master, eventsForLaunchpad, guiButtons)
+				MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, color, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 			}
 		}(lastColor)
 		return
 	}
 
-	if action.Mode == "Chase" {
+	if action.Mode == "Chase" || IsEffectMode(action.Mode) {
 
 		if debug_mini {
-			fmt.Printf("Chase mini sequence for switch number %d\n", swiTch.Number)
+			fmt.Printf("%s mini sequence for switch number %d\n", action.Mode, swiTch.Number)
 		}
 
 		// Stop any running fades.
 		select {
 		case switchChannels[swiTch.Number].StopFadeUp <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
 		// Stop any running fade downs.
 		select {
 		case switchChannels[swiTch.Number].StopFadeDown <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
@@ -442,14 +470,20 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 					return
 				case <-time.After(10 * time.Millisecond):
 				}
-				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
-				MapFixtures(false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, fade, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, lastColor.RGBColor, fade, eventsForLaunchpad, guiButtons)
+				MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, lastColor.RGBColor, 0, 0, 0, cfg.RotateSpeed, cfg.Program, 0, 0, fixturesConfig, blackout, brightness, fade, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 				// Control how long the fade take with the speed control.
 				time.Sleep((5 * time.Millisecond) * (time.Duration(cfg.Fade)))
+				// Hold the fade while frozen, still listening for stop commands.
+				select {
+				case <-switchChannels[swiTch.Number].StopFadeDown:
+					return
+				case <-common.FreezeReleased():
+				}
 			}
 			state := swiTch.States[0]
 			buttonColor, _ := common.GetRGBColorByName(state.ButtonColor)
-			common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, buttonColor, master, eventsForLaunchpad, guiButtons)
+			common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, buttonColor, master, eventsForLaunchpad, guiButtons)
 		}
 
 		// Remember that we have started this mini sequencer.
@@ -469,7 +503,7 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 		// Turn off the fixture.
 		select {
 		case switchChannels[swiTch.Number].Stop <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
@@ -489,10 +523,28 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 		// Stop any left over sequence left over for this switch.
 		select {
 		case switchChannels[swiTch.Number].Stop <- true:
-			lastColor = MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
+			lastColor = MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, false, 0, 0, 0, false, 0, dmxController, dmxInterfacePresent)
 		case <-time.After(100 * time.Millisecond):
 		}
 
+		// Effects work out their own colors rather than following a chase pattern.
+		if IsEffectMode(action.Mode) {
+
+			// Find the channel the sound service sends this switches beats on.
+			musicTrigger, err := soundConfig.GetSoundTriggerChannel(switchName)
+			if err != nil {
+				fmt.Printf("Error while trying to find sound trigger %s\n", err.Error())
+			}
+
+			go playEffect(action.Mode, cfg, musicTrigger, switchChannels[swiTch.Number].Stop, func(color common.Color, level int) {
+				// The effect's brightness is applied to the master.
+				actualMaster := (level * master) / common.MAX_DMX_BRIGHTNESS
+				common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, color, level, eventsForLaunchpad, guiButtons)
+				MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, color, 0, 0, 0, cfg.RotateSpeed, 0, cfg.Gobo, 0, fixturesConfig, blackout, brightness, actualMaster, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+			})
+			return
+		}
+
 		sequence := common.Sequence{
 			ScannerReverse:       false,
 			RGBInvert:            false,
@@ -552,6 +604,12 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 			}
 		}
 
+		// Find the channel the sound service sends this switches beats on.
+		musicTrigger, err := soundConfig.GetSoundTriggerChannel(switchName)
+		if err != nil {
+			fmt.Printf("Error while trying to find sound trigger %s\n", err.Error())
+		}
+
 		go func() {
 
 			if cfg.Rotatable {
@@ -580,15 +638,19 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 						select {
 						case <-switchChannels[swiTch.Number].StopRotate:
 							time.Sleep(1 * time.Millisecond)
-							SetChannel(fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
 							return
 						case <-switchChannels[swiTch.Number].KeepRotateAlive:
 							time.Sleep(1 * time.Millisecond)
 							continue
 						case <-time.After(1500 * time.Millisecond):
-							SetChannel(fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
+							// The chase stops sending keep alives while frozen.
+							if common.IsFrozen() {
+								continue
+							}
+							SetChannel(source, fixture.Address+int16(rotateChannel), byte(0), dmxController, dmxInterfacePresent)
 							time.Sleep(250 * time.Millisecond)
-							SetChannel(fixture.Address+int16(masterChannel), byte(0), dmxController, dmxInterfacePresent)
+							SetChannel(source, fixture.Address+int16(masterChannel), byte(0), dmxController, dmxInterfacePresent)
 						}
 					}
 				}(swiTch.Number)
@@ -654,20 +716,31 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 
 					// This is were we wait for a beat or a time out equivalent to the speed.
 					select {
-					// First five triggers are occupied by sequence 0-FOH,1-Upluighters,2-Scanners,3-Switches,4-ShutterChaser
-					// So switch channels use 5 -12
-					case <-soundConfig.SoundTriggers[swiTch.Number+4].Channel:
+					case <-musicTrigger:
 					case <-switchChannels[swiTch.Number].Stop:
 						// Stop.
 						if cfg.Rotatable {
 							switchChannels[swiTch.Number].StopRotate <- true
 						}
 						// And turn the fixture off.
-						MapFixtures(false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, blackout, brightness, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+						MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, blackout, brightness, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 						return
 					case <-time.After(cfg.Speed):
 					}
 
+					// Hold this step while frozen.
+					if common.IsFrozen() {
+						select {
+						case <-common.FreezeReleased():
+						case <-switchChannels[swiTch.Number].Stop:
+							if cfg.Rotatable {
+								switchChannels[swiTch.Number].StopRotate <- true
+							}
+							MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, common.Black, 0, 0, 0, 0, 0, 0, 0, fixturesConfig, blackout, brightness, master, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+							return
+						}
+					}
+
 					// Play out fixture to DMX channels.
 					position := RGBPositions[step]
 
@@ -676,14 +749,14 @@ func newMiniSequencer(fixture *Fixture, swiTch common.Switch, action Action,
 					var actualMaster int
 					for fixtureNumber := 0; fixtureNumber < sequence.NumberFixtures; fixtureNumber++ {
 						fixture := fixtures[fixtureNumber]
-						common.LightLamp(common.Button{X: swiTch.Number - 1, Y: 3}, fixture.Color, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
+						common.LightLamp(common.Button{X: swiTch.Number - 1, Y: common.SWITCH_ROW}, fixture.Color, common.MAX_DMX_BRIGHTNESS, eventsForLaunchpad, guiButtons)
 						if cfg.Map {
 							// Use sound triggered brighness and apply master
 							actualMaster = int((float64(fixture.Brightness) / 100) * (float64(master) / 2.55))
 						} else {
 							actualMaster = master
 						}
-						MapFixtures(false, false, mySequenceNumber, myFixtureNumber, fixture.Color, 0, 0, 0, cfg.RotateSpeed, 0, cfg.Gobo, 0, fixturesConfig, blackout, brightness, actualMaster, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
+						MapFixtures(source, false, false, mySequenceNumber, myFixtureNumber, fixture.Color, 0, 0, 0, cfg.RotateSpeed, 0, cfg.Gobo, 0, fixturesConfig, blackout, brightness, actualMaster, cfg.Music, cfg.Strobe, cfg.StrobeSpeed, dmxController, dmxInterfacePresent)
 					}
 
 					rotateCounter++
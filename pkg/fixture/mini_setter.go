@@ -22,16 +22,205 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/oliread/usbdmx/ft232"
 )
 
+// How often a fading setting updates its channel.
+const SETTING_FADE_FRAME = 25 * time.Millisecond
+
+// settingFader remembers what a switch's settings have sent so they can fade from where they are,
+// and lets a switch moving on cancel the fades its last state started.
+type settingFader struct {
+	lock     sync.Mutex
+	cancel   chan bool
+	values   map[int16]byte
+	fadeOuts map[int16]settingFadeOut // Channels set by the current state.
+	previous map[int16]settingFadeOut // Channels set by the state before.
+}
+
+// settingFadeOut is how a channel fades out when its state ends.
+type settingFadeOut struct {
+	time time.Duration
+	off  byte
+}
+
+// One fader for each switch output layer.
+var settingFaders = map[string]*settingFader{}
+var settingFadersLock sync.Mutex
+
+// startSettings is called when a switch moves to a new state. It cancels any delays and fades
+// still running from the last state and returns the fader to play the new state's settings with.
+func startSettings(source string) *settingFader {
+	settingFadersLock.Lock()
+	fader, ok := settingFaders[source]
+	if !ok {
+		fader = &settingFader{
+			cancel:   make(chan bool),
+			values:   map[int16]byte{},
+			fadeOuts: map[int16]settingFadeOut{},
+		}
+		settingFaders[source] = fader
+	}
+	settingFadersLock.Unlock()
+
+	fader.lock.Lock()
+	defer fader.lock.Unlock()
+	close(fader.cancel)
+	fader.cancel = make(chan bool)
+	fader.previous = fader.fadeOuts
+	fader.fadeOuts = map[int16]settingFadeOut{}
+	return fader
+}
+
+// stopSettings cancels any delays and fades still running for a switch.
+func stopSettings(source string) {
+	settingFadersLock.Lock()
+	fader, ok := settingFaders[source]
+	settingFadersLock.Unlock()
+	if !ok {
+		return
+	}
+	fader.lock.Lock()
+	close(fader.cancel)
+	fader.cancel = make(chan bool)
+	fader.lock.Unlock()
+}
+
+// play sends a setting's value to a channel, after its delay and fading in from where the channel is now.
+// If the setting doesn't fade in and the last state set the channel, it fades with the last state's fade out.
+// off is the value the channel fades out to when the state ends.
+func (fader *settingFader) play(source string, address int16, value int, off byte, setting common.Setting, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
+	fader.lock.Lock()
+	last, lastSet := fader.previous[address]
+	delete(fader.previous, address)
+	fader.fadeOuts[address] = settingFadeOut{time: setting.FadeOut, off: off}
+	cancel := fader.cancel
+	fader.lock.Unlock()
+
+	fadeIn := setting.FadeIn
+	if fadeIn == 0 && lastSet {
+		fadeIn = last.time
+	}
+
+	if setting.Delay == 0 && fadeIn == 0 {
+		fader.set(source, address, byte(value), dmxController, dmxInterfacePresent)
+		return
+	}
+	go fader.fade(source, address, byte(value), off, setting.Delay, fadeIn, cancel, dmxController, dmxInterfacePresent)
+}
+
+// finish fades out the channels the last state set that the new state doesn't.
+// Channels without a fade out time are left where they are.
+func (fader *settingFader) finish(source string, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
+	fader.lock.Lock()
+	defer fader.lock.Unlock()
+	for address, fadeOut := range fader.previous {
+		if fadeOut.time > 0 {
+			go fader.fade(source, address, fadeOut.off, fadeOut.off, 0, fadeOut.time, fader.cancel, dmxController, dmxInterfacePresent)
+		}
+	}
+	fader.previous = nil
+}
+
+// fade waits for the delay then fades a channel to a value, stopping where it is if cancelled.
+// A channel this fader hasn't set yet is still at its off value, so it fades from there.
+// Time spent frozen doesn't count.
+func (fader *settingFader) fade(source string, address int16, to byte, off byte, delay time.Duration, duration time.Duration, cancel chan bool, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
+
+	if !common.FreezableWait(delay, cancel) {
+		return
+	}
+
+	fader.lock.Lock()
+	from, ok := fader.values[address]
+	if !ok {
+		from = off
+	}
+	fader.lock.Unlock()
+
+	for elapsed := SETTING_FADE_FRAME; elapsed < duration; elapsed += SETTING_FADE_FRAME {
+		if !common.FreezableWait(SETTING_FADE_FRAME, cancel) {
+			return
+		}
+		fader.set(source, address, settingFadeValue(from, to, elapsed, duration), dmxController, dmxInterfacePresent)
+	}
+
+	select {
+	case <-cancel:
+	default:
+		fader.set(source, address, to, dmxController, dmxInterfacePresent)
+	}
+}
+
+func (fader *settingFader) set(source string, address int16, value byte, dmxController *ft232.DMXController, dmxInterfacePresent bool) {
+	fader.lock.Lock()
+	fader.values[address] = value
+	fader.lock.Unlock()
+	SetChannel(source, address, value, dmxController, dmxInterfacePresent)
+}
+
+// settingFadeValue works out a fading channel's value part way through its fade.
+func settingFadeValue(from byte, to byte, elapsed time.Duration, duration time.Duration) byte {
+	if elapsed >= duration {
+		return to
+	}
+	return byte(int(from) + (int(to)-int(from))*int(elapsed)/int(duration))
+}
+
+// settingOffValue returns the value a channel fades out to when the state that set it ends,
+// the value given to the channel in the fixture definition or zero if it hasn't got one.
+func settingOffValue(thisFixture *Fixture, channel int) byte {
+	if channel >= 0 && channel < len(thisFixture.Channels) && thisFixture.Channels[channel].Value != nil {
+		return byte(*thisFixture.Channels[channel].Value)
+	}
+	return 0
+}
+
+// Times returns how long a setting waits before it starts and how long it takes to fade in and out.
+func (setting Setting) Times() (delay time.Duration, fadeIn time.Duration, fadeOut time.Duration, err error) {
+	if delay, err = ParseSettingTime(setting.Delay); err != nil {
+		return 0, 0, 0, fmt.Errorf("setting %s delay: %w", setting.Name, err)
+	}
+	if fadeIn, err = ParseSettingTime(setting.FadeIn); err != nil {
+		return 0, 0, 0, fmt.Errorf("setting %s fade in: %w", setting.Name, err)
+	}
+	if fadeOut, err = ParseSettingTime(setting.FadeOut); err != nil {
+		return 0, 0, 0, fmt.Errorf("setting %s fade out: %w", setting.Name, err)
+	}
+	return delay, fadeIn, fadeOut, nil
+}
+
+// ParseSettingTime reads a setting time like 500ms or 2s, a plain number is in seconds.
+// An empty time is no time at all.
+func ParseSettingTime(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if IsNumericOnly(value) {
+		value = value + "s"
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("time %s isn't valid, use something like 500ms or 2s", value)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("time %s can't be negative", value)
+	}
+	return duration, nil
+}
+
 // Process settings.
-func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel int,
+// The fader plays each setting, so they can wait, fade in and out, and be cancelled when the switch moves on.
+func newMiniSetter(source string, thisFixture *Fixture, setting common.Setting, masterChannel int,
 	dmxController *ft232.DMXController,
 	master int,
-	dmxInterfacePresent bool) {
+	dmxInterfacePresent bool,
+	fader *settingFader) {
 
 	if debug {
 		fmt.Printf("settings are available\n")
@@ -50,6 +239,9 @@ func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel i
 		value, _ := strconv.ParseFloat(setting.FixtureValue, 32)
 
 		howBright := int((float64(value) / 100) * (float64(master) / 2.55))
+
+		// Apply this fixtures dimmer curve.
+		howBright = calibrateDimmer(howBright, thisFixture.Calibration)
 		if debug {
 			fmt.Printf("Fixture %s setting value %d master %d howBright %d\n", thisFixture.Name, int(value), master, howBright)
 		}
@@ -59,13 +251,13 @@ func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel i
 			if debug {
 				fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(masterChannel), int(howBright))
 			}
-			SetChannel(thisFixture.Address+int16(masterChannel), byte(reverse_dmx(howBright)), dmxController, dmxInterfacePresent)
+			fader.play(source, thisFixture.Address+int16(masterChannel), reverse_dmx(howBright), 255, setting, dmxController, dmxInterfacePresent)
 		} else {
 			// Set the master brightness value.
 			if debug {
 				fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(masterChannel), int(howBright))
 			}
-			SetChannel(thisFixture.Address+int16(masterChannel), byte(howBright), dmxController, dmxInterfacePresent)
+			fader.play(source, thisFixture.Address+int16(masterChannel), howBright, 0, setting, dmxController, dmxInterfacePresent)
 		}
 
 	} else {
@@ -80,7 +272,7 @@ func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel i
 				if debug {
 					fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(channel), value)
 				}
-				SetChannel(thisFixture.Address+int16(channel), byte(value), dmxController, dmxInterfacePresent)
+				fader.play(source, thisFixture.Address+int16(channel), limitSettingValue(thisFixture, int(channel), value), settingOffValue(thisFixture, int(channel)), setting, dmxController, dmxInterfacePresent)
 			} else {
 				// Handle the fact that the channel may be a label as well.
 				// Look for this channels number in this fixture identified by ID.
@@ -88,7 +280,7 @@ func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel i
 				if debug {
 					fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(channel), value)
 				}
-				SetChannel(thisFixture.Address+int16(channel), byte(value), dmxController, dmxInterfacePresent)
+				fader.play(source, thisFixture.Address+int16(channel), limitSettingValue(thisFixture, int(channel), value), settingOffValue(thisFixture, int(channel)), setting, dmxController, dmxInterfacePresent)
 			}
 
 		} else {
@@ -106,14 +298,14 @@ func newMiniSetter(thisFixture *Fixture, setting common.Setting, masterChannel i
 				if debug {
 					fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(channel), value)
 				}
-				SetChannel(thisFixture.Address+int16(channel), byte(value), dmxController, dmxInterfacePresent)
+				fader.play(source, thisFixture.Address+int16(channel), limitSettingValue(thisFixture, int(channel), value), settingOffValue(thisFixture, int(channel)), setting, dmxController, dmxInterfacePresent)
 			} else {
 				// Look for this channels number in this fixture identified by ID.
 				channel, _ := FindChannelNumberByName(thisFixture, setting.Channel)
 				if debug {
 					fmt.Printf("fixture %s: Control: send Setting %s Address %d Value %d \n", thisFixture.Name, setting.Name, thisFixture.Address+int16(channel), value)
 				}
-				SetChannel(thisFixture.Address+int16(channel), byte(value), dmxController, dmxInterfacePresent)
+				fader.play(source, thisFixture.Address+int16(channel), limitSettingValue(thisFixture, int(channel), value), settingOffValue(thisFixture, int(channel)), setting, dmxController, dmxInterfacePresent)
 			}
 		}
 	}
@@ -0,0 +1,287 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights programmer, it grabs fixtures and sets their
+// channels directly, overriding the sequences until it is cleared.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// The programmer writes into its own output layer, with a priority above any sequence or switch.
+const PROGRAMMER_SOURCE = "programmer"
+const PROGRAMMER_PRIORITY = 100
+
+// ProgrammerFixture identifies a fixture by its sequence and fixture number, both numbered from 0.
+type ProgrammerFixture struct {
+	Sequence int
+	Fixture  int
+}
+
+type Programmer struct {
+	mutex    sync.Mutex
+	selected []ProgrammerFixture
+	values   map[ProgrammerFixture]map[string]byte
+}
+
+// NewProgrammer creates an empty programmer.
+func NewProgrammer() *Programmer {
+	dmx.SetSourcePriority(PROGRAMMER_SOURCE, PROGRAMMER_PRIORITY)
+	return &Programmer{
+		values: make(map[ProgrammerFixture]map[string]byte),
+	}
+}
+
+// ToggleFixture adds a fixture to the selection or removes it if already selected.
+// Returns true if the fixture is now selected.
+func (p *Programmer) ToggleFixture(sequenceNumber int, fixtureNumber int) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	thisFixture := ProgrammerFixture{Sequence: sequenceNumber, Fixture: fixtureNumber}
+	for index, selected := range p.selected {
+		if selected == thisFixture {
+			p.selected = append(p.selected[:index], p.selected[index+1:]...)
+			return false
+		}
+	}
+	p.selected = append(p.selected, thisFixture)
+	return true
+}
+
+// IsSelected returns true if the fixture is selected in the programmer.
+func (p *Programmer) IsSelected(sequenceNumber int, fixtureNumber int) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, selected := range p.selected {
+		if selected.Sequence == sequenceNumber && selected.Fixture == fixtureNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// Selected returns the fixtures selected in the programmer in the order they were selected.
+func (p *Programmer) Selected() []ProgrammerFixture {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]ProgrammerFixture{}, p.selected...)
+}
+
+// Active returns true if the programmer is holding any channel values.
+func (p *Programmer) Active() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.values) > 0
+}
+
+// ChannelNames returns the names of the channels the selected fixtures have between them,
+// in the order they appear in the fixture definitions.
+func (p *Programmer) ChannelNames(fixtures *Fixtures) []string {
+
+	names := []string{}
+	found := make(map[string]bool)
+	for _, selected := range p.Selected() {
+		thisFixture, err := findFixtureByGroupAndNumber(selected.Sequence, selected.Fixture, fixtures)
+		if err != nil {
+			continue
+		}
+		for _, channel := range thisFixture.Channels {
+			if !found[channel.Name] {
+				found[channel.Name] = true
+				names = append(names, channel.Name)
+			}
+		}
+	}
+	return names
+}
+
+// GetChannel returns the value held for a channel on the first selected fixture which has one.
+func (p *Programmer) GetChannel(channelName string) (byte, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, selected := range p.selected {
+		if value, ok := p.values[selected][channelName]; ok {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// SetChannel sets a channel on every selected fixture which has a channel by that name.
+func (p *Programmer) SetChannel(channelName string, value byte, fixtures *Fixtures) {
+
+	for _, selected := range p.Selected() {
+		p.setFixtureChannel(selected, channelName, value, fixtures)
+	}
+}
+
+// setFixtureChannel sets a channel on one fixture and sends it to the programmer's output layer.
+func (p *Programmer) setFixtureChannel(selected ProgrammerFixture, channelName string, value byte, fixtures *Fixtures) {
+
+	thisFixture, err := findFixtureByGroupAndNumber(selected.Sequence, selected.Fixture, fixtures)
+	if err != nil {
+		fmt.Printf("programmer: %s\n", err.Error())
+		return
+	}
+
+	for channelNumber, channel := range thisFixture.Channels {
+		if channel.Name != channelName {
+			continue
+		}
+
+		if debug {
+			fmt.Printf("programmer: fixture %s channel %s value %d\n", thisFixture.Name, channelName, value)
+		}
+
+		p.mutex.Lock()
+		if p.values[selected] == nil {
+			p.values[selected] = make(map[string]byte)
+		}
+		p.values[selected][channelName] = value
+		p.mutex.Unlock()
+
+		// The pan and tilt limits still apply to the programmer.
+		limited := limitSettingValue(thisFixture, channelNumber, int(value))
+		dmx.SetChannel(PROGRAMMER_SOURCE, thisFixture.Address+int16(channelNumber), byte(limited))
+	}
+}
+
+// Clear deselects all the fixtures and hands them back to the sequences and switches.
+func (p *Programmer) Clear() {
+	p.mutex.Lock()
+	p.selected = []ProgrammerFixture{}
+	p.values = make(map[ProgrammerFixture]map[string]byte)
+	p.mutex.Unlock()
+
+	dmx.ReleaseSource(PROGRAMMER_SOURCE)
+}
+
+// Values returns every channel value held by the programmer.
+func (p *Programmer) Values() []common.ProgrammerValue {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	values := []common.ProgrammerValue{}
+	for thisFixture, channels := range p.values {
+		for channelName, value := range channels {
+			values = append(values, common.ProgrammerValue{
+				Sequence: thisFixture.Sequence,
+				Fixture:  thisFixture.Fixture,
+				Channel:  channelName,
+				Value:    value,
+			})
+		}
+	}
+	return values
+}
+
+// Load replaces the programmer's contents with a saved set of values.
+// The fixtures are left selected so they can be adjusted further.
+func (p *Programmer) Load(values []common.ProgrammerValue, fixtures *Fixtures) {
+
+	p.Clear()
+
+	for _, value := range values {
+		thisFixture := ProgrammerFixture{Sequence: value.Sequence, Fixture: value.Fixture}
+		if !p.IsSelected(value.Sequence, value.Fixture) {
+			p.ToggleFixture(value.Sequence, value.Fixture)
+		}
+		p.setFixtureChannel(thisFixture, value.Channel, value.Value, fixtures)
+	}
+}
+
+// RecordSwitchState adds a new state to a switch from the programmer values held for the fixture the switch uses.
+// Returns the new state's number.
+func (p *Programmer) RecordSwitchState(switchLabel string, fixtures *Fixtures) (int16, error) {
+
+	for switchNumber, swiTch := range fixtures.Fixtures {
+		if swiTch.Type != "switch" || swiTch.Label != switchLabel {
+			continue
+		}
+
+		usedFixture, err := findFixtureByLabel(swiTch.UseFixture, fixtures)
+		if err != nil {
+			return 0, fmt.Errorf("switch %s: %w", switchLabel, err)
+		}
+
+		p.mutex.Lock()
+		values := p.values[ProgrammerFixture{Sequence: usedFixture.Group - 1, Fixture: usedFixture.Number - 1}]
+		p.mutex.Unlock()
+		if len(values) == 0 {
+			return 0, fmt.Errorf("switch %s: no programmer values for fixture %s", switchLabel, usedFixture.Label)
+		}
+
+		stateNumber := int16(len(swiTch.States) + 1)
+		newState := State{
+			Name:        fmt.Sprintf("Programmer %d", stateNumber),
+			Number:      stateNumber,
+			Label:       fmt.Sprintf("Prog.%d", stateNumber),
+			ButtonColor: "White",
+			Master:      255,
+			Settings:    programmerSettings(usedFixture, values),
+		}
+		fixtures.Fixtures[switchNumber].States = append(fixtures.Fixtures[switchNumber].States, newState)
+		return stateNumber, nil
+	}
+	return 0, fmt.Errorf("switch %s not found", switchLabel)
+}
+
+// programmerSettings turns the programmer values for a fixture into switch state settings, in channel order.
+// Switch settings give master and dimmer levels as a percentage so those are converted.
+func programmerSettings(thisFixture *Fixture, values map[string]byte) []Setting {
+
+	intensityChannels := findIntensityChannels(thisFixture)
+
+	settings := []Setting{}
+	for channelNumber, channel := range thisFixture.Channels {
+		value, ok := values[channel.Name]
+		if !ok {
+			continue
+		}
+		settingValue := int(value)
+		if offValue, isIntensity := intensityChannels[thisFixture.Address+int16(channelNumber)]; isIntensity {
+			settingValue = dmx.Intensity(value, offValue) * 100 / 255
+		}
+		settings = append(settings, Setting{
+			Name:    channel.Name,
+			Label:   channel.Name,
+			Number:  len(settings) + 1,
+			Channel: channel.Name,
+			Value:   strconv.Itoa(settingValue),
+		})
+	}
+	return settings
+}
+
+// findFixtureByGroupAndNumber finds a fixture by its sequence and fixture number, both numbered from 0.
+func findFixtureByGroupAndNumber(sequenceNumber int, fixtureNumber int, fixtures *Fixtures) (*Fixture, error) {
+	for index, fixture := range fixtures.Fixtures {
+		if fixture.Type != "switch" && fixture.Group == sequenceNumber+1 && fixture.Number == fixtureNumber+1 {
+			return &fixtures.Fixtures[index], nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find fixture for sequence %d fixture %d", sequenceNumber, fixtureNumber)
+}
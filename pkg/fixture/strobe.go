@@ -0,0 +1,86 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights software strobe, used for fixtures that don't have
+// a strobe channel or a strobe setting on their shutter.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// hasHardwareStrobe returns true if the fixture has a strobe channel or a strobe setting on its shutter.
+func hasHardwareStrobe(fixture *Fixture) bool {
+	for _, channel := range fixture.Channels {
+		if strings.Contains(channel.Name, "Strobe") {
+			return true
+		}
+		if strings.Contains(channel.Name, "Shutter") {
+			for _, setting := range channel.Settings {
+				if strings.Contains(setting.Name, "Strobe") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// findStrobeChannels returns the channels the software strobe flashes and the value for each one when off.
+// The master dimmer is used if the fixture has one otherwise all the color channels are flashed.
+func findStrobeChannels(fixture *Fixture) map[int16]byte {
+
+	channels := findIntensityChannels(fixture)
+	if len(channels) > 0 {
+		return channels
+	}
+
+	for channelNumber, channel := range fixture.Channels {
+		if isColorChannel(channel.Name) {
+			channels[fixture.Address+int16(channelNumber)] = 0
+		}
+	}
+	return channels
+}
+
+// isColorChannel returns true for the channels which mix a fixture's color.
+func isColorChannel(name string) bool {
+	for _, color := range []string{"Red", "Green", "Blue", "White", "Amber", "UV"} {
+		if strings.HasPrefix(name, color) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSoftwareStrobe starts or stops the software strobe for a fixture without a hardware strobe.
+func setSoftwareStrobe(fixture *Fixture, strobe bool, strobeSpeed int) {
+
+	if hasHardwareStrobe(fixture) {
+		return
+	}
+
+	now := time.Now()
+	for address, offValue := range findStrobeChannels(fixture) {
+		if strobe {
+			dmx.StartSoftwareStrobe(address, strobeSpeed, offValue, now)
+		} else {
+			dmx.StopSoftwareStrobe(address)
+		}
+	}
+}
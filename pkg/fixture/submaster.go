@@ -0,0 +1,176 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These submasters dim single fixtures and whole groups of fixtures,
+// on top of the sequence masters and the global master brightness.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fixture
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/dmx"
+)
+
+// A submaster at full leaves its fixtures alone.
+const SUBMASTER_FULL = 255
+
+var submasterLock sync.Mutex
+var submasterFixtures = make(map[ProgrammerFixture]int) // Fixture levels by sequence and fixture number.
+var submasterGroups = make(map[int]int)                 // Group levels by group number.
+var submasterConfig *Fixtures                           // The fixtures last registered with the output stage.
+
+// SetFixtureSubmaster sets the submaster level of a fixture, sequence and fixture are numbered from 0.
+func SetFixtureSubmaster(sequenceNumber int, fixtureNumber int, level int) {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	submasterFixtures[ProgrammerFixture{Sequence: sequenceNumber, Fixture: fixtureNumber}] = limitSubmaster(level)
+	applySubmasters()
+}
+
+// FixtureSubmaster returns the submaster level of a fixture, sequence and fixture are numbered from 0.
+func FixtureSubmaster(sequenceNumber int, fixtureNumber int) int {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	level, ok := submasterFixtures[ProgrammerFixture{Sequence: sequenceNumber, Fixture: fixtureNumber}]
+	if !ok {
+		return SUBMASTER_FULL
+	}
+	return level
+}
+
+// SetGroupSubmaster sets the submaster level of a group, by its number in groups.yaml.
+func SetGroupSubmaster(groupNumber int, level int) {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	submasterGroups[groupNumber] = limitSubmaster(level)
+	applySubmasters()
+}
+
+// GroupSubmaster returns the submaster level of a group, by its number in groups.yaml.
+func GroupSubmaster(groupNumber int) int {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	level, ok := submasterGroups[groupNumber]
+	if !ok {
+		return SUBMASTER_FULL
+	}
+	return level
+}
+
+// Submasters returns every submaster which isn't at full, groups first.
+func Submasters() []common.SubmasterValue {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	values := []common.SubmasterValue{}
+	for groupNumber, level := range submasterGroups {
+		if level < SUBMASTER_FULL {
+			values = append(values, common.SubmasterValue{Group: groupNumber, Level: level})
+		}
+	}
+	for thisFixture, level := range submasterFixtures {
+		if level < SUBMASTER_FULL {
+			values = append(values, common.SubmasterValue{Sequence: thisFixture.Sequence, Fixture: thisFixture.Fixture, Level: level})
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Group != values[j].Group {
+			return values[i].Group > values[j].Group
+		}
+		if values[i].Sequence != values[j].Sequence {
+			return values[i].Sequence < values[j].Sequence
+		}
+		return values[i].Fixture < values[j].Fixture
+	})
+	return values
+}
+
+// LoadSubmasters replaces all the submasters with a saved set, any submaster not in the set goes back to full.
+func LoadSubmasters(values []common.SubmasterValue) {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	submasterFixtures = make(map[ProgrammerFixture]int)
+	submasterGroups = make(map[int]int)
+	for _, value := range values {
+		if value.Group > 0 {
+			submasterGroups[value.Group] = limitSubmaster(value.Level)
+		} else {
+			submasterFixtures[ProgrammerFixture{Sequence: value.Sequence, Fixture: value.Fixture}] = limitSubmaster(value.Level)
+		}
+	}
+	applySubmasters()
+}
+
+// registerSubmasters remembers the fixtures the submasters dim and works out their channels again.
+func registerSubmasters(fixtures *Fixtures) {
+	submasterLock.Lock()
+	defer submasterLock.Unlock()
+
+	submasterConfig = fixtures
+	applySubmasters()
+}
+
+// applySubmasters sends the level of each dimmable channel to the output stage.
+// Called with the submaster lock held.
+func applySubmasters() {
+	if submasterConfig == nil {
+		return
+	}
+	dmx.SetSubmasterLevels(submasterChannelLevels(submasterConfig, submasterFixtures, submasterGroups))
+}
+
+// submasterChannelLevels works out the level of the dimmable channels of every fixture from its own submaster
+// multiplied by its group's submaster. A channel shared by two fixtures takes the lower level.
+func submasterChannelLevels(fixtures *Fixtures, fixtureLevels map[ProgrammerFixture]int, groupLevels map[int]int) map[int16]int {
+
+	levels := make(map[int16]int)
+
+	for _, thisFixture := range fixtures.Fixtures {
+		level := SUBMASTER_FULL
+		if fixtureLevel, ok := fixtureLevels[ProgrammerFixture{Sequence: thisFixture.Group - 1, Fixture: thisFixture.Number - 1}]; ok {
+			level = fixtureLevel
+		}
+		if groupLevel, ok := groupLevels[thisFixture.Group]; ok {
+			level = level * groupLevel / SUBMASTER_FULL
+		}
+		if level >= SUBMASTER_FULL {
+			continue
+		}
+		for address := range findStrobeChannels(&thisFixture) {
+			if current, ok := levels[address]; !ok || level < current {
+				levels[address] = level
+			}
+		}
+	}
+	return levels
+}
+
+// limitSubmaster keeps a submaster level within 0-255.
+func limitSubmaster(level int) int {
+	if level < 0 {
+		return 0
+	}
+	if level > SUBMASTER_FULL {
+		return SUBMASTER_FULL
+	}
+	return level
+}
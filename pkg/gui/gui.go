@@ -36,9 +36,11 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"github.com/dhowlett99/dmxlights/pkg/buttons"
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/cues"
 	"github.com/dhowlett99/dmxlights/pkg/editor"
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
 	"github.com/dhowlett99/dmxlights/pkg/presets"
+	"github.com/dhowlett99/dmxlights/pkg/project"
 	"github.com/dhowlett99/dmxlights/pkg/sound"
 	"github.com/oliread/usbdmx"
 	"github.com/oliread/usbdmx/ft232"
@@ -53,18 +55,26 @@ type Button struct {
 }
 
 type MyPanel struct {
-	Buttons          [][]Button
-	SpeedLabel       *widget.Label
-	ShiftLabel       *widget.Label
-	SizeLabel        *widget.Label
-	FadeLabel        *widget.Label
-	VersionLabel     *widget.Button
-	TiltLabel        *widget.Label
-	RedLabel         *widget.Label
-	GreenLabel       *widget.Label
-	BlueLabel        *widget.Label
-	SensitivityLabel *widget.Label
-	MasterLabel      *widget.Label
+	Buttons           [][]Button
+	SpeedLabel        *widget.Label
+	ShiftLabel        *widget.Label
+	SizeLabel         *widget.Label
+	FadeLabel         *widget.Label
+	VersionLabel      *widget.Button
+	TiltLabel         *widget.Label
+	RedLabel          *widget.Label
+	GreenLabel        *widget.Label
+	BlueLabel         *widget.Label
+	SensitivityLabel  *widget.Label
+	MasterLabel       *widget.Label
+	PageLabel         *widget.Label
+	SequencePageLabel *widget.Label
+	ProgrammerLabel   *widget.Label
+	SubmasterLabel    *widget.Label
+	CueLabel          *widget.Label
+	FreezeLabel       *widget.Label
+	PresetPageLabel   *widget.Label
+	PresetFadeSelect  *widget.Select
 }
 
 func NewPanel() MyPanel {
@@ -251,6 +261,30 @@ func (panel *MyPanel) UpdateStatusBar(label string, hide bool, which string) {
 	if which == "master" {
 		panel.MasterLabel.SetText(label)
 	}
+	if which == "page" {
+		panel.PageLabel.SetText(label)
+	}
+	if which == "sequencepage" {
+		panel.SequencePageLabel.SetText(label)
+	}
+	if which == "programmer" {
+		panel.ProgrammerLabel.SetText(label)
+	}
+	if which == "submaster" {
+		panel.SubmasterLabel.SetText(label)
+	}
+	if which == "cue" {
+		panel.CueLabel.SetText(label)
+	}
+	if which == "freeze" {
+		panel.FreezeLabel.SetText(label)
+	}
+	if which == "presetpage" {
+		panel.PresetPageLabel.SetText(label)
+	}
+	if which == "presetfade" {
+		panel.PresetFadeSelect.SetSelected(label)
+	}
 }
 
 func (panel *MyPanel) ConvertButtonImageToIcon(filename string) []byte {
@@ -357,32 +391,63 @@ func (panel *MyPanel) GenerateRow(myWindow fyne.Window, rowNumber int,
 
 		var skipPopup bool
 		button.button = newNoHoverButton("     ", func() {
-			if X == 8 && Y == 5 || X > 7 || Y < 5 {
+			if !presets.IsPresetPad(this.PresetPage, X, Y-1) {
 				skipPopup = true
 			}
 			if this.SavePreset {
 				if !skipPopup {
 
-					// Popup name
+					// The preset on this pad, or the ID a new preset will be saved with.
+					id, found := presets.FindPreset(this.PresetsStore, this.PresetPage, X, Y-1)
+					if !found {
+						id = presets.NewPresetID(this.PresetsStore)
+					}
+					current := this.PresetsStore[id]
+
+					// Popup name, cues recall presets by their ID.
 					title := widget.NewLabel("Save Presets")
+					idLabel := widget.NewLabel("Preset ID " + id)
 
 					// Preset name.
 					presetInput := widget.NewEntry()
-					presetInput.Text = presets.GetPresetNumber(X, Y)
+					presetInput.Text = presets.DefaultLabel(this.PresetPage, X, Y-1)
 					presetLabel := widget.NewLabel("Preset Name")
 					preset := container.NewAdaptiveGrid(3, presetLabel, presetInput, layout.NewSpacer())
 
-					if this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y-1)].Label != "" {
-						presetInput.SetText(this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y-1)].Label)
+					if current.Label != "" {
+						presetInput.SetText(current.Label)
 					}
 
 					// Button color.
 					buttonColorSelect := widget.NewSelect([]string{"White", "Red", "Orange", "Yellow", "Green", "Cyan", "Blue", "Purple", "Pink", "Black"}, func(value string) {})
-					buttonColorSelect.Selected = this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y-1)].ButtonColor
+					buttonColorSelect.Selected = current.ButtonColor
 
 					buttonColorLabel := widget.NewLabel("Button Color")
 					buttonColor := container.NewAdaptiveGrid(3, buttonColorLabel, buttonColorSelect, layout.NewSpacer())
 
+					// Crossfade time, the global fade time is used unless the preset has its own.
+					fadeOptions := []string{"Global"}
+					for _, fade := range presets.FadeTimes {
+						fadeOptions = append(fadeOptions, presets.FadeLabel(fade))
+					}
+					fadeSelect := widget.NewSelect(fadeOptions, func(value string) {})
+					fadeSelect.Selected = "Global"
+					if current.Fade != nil {
+						fadeSelect.Selected = presets.FadeLabel(*current.Fade)
+					}
+					fadeLabel := widget.NewLabel("Fade Time")
+					fadeTime := container.NewAdaptiveGrid(3, fadeLabel, fadeSelect, layout.NewSpacer())
+
+					// Page, choosing another page moves the preset there.
+					pageOptions := []string{}
+					for _, page := range presets.Pages() {
+						pageOptions = append(pageOptions, presets.PageLabel(page))
+					}
+					pageSelect := widget.NewSelect(pageOptions, func(value string) {})
+					pageSelect.Selected = presets.PageLabel(this.PresetPage)
+					pageLabel := widget.NewLabel("Page")
+					page := container.NewAdaptiveGrid(3, pageLabel, pageSelect, layout.NewSpacer())
+
 					// Save button.
 					buttonSave := widget.NewButton("OK", func() {})
 
@@ -394,7 +459,7 @@ func (panel *MyPanel) GenerateRow(myWindow fyne.Window, rowNumber int,
 					decideContents := container.NewHBox(layout.NewSpacer(), buttonCancel, buttonSave)
 					decide := container.NewAdaptiveGrid(3, layout.NewSpacer(), layout.NewSpacer(), decideContents)
 
-					form := container.NewVBox(title, preset, buttonColor, decide)
+					form := container.NewVBox(container.NewHBox(title, layout.NewSpacer(), idLabel), preset, buttonColor, fadeTime, page, decide)
 
 					// Layout of settings panel.
 					popup = widget.NewModalPopUp(
@@ -408,9 +473,36 @@ func (panel *MyPanel) GenerateRow(myWindow fyne.Window, rowNumber int,
 						if presetInput.Text == "" { // We clicked cancel so give up labelling.
 							return
 						}
-						this.PresetsStore[fmt.Sprint(X)+","+fmt.Sprint(Y-1)] = presets.Preset{Label: presetInput.Text, State: true, Selected: true, ButtonColor: buttonColorSelect.Selected}
-						presets.SavePresets(this.PresetsStore)
-						presets.RefreshPresets(eventsForLauchpad, guiButtons, this.PresetsStore)
+						// Global leaves the preset without a fade time of its own.
+						var fade *float64
+						for _, fadeTime := range presets.FadeTimes {
+							if presets.FadeLabel(fadeTime) == fadeSelect.Selected {
+								fadeTime := fadeTime
+								fade = &fadeTime
+							}
+						}
+						// The preset was saved on this pad when the button was pressed.
+						saved := this.PresetsStore[id]
+						saved.Label = presetInput.Text
+						saved.State = true
+						saved.Selected = true
+						saved.ButtonColor = buttonColorSelect.Selected
+						saved.Fade = fade
+						this.PresetsStore[id] = saved
+						for _, page := range presets.Pages() {
+							if presets.PageLabel(page) == pageSelect.Selected && page != saved.Page {
+								err := presets.MovePreset(this.PresetsStore, id, page)
+								if err != nil {
+									fmt.Printf("error: %s\n", err.Error())
+								}
+							}
+						}
+						err := presets.SavePresets(this.PresetsStore)
+						if err != nil {
+							fmt.Printf("error: %s\n", err.Error())
+							PopupErrorMessage(myWindow, err.Error())
+						}
+						presets.RefreshPresets(eventsForLauchpad, guiButtons, this.PresetsStore, this.PresetPage)
 					}
 					popup.Show()
 				}
@@ -494,6 +586,34 @@ func PopupErrorMessage(myWindow fyne.Window, errorMessage string) {
 	popupErrorPanel.Show()
 }
 
+// PopupPresetsLoadError tells the user the presets file couldn't be read. The presets aren't saved,
+// so the file can be restored from a backup, unless the user says it can be overwritten.
+func PopupPresetsLoadError(myWindow fyne.Window, errorMessage string) {
+
+	popupPresetsPanel := &widget.PopUp{}
+
+	buttonKeep := widget.NewButton("Keep File", func() {
+		popupPresetsPanel.Hide()
+	})
+
+	buttonOverwrite := widget.NewButton("Overwrite When Saving", func() {
+		presets.AllowOverwrite()
+		popupPresetsPanel.Hide()
+	})
+
+	popupPresetsPanel = widget.NewModalPopUp(
+		container.NewVBox(
+			widget.NewLabel("Presets Can't Be Read"),
+			widget.NewLabel(errorMessage),
+			widget.NewLabel("Presets won't be saved, so the file can be restored from its .bak backups."),
+			widget.NewLabel("Or overwrite it with the presets made from now on."),
+			container.NewHBox(buttonKeep, buttonOverwrite),
+		),
+		myWindow.Canvas(),
+	)
+	popupPresetsPanel.Show()
+}
+
 func AreYouSureDialog(myWindow fyne.Window, message string) *widget.PopUp {
 
 	// Create a dialog for error messages.
@@ -526,22 +646,72 @@ func AreYouSureDialog(myWindow fyne.Window, message string) *widget.PopUp {
 // MakeToolbar generates a tool bar at the top of the main window.
 func MakeToolbar(myWindow fyne.Window, soundConfig *sound.SoundConfig,
 	guiButtons chan common.ALight, eventsForLaunchPad chan common.ALight, commandChannels []chan common.Command,
-	config *usbdmx.ControllerConfig, launchPadName string, fixturesConfig *fixture.Fixtures, startConfig *fixture.Fixtures) *widget.Toolbar {
+	config *usbdmx.ControllerConfig, launchPadName string, fixturesConfig *fixture.Fixtures, startConfig *fixture.Fixtures,
+	groupConfig *fixture.Groups, programmer *fixture.Programmer, sequences []*common.Sequence, this *buttons.CurrentState) *widget.Toolbar {
 
 	// Project open.
 	toolbar := widget.NewToolbar(
 		widget.NewToolbarAction(theme.FolderOpenIcon(), func() {
-			FileOpen(myWindow, startConfig, fixturesConfig, commandChannels)
+			FileOpen(myWindow, this, startConfig, fixturesConfig, groupConfig, commandChannels, eventsForLaunchPad, guiButtons)
 		}),
 
 		// Project save.
 		widget.NewToolbarAction(theme.FileIcon(), func() {
-			FileSave(myWindow, startConfig, fixturesConfig, commandChannels)
+			FileSave(myWindow, this, startConfig, fixturesConfig)
 		}),
 
 		widget.NewToolbarSeparator(),
+
+		// Programmer faders.
+		widget.NewToolbarAction(theme.ColorPaletteIcon(), func() {
+			modal := editor.NewProgrammerPanel(myWindow, programmer, sequences, fixturesConfig, commandChannels)
+			modal.Resize(fyne.NewSize(800, 550))
+			modal.Show()
+		}),
+
+		// Submasters for the groups and fixtures.
+		widget.NewToolbarAction(theme.VolumeUpIcon(), func() {
+			modal := editor.NewSubmastersPanel(myWindow, groupConfig, fixturesConfig)
+			modal.Resize(fyne.NewSize(800, 550))
+			modal.Show()
+		}),
+
+		// Master clock, tap tempo and the rate each sequence follows it at.
+		widget.NewToolbarAction(theme.HistoryIcon(), func() {
+			modal := editor.NewClockPanel(myWindow, sequences, commandChannels, this.SequenceChannels.UpdateChannels)
+			modal.Resize(fyne.NewSize(800, 550))
+			modal.Show()
+		}),
+
+		// Cue lists, with GO and BACK.
+		widget.NewToolbarAction(theme.MediaPlayIcon(), func() {
+			modal := editor.NewCuesPanel(myWindow, this.CuePlayer, fixturesConfig)
+			modal.Resize(fyne.NewSize(800, 600))
+			modal.Show()
+		}),
+
+		// Show the next page of sequences when there are more than the launchpad has rows for.
+		widget.NewToolbarAction(theme.ListIcon(), func() {
+			buttons.NextSequencePage(sequences, this, eventsForLaunchPad, guiButtons, commandChannels)
+		}),
+
+		// Show the faders on the grid or go back to the normal grid.
+		widget.NewToolbarAction(theme.ViewFullScreenIcon(), func() {
+			buttons.ToggleFaderPage(this, eventsForLaunchPad, guiButtons)
+		}),
+
+		// Dead blackout, cuts everything to black straight away whatever the blackout fade time.
+		widget.NewToolbarAction(theme.MediaStopIcon(), func() {
+			buttons.ToggleDeadBlackout(this, commandChannels, eventsForLaunchPad, guiButtons)
+		}),
+
+		// Freeze or unfreeze everything.
+		widget.NewToolbarAction(theme.MediaPauseIcon(), func() {
+			buttons.ToggleFreeze(this, eventsForLaunchPad, guiButtons)
+		}),
+
 		widget.NewToolbarAction(theme.SettingsIcon(), func() {
-			modal := RunSettingsPopUp(myWindow, soundConfig, guiButtons, eventsForLaunchPad, config, launchPadName)
+			modal := RunSettingsPopUp(myWindow, this, soundConfig, guiButtons, eventsForLaunchPad, config, launchPadName)
 			modal.Resize(fyne.NewSize(250, 250))
 			modal.Show()
 		}),
@@ -549,42 +719,122 @@ func MakeToolbar(myWindow fyne.Window, soundConfig *sound.SoundConfig,
 	return toolbar
 }
 
-func FileOpen(myWindow fyne.Window, startConfig *fixture.Fixtures, fixturesConfig *fixture.Fixtures, commandChannels []chan common.Command) {
+// FileOpen opens a project by choosing the fixtures.yaml in its directory. The project's fixtures,
+// groups and presets replace the ones being used. Projects saved as a single yaml file before
+// projects were directories are imported into a directory of their own first. The sequences are
+// made when dmxlights starts, so a project with different sequences is opened by restarting.
+func FileOpen(myWindow fyne.Window, this *buttons.CurrentState, startConfig *fixture.Fixtures, fixturesConfig *fixture.Fixtures,
+	groupConfig *fixture.Groups, commandChannels []chan common.Command, eventsForLaunchpad chan common.ALight, guiButtons chan common.ALight) {
 	fileOpener := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
 		if err == nil && reader != nil {
-			newFixturesConfig, err := fixture.LoadFixturesReader(reader)
+			reader.Close()
+			filename := reader.URI().Path()
+			dir := filepath.Dir(filename)
+
+			if filepath.Base(filename) != project.FIXTURES_FILE {
+				// An old single file project, its presets were never kept apart so it starts without any.
+				dir = strings.TrimSuffix(filename, filepath.Ext(filename))
+				if !project.IsProject(dir) {
+					err = project.Import(filename, project.Current(), false)
+					if err != nil {
+						fmt.Printf("dmxlights: error failed to import project: %s\n", err.Error())
+						PopupErrorMessage(myWindow, err.Error())
+						return
+					}
+				}
+			}
+
+			newFixturesConfig, err := fixture.LoadFixtures(filepath.Join(dir, project.FIXTURES_FILE))
 			if err != nil {
 				fmt.Printf("dmxlights: error failed to load fixtures: %s\n", err.Error())
 				PopupErrorMessage(myWindow, err.Error())
 				return
-			} else {
-				// Reset the startConfig.
-				startConfig.Fixtures = []fixture.Fixture{}
-				startConfig.Fixtures = append(startConfig.Fixtures, newFixturesConfig.Fixtures...)
-				filename := filepath.Base(reader.URI().String())
-				myWindow.SetTitle("DMX Lights:" + filename)
-
-				// Copy the newFixtures into the old pointer to the fixtures config.
-				fixturesConfig.Fixtures = newFixturesConfig.Fixtures
-
-				// Stop all the sequences.
-				cmd := common.Command{
-					Action: common.Reset,
-				}
-				common.SendCommandToAllSequence(cmd, commandChannels)
-				// Update the fixtures config in all the sequences.
-				cmd = common.Command{
-					Action: common.UpdateFixturesConfig,
-					Args: []common.Arg{
-						{Name: "FixturesConfig", Value: fixturesConfig},
-					},
+			}
+			newGroupConfig, err := fixture.LoadFixtureGroups(filepath.Join(dir, project.GROUPS_FILE))
+			if err != nil {
+				fmt.Printf("dmxlights: error failed to load groups: %s\n", err.Error())
+				PopupErrorMessage(myWindow, err.Error())
+				return
+			}
+
+			// The sequences are made when dmxlights starts, so a project with different sequences needs a restart.
+			if !sameFile(project.File(project.SEQUENCES_FILE), filepath.Join(dir, project.SEQUENCES_FILE)) {
+				message := fmt.Sprintf("The sequences in project %s are different.\nDMX Lights will close, start it again to use them.", project.Name(dir))
+				if theSame, _ := fixture.CheckFixturesAreTheSame(fixturesConfig, startConfig); !theSame {
+					message = message + "\nChanges to the fixtures in this project will be lost."
 				}
-				common.SendCommandToAllSequence(cmd, commandChannels)
+				dialog.ShowConfirm("Open Project", message, func(quit bool) {
+					if !quit {
+						return
+					}
+					err := project.SetLast(dir)
+					if err != nil {
+						fmt.Printf("dmxlights: error failed to remember project: %s\n", err.Error())
+						PopupErrorMessage(myWindow, err.Error())
+						return
+					}
+					os.Exit(0)
+				}, myWindow)
+				return
+			}
+
+			// From now on presets are saved in the new project.
+			project.SetCurrent(dir)
+			myWindow.SetTitle("DMX Lights:" + project.Name(dir))
+			err = project.SetLast(dir)
+			if err != nil {
+				fmt.Printf("dmxlights: error failed to remember project: %s\n", err.Error())
+			}
+
+			// Reset the startConfig.
+			startConfig.Fixtures = []fixture.Fixture{}
+			startConfig.Fixtures = append(startConfig.Fixtures, newFixturesConfig.Fixtures...)
+			startConfig.CueLists = cues.CopyCueLists(newFixturesConfig.CueLists)
+
+			// Copy the newFixtures into the old pointer to the fixtures config.
+			fixturesConfig.Fixtures = newFixturesConfig.Fixtures
+			fixturesConfig.CueLists = newFixturesConfig.CueLists
+			fixture.RegisterMergeChannels(fixturesConfig)
+			groupConfig.Groups = newGroupConfig.Groups
+
+			// Start the new project at the top of its first cue list.
+			this.CuePlayer.SetList(cues.FirstCueList(fixturesConfig.CueLists))
+
+			// Stop all the sequences.
+			cmd := common.Command{
+				Action: common.Reset,
+			}
+			common.SendCommandToAllSequence(cmd, commandChannels)
+			// Update the fixtures config in all the sequences.
+			cmd = common.Command{
+				Action: common.UpdateFixturesConfig,
+				Args: []common.Arg{
+					{Name: "FixturesConfig", Value: fixturesConfig},
+				},
+			}
+			common.SendCommandToAllSequence(cmd, commandChannels)
+
+			// Use the new project's settings.
+			settings, err := project.LoadSettings()
+			if err != nil {
+				fmt.Printf("dmxlights: error failed to load settings: %s\n", err.Error())
+				PopupErrorMessage(myWindow, err.Error())
 			}
+			this.BlackoutFadeTime = settings.BlackoutFadeTime
+			this.BlackoutReleaseTime = settings.BlackoutReleaseTime
+
+			// Show the new project's presets.
+			this.PresetsStore, err = presets.LoadPresets()
+			if err != nil {
+				fmt.Printf("dmxlights: error failed to load presets: %s\n", err.Error())
+				PopupPresetsLoadError(myWindow, err.Error())
+			}
+			this.LastPreset = nil
+			presets.RefreshPresets(eventsForLaunchpad, guiButtons, this.PresetsStore, this.PresetPage)
 		}
 	}, myWindow)
 	pwd, _ := os.Getwd()
-	currentmfolder, _ := filepath.Abs(pwd + "/projects")
+	currentmfolder, _ := filepath.Abs(pwd + "/" + project.PROJECTS_DIR)
 	if currentmfolder != "" {
 		mfileURI := storage.NewFileURI(currentmfolder)
 		mfileLister, _ := storage.ListerForURI(mfileURI)
@@ -598,38 +848,90 @@ func FileOpen(myWindow fyne.Window, startConfig *fixture.Fixtures, fixturesConfi
 	fileOpener.Show()
 }
 
-func FileSave(myWindow fyne.Window, startConfig *fixture.Fixtures, fixturesConfig *fixture.Fixtures, commandChannels []chan common.Command) {
-	fileSaver := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-		if err == nil && writer != nil {
-			err = fixture.SaveFixturesWriter(writer, fixturesConfig)
-			if err != nil {
-				fmt.Printf("dmxlights: error failed to save fixtures: %s\n", err.Error())
-			}
-			// Reset the startConfig.
-			startConfig.Fixtures = []fixture.Fixture{}
-			startConfig.Fixtures = append(startConfig.Fixtures, fixturesConfig.Fixtures...)
+// FileSave saves the project. Saving under a new name copies the groups, sequences and presets
+// into the new project's directory and carries on using the new project.
+func FileSave(myWindow fyne.Window, this *buttons.CurrentState, startConfig *fixture.Fixtures, fixturesConfig *fixture.Fixtures) {
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(project.Name(project.Current()))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Project Name", nameEntry),
+	}
+
+	dialog.ShowForm("Save Project", "Save", "Cancel", items, func(save bool) {
+		if !save {
+			return
+		}
+
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			PopupErrorMessage(myWindow, "Project name can't be empty or contain a /")
+			return
 		}
+
+		dir := project.Dir(name)
+
+		// Saving over another project replaces everything in it.
+		if dir != project.Current() && project.IsProject(dir) {
+			dialog.ShowConfirm("Save Project", fmt.Sprintf("Project %s already exists, replace it?", name), func(replace bool) {
+				if replace {
+					saveProject(myWindow, dir, this, startConfig, fixturesConfig)
+				}
+			}, myWindow)
+			return
+		}
+
+		saveProject(myWindow, dir, this, startConfig, fixturesConfig)
 	}, myWindow)
-	pwd, _ := os.Getwd()
-	currentmfolder, _ := filepath.Abs(pwd + "/projects")
-	if currentmfolder != "" {
-		mfileURI := storage.NewFileURI(currentmfolder)
-		mfileLister, _ := storage.ListerForURI(mfileURI)
+}
 
-		fileSaver.SetLocation(mfileLister)
-		fileSaver.SetFilter(&storage.ExtensionFileFilter{
-			Extensions: []string{
-				".yaml",
-			},
-		})
-		filename := strings.Split(myWindow.Title(), ":")
-		fileSaver.SetFileName(filename[1])
+// saveProject saves the fixtures and presets in the project in dir, copying the rest of the project there first if it's a new one.
+func saveProject(myWindow fyne.Window, dir string, this *buttons.CurrentState, startConfig *fixture.Fixtures, fixturesConfig *fixture.Fixtures) {
+
+	if dir != project.Current() {
+		err := project.Copy(project.Current(), dir)
+		if err != nil {
+			fmt.Printf("dmxlights: error failed to save project: %s\n", err.Error())
+			PopupErrorMessage(myWindow, err.Error())
+			return
+		}
+		project.SetCurrent(dir)
+		myWindow.SetTitle("DMX Lights:" + project.Name(dir))
 	}
-	fileSaver.Show()
 
+	err := fixture.SaveFixtures(project.File(project.FIXTURES_FILE), fixturesConfig)
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to save fixtures: %s\n", err.Error())
+		PopupErrorMessage(myWindow, err.Error())
+		return
+	}
+	err = presets.SavePresets(this.PresetsStore)
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to save presets: %s\n", err.Error())
+		PopupErrorMessage(myWindow, err.Error())
+	}
+
+	// Start with this project next time.
+	err = project.SetLast(dir)
+	if err != nil {
+		fmt.Printf("dmxlights: error failed to remember project: %s\n", err.Error())
+	}
+
+	// Reset the startConfig.
+	startConfig.Fixtures = []fixture.Fixture{}
+	startConfig.Fixtures = append(startConfig.Fixtures, fixturesConfig.Fixtures...)
+	startConfig.CueLists = cues.CopyCueLists(fixturesConfig.CueLists)
+}
+
+// sameFile returns true if both files hold the same thing.
+func sameFile(filename1 string, filename2 string) bool {
+	data1, err1 := os.ReadFile(filename1)
+	data2, err2 := os.ReadFile(filename2)
+	return err1 == nil && err2 == nil && string(data1) == string(data2)
 }
 
-func RunSettingsPopUp(w fyne.Window, soundConfig *sound.SoundConfig,
+func RunSettingsPopUp(w fyne.Window, this *buttons.CurrentState, soundConfig *sound.SoundConfig,
 	guiButtons chan common.ALight, eventsForLaunchPad chan common.ALight, config *usbdmx.ControllerConfig, launchPadName string) (modal *widget.PopUp) {
 
 	selectedInput := soundConfig.GetDeviceName()
@@ -668,9 +970,39 @@ func RunSettingsPopUp(w fyne.Window, soundConfig *sound.SoundConfig,
 	})
 	audioInterfaceSelect.PlaceHolder = selectedInput
 
+	// Blackout fade to black and release times, zero is instant.
+	var blackoutTimes []string
+	for _, fadeTime := range buttons.BlackoutFadeTimes {
+		blackoutTimes = append(blackoutTimes, fadeTime.String())
+	}
+	blackoutFadeTime := this.BlackoutFadeTime
+	blackoutFadeLabel := widget.NewLabel("Blackout Fade Time")
+	blackoutFadeSelect := widget.NewSelect(blackoutTimes, func(value string) {
+		blackoutFadeTime, _ = time.ParseDuration(value)
+	})
+	blackoutFadeSelect.SetSelected(this.BlackoutFadeTime.String())
+
+	blackoutReleaseTime := this.BlackoutReleaseTime
+	blackoutReleaseLabel := widget.NewLabel("Blackout Release Time")
+	blackoutReleaseSelect := widget.NewSelect(blackoutTimes, func(value string) {
+		blackoutReleaseTime, _ = time.ParseDuration(value)
+	})
+	blackoutReleaseSelect.SetSelected(this.BlackoutReleaseTime.String())
+
 	// Ok button.
 	button := widget.NewButton("OK", func() {
 		modal.Hide()
+		this.BlackoutFadeTime = blackoutFadeTime
+		this.BlackoutReleaseTime = blackoutReleaseTime
+		// The blackout times are kept with the project.
+		err := project.SaveSettings(project.Settings{
+			BlackoutFadeTime:    blackoutFadeTime,
+			BlackoutReleaseTime: blackoutReleaseTime,
+		})
+		if err != nil {
+			fmt.Printf("dmxlights: error failed to save settings: %s\n", err.Error())
+			PopupErrorMessage(w, err.Error())
+		}
 		soundConfig.StopSoundConfig()
 		soundConfig.StartSoundConfig(selectedInput, guiButtons, eventsForLaunchPad)
 	})
@@ -682,6 +1014,8 @@ func RunSettingsPopUp(w fyne.Window, soundConfig *sound.SoundConfig,
 			container.NewHBox(dmxInterfaceLabel, dmxInterfaceSelect),
 			container.NewHBox(launchpadLabel, launchpadSelect),
 			container.NewHBox(audioInterfaceLabel, audioInterfaceSelect),
+			container.NewHBox(blackoutFadeLabel, blackoutFadeSelect),
+			container.NewHBox(blackoutReleaseLabel, blackoutReleaseSelect),
 			widget.NewLabel(""),
 			container.NewHBox(layout.NewSpacer(), button),
 		),
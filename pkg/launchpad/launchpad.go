@@ -39,7 +39,8 @@ func NewLaunchPad() (*pad.Pad, error) {
 	return pad, nil
 }
 
-// main thread is used to get commands from the lauchpad.
+// main thread is used to get commands from the lauchpad and the cues the cue player wants recalled.
+// Runs without a launchpad too, so the cues are still recalled.
 func ReadLaunchPadButtons(guiButtons chan common.ALight, this *buttons.CurrentState, sequences []*common.Sequence,
 	eventsForLaunchpad chan common.ALight, dmxController *ft232.DMXController,
 	fixturesConfig *fixture.Fixtures, commandChannels []chan common.Command,
@@ -49,15 +50,22 @@ func ReadLaunchPadButtons(guiButtons chan common.ALight, this *buttons.CurrentSt
 	// Create a channel to listen for buttons being pressed.
 	// Send the button pressed hit to the button channel.
 	buttonChannel := make(chan pad.Hit)
-	go func() {
-		this.Pad.Listen(buttonChannel)
-	}()
+	if this.LaunchPadConnected {
+		go func() {
+			this.Pad.Listen(buttonChannel)
+		}()
+	}
 
 	// Main loop reading commands from the Novation Launchpad.
 	for {
-		hit := <-buttonChannel
-		this.GUI = false
-		buttons.ProcessButtons(hit.X, hit.Y, sequences, this, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels)
+		select {
+		case hit := <-buttonChannel:
+			this.GUI = false
+			buttons.ProcessButtons(hit.X, hit.Y, sequences, this, eventsForLaunchpad, guiButtons, dmxController, fixturesConfig, commandChannels, replyChannels, updateChannels)
+
+		case recall := <-this.CueRecalls:
+			buttons.RecallCue(recall.Number, recall.Cue, sequences, this, fixturesConfig, commandChannels, eventsForLaunchpad, guiButtons, updateChannels)
+		}
 	}
 }
 
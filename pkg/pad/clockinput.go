@@ -0,0 +1,101 @@
+// MIDI clock input, lets the master clock follow MIDI clock from any MIDI port,
+// not just the Launchpad's.
+
+package pad
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/clock"
+	"github.com/pkg/errors"
+	"github.com/scgolang/midi"
+)
+
+// The port chosen to follow MIDI clock from, nil for none.
+var clockInputLock sync.Mutex
+var clockInput *midi.Device
+
+// ClockInputs returns the names of the MIDI ports that can send us clock.
+func ClockInputs() ([]string, error) {
+	devices, err := midi.Devices()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing MIDI devices")
+	}
+	names := []string{}
+	for _, device := range devices {
+		if device.Type == midi.DeviceInput || device.Type == midi.DeviceDuplex {
+			names = append(names, device.Name)
+		}
+	}
+	return names, nil
+}
+
+// ClockInput returns the name of the MIDI port clock is followed from, empty for none.
+func ClockInput() string {
+	clockInputLock.Lock()
+	defer clockInputLock.Unlock()
+	if clockInput == nil {
+		return ""
+	}
+	return clockInput.Name
+}
+
+// SetClockInput follows MIDI clock from the named port, sending it to the master clock.
+// An empty name stops following MIDI clock.
+func SetClockInput(name string) error {
+	clockInputLock.Lock()
+	defer clockInputLock.Unlock()
+
+	if clockInput != nil {
+		clockInput.Close()
+		clockInput = nil
+	}
+	if name == "" {
+		return nil
+	}
+
+	devices, err := midi.Devices()
+	if err != nil {
+		return errors.Wrap(err, "listing MIDI devices")
+	}
+	for _, device := range devices {
+		if device.Name != name || (device.Type != midi.DeviceInput && device.Type != midi.DeviceDuplex) {
+			continue
+		}
+		if err := device.Open(); err != nil {
+			return errors.Wrap(err, "opening MIDI clock input "+name)
+		}
+		packets, err := device.Packets()
+		if err != nil {
+			device.Close()
+			return errors.Wrap(err, "reading MIDI clock input "+name)
+		}
+		clockInput = device
+		go listenForClock(device, packets)
+		return nil
+	}
+	return fmt.Errorf("MIDI clock input %s not found", name)
+}
+
+// listenForClock sends the real time messages from a port to the master clock, until the port is closed.
+func listenForClock(device *midi.Device, packets <-chan []midi.Packet) {
+	for events := range packets {
+		for _, packet := range events {
+			if packet.Err != nil {
+				// Closing the port ends the read with an error, only report it if we're still using the port.
+				clockInputLock.Lock()
+				current := clockInput == device
+				clockInputLock.Unlock()
+				if current {
+					fmt.Printf("error: MIDI clock input %s: %s\n", device.Name, packet.Err.Error())
+				}
+				return
+			}
+			if packet.Data[0] >= clock.MIDI_CLOCK {
+				clock.MidiClock(packet.Data[0], time.Now())
+			}
+		}
+	}
+}
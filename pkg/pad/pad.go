@@ -8,6 +8,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/dhowlett99/dmxlights/pkg/clock"
 	"github.com/pkg/errors"
 	"github.com/scgolang/midi"
 )
@@ -105,6 +106,11 @@ func (pad *Pad) Listen(buttonchannel chan Hit) error {
 			}
 			var x, y int
 
+			// Real time messages aren't buttons, MIDI clock is read from the port chosen for it.
+			if packet.Data[0] >= clock.MIDI_CLOCK {
+				continue
+			}
+
 			// Button pressed codes.
 			if packet.Data[2] > 0 {
 				x = int(packet.Data[1])%10 - 1
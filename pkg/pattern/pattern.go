@@ -798,6 +798,50 @@ type Coordinate struct {
 	Pan  int
 }
 
+// FitPattern fits an RGB pattern to a sequence with more fixtures than the pattern was written for.
+// Scale stretches the pattern so each pattern fixture drives a group of neighbouring fixtures,
+// tile repeats the pattern along the row of fixtures.
+// Patterns which are already wide enough are returned unchanged.
+func FitPattern(patternIn common.Pattern, numberFixtures int, fit string) common.Pattern {
+
+	// Find how many fixtures the pattern was written for.
+	var width int
+	for _, step := range patternIn.Steps {
+		if len(step.Fixtures) > width {
+			width = len(step.Fixtures)
+		}
+	}
+
+	if width == 0 || numberFixtures <= width {
+		return patternIn
+	}
+
+	if debug {
+		fmt.Printf("FitPattern: %s %d fixtures wide to %d fixtures using %s\n", patternIn.Name, width, numberFixtures, fit)
+	}
+
+	patternOut := patternIn
+	patternOut.Fixtures = numberFixtures
+	patternOut.Steps = []common.Step{}
+
+	for _, step := range patternIn.Steps {
+		newStep := step
+		newStep.Fixtures = make(map[int]common.Fixture, numberFixtures)
+		for fixtureNumber := 0; fixtureNumber < numberFixtures; fixtureNumber++ {
+			var source int
+			if fit == common.PATTERN_FIT_TILE {
+				source = fixtureNumber % width
+			} else {
+				source = fixtureNumber * width / numberFixtures
+			}
+			newStep.Fixtures[fixtureNumber] = step.Fixtures[source]
+		}
+		patternOut.Steps = append(patternOut.Steps, newStep)
+	}
+
+	return patternOut
+}
+
 func GetNumberEnabledScanners(scannerState map[int]common.FixtureState, numberOfFixtures int) int {
 
 	var getNumberEnabledScanners int
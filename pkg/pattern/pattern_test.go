@@ -1266,3 +1266,66 @@ func Test_traingle(t *testing.T) {
 		})
 	}
 }
+
+func TestFitPattern(t *testing.T) {
+
+	red := common.Color{R: 255}
+	blue := common.Color{B: 255}
+
+	twoWide := common.Pattern{
+		Name: "test",
+		Steps: []common.Step{
+			{
+				Fixtures: map[int]common.Fixture{
+					0: {Color: red},
+					1: {Color: blue},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		numberFixtures int
+		fit            string
+		want           []common.Color
+	}{
+		{
+			name:           "pattern already wide enough",
+			numberFixtures: 2,
+			fit:            common.PATTERN_FIT_SCALE,
+			want:           []common.Color{red, blue},
+		},
+		{
+			name:           "scale to four fixtures",
+			numberFixtures: 4,
+			fit:            common.PATTERN_FIT_SCALE,
+			want:           []common.Color{red, red, blue, blue},
+		},
+		{
+			name:           "tile to four fixtures",
+			numberFixtures: 4,
+			fit:            common.PATTERN_FIT_TILE,
+			want:           []common.Color{red, blue, red, blue},
+		},
+		{
+			name:           "scale to odd number of fixtures",
+			numberFixtures: 3,
+			fit:            common.PATTERN_FIT_SCALE,
+			want:           []common.Color{red, red, blue},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FitPattern(twoWide, tt.numberFixtures, tt.fit)
+			if len(got.Steps[0].Fixtures) != len(tt.want) {
+				t.Fatalf("FitPattern() got %d fixtures, want %d", len(got.Steps[0].Fixtures), len(tt.want))
+			}
+			for fixtureNumber, color := range tt.want {
+				if got.Steps[0].Fixtures[fixtureNumber].Color != color {
+					t.Errorf("FitPattern() fixture %d got %+v, want %+v", fixtureNumber, got.Steps[0].Fixtures[fixtureNumber].Color, color)
+				}
+			}
+		})
+	}
+}
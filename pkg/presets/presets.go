@@ -1,7 +1,7 @@
 // Copyright (C) 2022,2023 dhowlett99.
 // This is the dmxlights presets mechanism, controlling the saving and
 // recalling of sequence configurations.
-// All presets are saved in configX,Y.json files.
+// Each preset is saved in a config<ID>.json file in the project.
 //
 // This program is free software: you can redistribute it and/or modify
 // it under the terms of the GNU General Public License as published by
@@ -21,45 +21,169 @@ package presets
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/project"
 )
 
+// Presets are shown a page (bank) at a time in the three rows below the sequences.
+const NUMBER_PRESET_PAGES = 8
+const FIRST_PRESET_ROW = 4
+const LAST_PRESET_ROW = 6
+
+// The full page uses every pad on the launchpad for presets.
+const FULL_PAGE = -1
+const FULL_PAGE_ROWS = 8
+
+// Presets are stored by ID, a preset keeps its ID and the files saved with it when it moves between pages.
 type Preset struct {
-	State       bool   `json:"state"`
-	Selected    bool   `json:"-"`
-	Label       string `json:"label"`
-	ButtonColor string `json:"buttoncolor"`
+	Page        int      `json:"page"` // Page the preset is on, FULL_PAGE for the full page.
+	X           int      `json:"x"`    // Pad the preset is on.
+	Y           int      `json:"y"`    // Pad the preset is on.
+	State       bool     `json:"state"`
+	Selected    bool     `json:"-"`
+	Label       string   `json:"label"`
+	ButtonColor string   `json:"buttoncolor"`
+	Fade        *float64 `json:"fade,omitempty"` // Crossfade time in seconds, nil uses the global fade time and zero snaps.
+}
+
+// Global crossfade times offered for recalling presets, in seconds. Zero snaps straight to the preset.
+var FadeTimes = []float64{0, 0.5, 1, 2, 3, 5, 10}
+
+// When the presets file can't be read the presets in memory are empty, saving them would overwrite
+// the file and the backups with nothing. So saving is refused until the file loads or the user says
+// it can be overwritten.
+var loadLock sync.Mutex
+var loadFailed bool
+
+// IsPresetPad returns true if the pad at X,Y holds a preset on the given page.
+func IsPresetPad(page int, X int, Y int) bool {
+	if X < 0 || X > 7 {
+		return false
+	}
+	if page == FULL_PAGE {
+		return Y >= 0 && Y < FULL_PAGE_ROWS
+	}
+	return Y >= FIRST_PRESET_ROW && Y <= LAST_PRESET_ROW
+}
+
+// FindPreset returns the ID of the preset on the pad at X,Y of the given page.
+func FindPreset(presets map[string]Preset, page int, X int, Y int) (string, bool) {
+	for id, preset := range presets {
+		if preset.State && preset.Page == page && preset.X == X && preset.Y == Y {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// NewPresetID returns an ID not used by any preset. IDs are never reused, so a cue
+// asking for a deleted preset doesn't pick up a new one saved later.
+func NewPresetID(presets map[string]Preset) string {
+	highest := 0
+	for id := range presets {
+		number, err := strconv.Atoi(id)
+		if err == nil && number > highest {
+			highest = number
+		}
+	}
+	return strconv.Itoa(highest + 1)
 }
 
-// RefeshPresets is used to refresh the view of presets.
-func RefreshPresets(eventsForLauchpad chan common.ALight, guiButtons chan common.ALight, presets map[string]Preset) {
-	for y := 4; y < 7; y++ {
+// MovePreset moves a preset to the first free pad on another page.
+func MovePreset(presets map[string]Preset, id string, page int) error {
+	preset, found := presets[id]
+	if !found || !preset.State {
+		return fmt.Errorf("preset %s not found", id)
+	}
+	if preset.Page == page {
+		return nil
+	}
+	rows := []int{FIRST_PRESET_ROW, LAST_PRESET_ROW}
+	if page == FULL_PAGE {
+		rows = []int{0, FULL_PAGE_ROWS - 1}
+	}
+	for Y := rows[0]; Y <= rows[1]; Y++ {
+		for X := 0; X < 8; X++ {
+			if _, used := FindPreset(presets, page, X, Y); !used {
+				preset.Page = page
+				preset.X = X
+				preset.Y = Y
+				presets[id] = preset
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%s is full", PageLabel(page))
+}
+
+// Pages lists the pages of presets, the full page last.
+func Pages() []int {
+	pages := []int{}
+	for page := 0; page < NUMBER_PRESET_PAGES; page++ {
+		pages = append(pages, page)
+	}
+	return append(pages, FULL_PAGE)
+}
+
+// PageLabel names a page of presets.
+func PageLabel(page int) string {
+	if page == FULL_PAGE {
+		return "Full Page"
+	}
+	return fmt.Sprintf("Page %d", page+1)
+}
+
+// DefaultLabel is the label given to a new preset, presets are numbered across the pages.
+func DefaultLabel(page int, X int, Y int) string {
+	if page == FULL_PAGE {
+		return fmt.Sprintf("Full%d", Y*8+X+1)
+	}
+	presetsPerPage := (LAST_PRESET_ROW - FIRST_PRESET_ROW + 1) * 8
+	return fmt.Sprintf("Preset%d", page*presetsPerPage+(Y-FIRST_PRESET_ROW)*8+X+1)
+}
+
+// RefeshPresets is used to refresh the view of the presets on a page.
+func RefreshPresets(eventsForLauchpad chan common.ALight, guiButtons chan common.ALight, presets map[string]Preset, page int) {
+
+	firstRow := FIRST_PRESET_ROW
+	lastRow := LAST_PRESET_ROW
+	if page == FULL_PAGE {
+		firstRow = 0
+		lastRow = FULL_PAGE_ROWS - 1
+	}
+
+	for y := firstRow; y <= lastRow; y++ {
 		for x := 0; x < 8; x++ {
+			id, found := FindPreset(presets, page, x, y)
+			preset := presets[id]
 			// State true is a preset which is being used and has a saved config.
-			if presets[fmt.Sprint(x)+","+fmt.Sprint(y)].State {
+			if found {
 				// Selected preset is set to it's flashing color.
-				if presets[fmt.Sprint(x)+","+fmt.Sprint(y)].Selected {
+				if preset.Selected {
 					// Selected.
-					if presets[fmt.Sprint(x)+","+fmt.Sprint(y)].ButtonColor == "" {
+					if preset.ButtonColor == "" {
 						// There's no color defined so flash red & yellow.
 						common.FlashLight(common.Button{X: x, Y: y}, common.Red, common.PresetYellow, eventsForLauchpad, guiButtons)
 					} else {
 						// There is a color in the presets datatbase so set the color
-						color, _ := common.GetRGBColorByName(presets[fmt.Sprint(x)+","+fmt.Sprint(y)].ButtonColor)
+						color, _ := common.GetRGBColorByName(preset.ButtonColor)
 						common.FlashLight(common.Button{X: x, Y: y}, color, common.PresetYellow, eventsForLauchpad, guiButtons)
 					}
 				} else {
 					// Not Selected and there's no button color defined so just light the lamp red.
-					if presets[fmt.Sprint(x)+","+fmt.Sprint(y)].ButtonColor == "" {
+					if preset.ButtonColor == "" {
 						color := common.Red
 						common.LightLamp(common.Button{X: x, Y: y}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 
 					} else {
 						// We're not selected and there is a button color specified so set that color.
-						color, _ := common.GetRGBColorByName(presets[fmt.Sprint(x)+","+fmt.Sprint(y)].ButtonColor)
+						color, _ := common.GetRGBColorByName(preset.ButtonColor)
 						common.LightLamp(common.Button{X: x, Y: y}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 					}
 				}
@@ -67,117 +191,132 @@ func RefreshPresets(eventsForLauchpad chan common.ALight, guiButtons chan common
 				// Unused preset is set to yellow.
 				common.LightLamp(common.Button{X: x, Y: y}, common.PresetYellow, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 			}
-			common.LabelButton(x, y, presets[fmt.Sprint(x)+","+fmt.Sprint(y)].Label, guiButtons)
+			common.LabelButton(x, y, preset.Label, guiButtons)
 		}
 	}
 }
 
 // ClearPresets is used to un-select all presets.
 func ClearPresets(eventsForLauchpad chan common.ALight, guiButtons chan common.ALight, presets map[string]Preset) {
-	for y := 4; y < 7; y++ {
-		for x := 0; x < 8; x++ {
-			newPreset := presets[fmt.Sprint(x)+","+fmt.Sprint(y)]
-			newPreset.Selected = false
-			presets[fmt.Sprint(x)+","+fmt.Sprint(y)] = newPreset
-		}
+	for id, preset := range presets {
+		preset.Selected = false
+		presets[id] = preset
 	}
 }
 
-func SavePresets(presets map[string]Preset) {
+// SavePresets saves the presets in the project being used. The file is replaced in one go and
+// the previous one kept as a backup, so a crash while saving never loses the presets.
+func SavePresets(presets map[string]Preset) error {
+
+	filename := project.File(project.PRESETS_FILE)
+
+	if LoadFailed() {
+		return fmt.Errorf("not saving presets, %s couldn't be read and would be overwritten", filename)
+	}
+
 	// Marshall the config into a json object.
 	data, err := json.MarshalIndent(presets, "", " ")
 	if err != nil {
-		log.Fatalf("error: marshalling config: %v", err)
+		return fmt.Errorf("marshalling presets: %v", err)
 	}
 
 	// Write to file
-	err = os.WriteFile("presets.json", data, 0644)
+	err = project.WriteFile(filename, data)
 	if err != nil {
-		log.Fatalf("error: writing config: %v to file:%s", err, "presets.json")
+		return fmt.Errorf("writing presets: %v to file:%s", err, filename)
 	}
+	return nil
 }
 
-func LoadPresets() map[string]Preset {
+// LoadPresets loads the presets from the project being used.
+// A project without any presets saved yet has none, an unreadable presets file is an error.
+func LoadPresets() (map[string]Preset, error) {
 
 	presets := map[string]Preset{}
+	filename := project.File(project.PRESETS_FILE)
 
 	// Read the file.
-	data, err := os.ReadFile("presets.json")
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		setLoadFailed(false)
+		return presets, nil
+	}
 	if err != nil {
-		fmt.Printf("error reading presets: %v from file:%s\n", err, "presets.json")
-		return presets
+		setLoadFailed(true)
+		return presets, fmt.Errorf("reading presets: %v from file:%s", err, filename)
 	}
 
 	err = json.Unmarshal(data, &presets)
 	if err != nil {
-		log.Fatalf("error unmashalling presets: %v from file:%s", err, "presets.json")
-	}
-
-	return presets
-}
-
-func GetPresetNumber(X int, Y int) string {
-
-	switch Y {
-	case 5:
-		switch X {
-		case 0:
-			return "Preset1"
-		case 1:
-			return "Preset2"
-		case 2:
-			return "Preset3"
-		case 3:
-			return "Preset4"
-		case 4:
-			return "Preset5"
-		case 5:
-			return "Preset6"
-		case 6:
-			return "Preset7"
-		case 7:
-			return "Preset8"
-		}
+		setLoadFailed(true)
+		return map[string]Preset{}, fmt.Errorf("reading presets: %v from file:%s", err, filename)
+	}
 
-	case 6:
-		switch X {
-		case 0:
-			return "Preset9"
-		case 1:
-			return "Preset10"
-		case 2:
-			return "Preset11"
-		case 3:
-			return "Preset12"
-		case 4:
-			return "Preset13"
-		case 5:
-			return "Preset14"
-		case 6:
-			return "Preset15"
-		case 7:
-			return "Preset16"
-		}
+	setLoadFailed(false)
+	return migratePresets(presets), nil
+}
+
+// LoadFailed returns true if the presets file couldn't be read, the presets won't be saved until
+// they load or AllowOverwrite is called.
+func LoadFailed() bool {
+	loadLock.Lock()
+	defer loadLock.Unlock()
+	return loadFailed
+}
+
+// AllowOverwrite lets the presets be saved over a presets file that couldn't be read.
+// Called once the user has said it can go.
+func AllowOverwrite() {
+	setLoadFailed(false)
+}
+
+func setLoadFailed(failed bool) {
+	loadLock.Lock()
+	loadFailed = failed
+	loadLock.Unlock()
+}
 
-	case 7:
-		switch X {
-		case 0:
-			return "Preset17"
-		case 1:
-			return "Preset18"
-		case 2:
-			return "Preset19"
-		case 3:
-			return "Preset20"
-		case 4:
-			return "Preset21"
-		case 5:
-			return "Preset22"
-		case 6:
-			return "Preset23"
-		case 7:
-			return "Preset24"
+// migratePresets converts presets saved by pad as "X,Y" to the first page, keeping their files.
+// The ID "X.Y" matches the configX.Y.json file the preset was saved in.
+func migratePresets(presets map[string]Preset) map[string]Preset {
+	migrated := map[string]Preset{}
+	for key, preset := range presets {
+		parts := strings.Split(key, ",")
+		if len(parts) != 2 {
+			migrated[key] = preset
+			continue
+		}
+		X, errX := strconv.Atoi(parts[0])
+		Y, errY := strconv.Atoi(parts[1])
+		if errX != nil || errY != nil {
+			fmt.Printf("error: preset %q isn't a pad location\n", key)
+			continue
 		}
+		// Old files kept empty presets, we only keep the ones being used.
+		if !preset.State {
+			continue
+		}
+		preset.Page = 0
+		preset.X = X
+		preset.Y = Y
+		migrated[fmt.Sprintf("%d.%d", X, Y)] = preset
+	}
+	return migrated
+}
+
+// FadeTime returns the crossfade time used to recall a preset, its own time if it has one, otherwise the global time.
+func FadeTime(preset Preset, globalFade float64) time.Duration {
+	fade := globalFade
+	if preset.Fade != nil {
+		fade = *preset.Fade
+	}
+	return time.Duration(fade * float64(time.Second))
+}
+
+// FadeLabel describes a crossfade time.
+func FadeLabel(fade float64) string {
+	if fade <= 0 {
+		return "Snap"
 	}
-	return ""
+	return fmt.Sprintf("Fade %ss", strconv.FormatFloat(fade, 'f', -1, 64))
 }
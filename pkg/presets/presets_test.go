@@ -0,0 +1,268 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights presets tests.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package presets
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dhowlett99/dmxlights/pkg/project"
+)
+
+func TestFadeTime(t *testing.T) {
+	snap := 0.0
+	half := 0.5
+	tests := []struct {
+		name       string
+		preset     Preset
+		globalFade float64
+		want       time.Duration
+	}{
+		{
+			name:       "snap",
+			preset:     Preset{},
+			globalFade: 0,
+			want:       0,
+		},
+		{
+			name:       "global fade time",
+			preset:     Preset{},
+			globalFade: 2,
+			want:       2 * time.Second,
+		},
+		{
+			name:       "preset's own fade time",
+			preset:     Preset{Fade: &half},
+			globalFade: 2,
+			want:       500 * time.Millisecond,
+		},
+		{
+			name:       "preset snaps whatever the global fade time",
+			preset:     Preset{Fade: &snap},
+			globalFade: 2,
+			want:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FadeTime(tt.preset, tt.globalFade); got != tt.want {
+				t.Errorf("FadeTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFadeLabel(t *testing.T) {
+	tests := []struct {
+		fade float64
+		want string
+	}{
+		{fade: 0, want: "Snap"},
+		{fade: 0.5, want: "Fade 0.5s"},
+		{fade: 10, want: "Fade 10s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FadeLabel(tt.fade); got != tt.want {
+				t.Errorf("FadeLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratePresets(t *testing.T) {
+
+	presets := map[string]Preset{
+		"2,5": {State: true, Label: "Chase", ButtonColor: "Blue"},
+		"3,6": {State: false},
+		"7":   {Page: 1, X: 1, Y: 4, State: true, Label: "New"},
+	}
+
+	want := map[string]Preset{
+		"2.5": {Page: 0, X: 2, Y: 5, State: true, Label: "Chase", ButtonColor: "Blue"},
+		"7":   {Page: 1, X: 1, Y: 4, State: true, Label: "New"},
+	}
+
+	if got := migratePresets(presets); !reflect.DeepEqual(got, want) {
+		t.Errorf("migratePresets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindPreset(t *testing.T) {
+
+	presets := map[string]Preset{
+		"1": {Page: 0, X: 2, Y: 5, State: true},
+		"2": {Page: 1, X: 2, Y: 5, State: true},
+		"3": {Page: FULL_PAGE, X: 0, Y: 0, State: true},
+	}
+
+	tests := []struct {
+		name   string
+		page   int
+		X      int
+		Y      int
+		want   string
+		wantOK bool
+	}{
+		{name: "first page", page: 0, X: 2, Y: 5, want: "1", wantOK: true},
+		{name: "second page", page: 1, X: 2, Y: 5, want: "2", wantOK: true},
+		{name: "full page", page: FULL_PAGE, X: 0, Y: 0, want: "3", wantOK: true},
+		{name: "empty pad", page: 0, X: 3, Y: 5, want: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindPreset(presets, tt.page, tt.X, tt.Y)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FindPreset() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewPresetID(t *testing.T) {
+	presets := map[string]Preset{
+		"2.5": {State: true},
+		"4":   {State: true},
+	}
+	if got := NewPresetID(presets); got != "5" {
+		t.Errorf("NewPresetID() = %q, want 5", got)
+	}
+	if got := NewPresetID(map[string]Preset{}); got != "1" {
+		t.Errorf("NewPresetID() = %q, want 1", got)
+	}
+}
+
+func TestMovePreset(t *testing.T) {
+
+	presets := map[string]Preset{
+		"1": {Page: 0, X: 0, Y: 4, State: true},
+		"2": {Page: 1, X: 0, Y: 4, State: true},
+	}
+
+	// The first pad on page two is taken so the preset goes on the next one.
+	if err := MovePreset(presets, "1", 1); err != nil {
+		t.Fatalf("MovePreset() error = %v", err)
+	}
+	if got := presets["1"]; got.Page != 1 || got.X != 1 || got.Y != 4 {
+		t.Errorf("MovePreset() moved to page %d %d,%d, want page 1 1,4", got.Page, got.X, got.Y)
+	}
+
+	if err := MovePreset(presets, "1", FULL_PAGE); err != nil {
+		t.Fatalf("MovePreset() error = %v", err)
+	}
+	if got := presets["1"]; got.Page != FULL_PAGE || got.X != 0 || got.Y != 0 {
+		t.Errorf("MovePreset() moved to page %d %d,%d, want full page 0,0", got.Page, got.X, got.Y)
+	}
+
+	if err := MovePreset(presets, "9", 0); err == nil {
+		t.Errorf("MovePreset() of a missing preset want error")
+	}
+}
+
+func TestIsPresetPad(t *testing.T) {
+	tests := []struct {
+		name string
+		page int
+		X    int
+		Y    int
+		want bool
+	}{
+		{name: "preset row", page: 0, X: 0, Y: 4, want: true},
+		{name: "sequence row", page: 0, X: 0, Y: 0, want: false},
+		{name: "bottom row", page: 2, X: 7, Y: 7, want: false},
+		{name: "full page sequence row", page: FULL_PAGE, X: 0, Y: 0, want: true},
+		{name: "full page bottom row", page: FULL_PAGE, X: 7, Y: 7, want: true},
+		{name: "side buttons", page: FULL_PAGE, X: 8, Y: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPresetPad(tt.page, tt.X, tt.Y); got != tt.want {
+				t.Errorf("IsPresetPad() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultLabel(t *testing.T) {
+	tests := []struct {
+		page int
+		X    int
+		Y    int
+		want string
+	}{
+		{page: 0, X: 0, Y: 4, want: "Preset1"},
+		{page: 0, X: 7, Y: 6, want: "Preset24"},
+		{page: 1, X: 0, Y: 4, want: "Preset25"},
+		{page: FULL_PAGE, X: 7, Y: 7, want: "Full64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := DefaultLabel(tt.page, tt.X, tt.Y); got != tt.want {
+				t.Errorf("DefaultLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadPresets(t *testing.T) {
+	defer project.SetCurrent(project.Current())
+	project.SetCurrent(t.TempDir())
+
+	// A project without presets yet.
+	got, err := LoadPresets()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("LoadPresets() = %v, %v, want no presets", got, err)
+	}
+
+	want := map[string]Preset{
+		"1": {State: true, Label: "Intro", Page: 0, X: 0, Y: 4},
+	}
+	err = SavePresets(want)
+	if err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+	got, err = LoadPresets()
+	if err != nil {
+		t.Fatalf("LoadPresets() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadPresets() = %v, want %v", got, want)
+	}
+
+	// A corrupt file is reported rather than stopping the program.
+	os.WriteFile(project.File(project.PRESETS_FILE), []byte("{"), 0644)
+	if _, err := LoadPresets(); err == nil {
+		t.Errorf("LoadPresets() of a corrupt file want error")
+	}
+
+	// The corrupt file isn't overwritten until the user says it can be.
+	err = SavePresets(map[string]Preset{})
+	if err == nil {
+		t.Errorf("SavePresets() after a failed load want error")
+	}
+	data, _ := os.ReadFile(project.File(project.PRESETS_FILE))
+	if string(data) != "{" {
+		t.Errorf("SavePresets() after a failed load wrote %q", data)
+	}
+	AllowOverwrite()
+	err = SavePresets(map[string]Preset{})
+	if err != nil {
+		t.Errorf("SavePresets() after AllowOverwrite() error = %v", err)
+	}
+}
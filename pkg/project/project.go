@@ -0,0 +1,408 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights project, a directory under projects/ holding
+// everything saved for a show: fixtures, groups, sequences, presets and
+// the sequence configs saved with each preset.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const debug = false
+
+const PROJECTS_DIR = "projects"
+
+// The files in a project directory.
+const FIXTURES_FILE = "fixtures.yaml"
+const GROUPS_FILE = "groups.yaml"
+const SEQUENCES_FILE = "sequences.yaml"
+const PRESETS_FILE = "presets.json"
+
+// Remembers the project last opened or saved, dmxlights starts with it.
+const LAST_PROJECT_FILE = "last"
+
+// Number of older copies kept of a file written with WriteFile.
+const NUMBER_BACKUPS = 3
+
+// Each preset saves a config<ID>.json and may save a programmer<ID>.json and a submasters<ID>.json.
+var presetFilePatterns = []string{"config*.json", "programmer*.json", "submasters*.json"}
+
+// The project being used, the sequences read and write preset configs here.
+var current = struct {
+	mutex sync.Mutex
+	dir   string
+}{dir: "."}
+
+// SetCurrent makes the project in dir the one being used.
+func SetCurrent(dir string) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.dir = dir
+}
+
+// Current returns the directory of the project being used.
+func Current() string {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.dir
+}
+
+// File returns the path of a file in the project being used.
+func File(filename string) string {
+	return filepath.Join(Current(), filename)
+}
+
+// Dir returns the directory of a named project.
+func Dir(name string) string {
+	return filepath.Join(PROJECTS_DIR, name)
+}
+
+// Name returns a project's name from its directory.
+func Name(dir string) string {
+	return filepath.Base(dir)
+}
+
+// Last returns the name of the project last opened or saved, or defaultName if there isn't one.
+func Last(defaultName string) string {
+	data, err := os.ReadFile(filepath.Join(PROJECTS_DIR, LAST_PROJECT_FILE))
+	if err != nil {
+		return defaultName
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" || !IsProject(Dir(name)) {
+		return defaultName
+	}
+	return name
+}
+
+// SetLast remembers the project in dir as the one to start with next time.
+func SetLast(dir string) error {
+	return os.WriteFile(filepath.Join(PROJECTS_DIR, LAST_PROJECT_FILE), []byte(Name(dir)+"\n"), 0644)
+}
+
+// IsProject returns true if dir holds a project.
+func IsProject(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, FIXTURES_FILE))
+	return err == nil && !info.IsDir()
+}
+
+// Find returns the directory of a named project. Projects saved before they were directories,
+// as projects/<name>.yaml, are imported first along with the groups, sequences and presets in
+// the working directory.
+func Find(name string) (string, error) {
+
+	dir := Dir(name)
+	if IsProject(dir) {
+		return dir, nil
+	}
+
+	legacy := dir + ".yaml"
+	if _, err := os.Stat(legacy); err != nil {
+		return "", fmt.Errorf("project %s not found in %s", name, PROJECTS_DIR)
+	}
+
+	fmt.Printf("Importing project %s into %s\n", legacy, dir)
+	err := Import(legacy, ".", true)
+	if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Import makes a project directory from a fixtures file saved before projects were directories.
+// The project is named after the fixtures file and put alongside it, the groups and sequences
+// are copied from fromDir, as are the presets if withPresets is set.
+func Import(fixturesFile string, fromDir string, withPresets bool) error {
+
+	dir := strings.TrimSuffix(fixturesFile, filepath.Ext(fixturesFile))
+
+	if debug {
+		fmt.Printf("Import %s from %s into %s presets %t\n", fixturesFile, fromDir, dir, withPresets)
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("importing project: %w", err)
+	}
+
+	err = copyFile(fixturesFile, filepath.Join(dir, FIXTURES_FILE))
+	if err != nil {
+		return fmt.Errorf("importing project: %w", err)
+	}
+
+	for _, filename := range []string{GROUPS_FILE, SEQUENCES_FILE} {
+		err = copyFile(filepath.Join(fromDir, filename), filepath.Join(dir, filename))
+		if err != nil {
+			return fmt.Errorf("importing project: %w", err)
+		}
+	}
+
+	if withPresets {
+		err = copyPresets(fromDir, dir)
+		if err != nil {
+			return fmt.Errorf("importing project: %w", err)
+		}
+	}
+	return nil
+}
+
+// Copy copies a project's groups, sequences, settings and presets into another project directory.
+// The fixtures aren't copied, they are saved from the fixtures being edited. Any presets
+// already in the other project are removed so none are left over from before.
+func Copy(fromDir string, toDir string) error {
+
+	if debug {
+		fmt.Printf("Copy project %s to %s\n", fromDir, toDir)
+	}
+
+	err := os.MkdirAll(toDir, 0755)
+	if err != nil {
+		return fmt.Errorf("copying project: %w", err)
+	}
+
+	for _, filename := range []string{GROUPS_FILE, SEQUENCES_FILE} {
+		err = copyFile(filepath.Join(fromDir, filename), filepath.Join(toDir, filename))
+		if err != nil {
+			return fmt.Errorf("copying project: %w", err)
+		}
+	}
+
+	// Projects saved before there were settings don't have any.
+	os.Remove(filepath.Join(toDir, SETTINGS_FILE))
+	if _, err := os.Stat(filepath.Join(fromDir, SETTINGS_FILE)); err == nil {
+		err = copyFile(filepath.Join(fromDir, SETTINGS_FILE), filepath.Join(toDir, SETTINGS_FILE))
+		if err != nil {
+			return fmt.Errorf("copying project: %w", err)
+		}
+	}
+
+	err = removePresets(toDir)
+	if err != nil {
+		return fmt.Errorf("copying project: %w", err)
+	}
+
+	err = copyPresets(fromDir, toDir)
+	if err != nil {
+		return fmt.Errorf("copying project: %w", err)
+	}
+	return nil
+}
+
+// presetFiles returns the presets and the configs saved with them found in dir.
+func presetFiles(dir string) ([]string, error) {
+
+	filenames := []string{}
+	if _, err := os.Stat(filepath.Join(dir, PRESETS_FILE)); err == nil {
+		filenames = append(filenames, PRESETS_FILE)
+	}
+	for _, pattern := range presetFilePatterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			filenames = append(filenames, filepath.Base(match))
+		}
+	}
+	return filenames, nil
+}
+
+// removePresets removes the presets and the configs saved with them, the backups are kept.
+func removePresets(dir string) error {
+
+	filenames, err := presetFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		err := os.Remove(filepath.Join(dir, filename))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyPresets copies the presets and the configs saved with them, if there are any.
+func copyPresets(fromDir string, toDir string) error {
+
+	filenames, err := presetFiles(fromDir)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range filenames {
+		err := copyFile(filepath.Join(fromDir, filename), filepath.Join(toDir, filename))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(from string, to string) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0644)
+}
+
+// WriteFile writes a file so that a crash part way through never leaves it half written.
+// The data goes to a temporary file which is renamed over the old file. The old file is
+// kept as the newest of a rotating set of backups, see BackupName.
+func WriteFile(filename string, data []byte) error {
+	return WriteFiles(map[string][]byte{filename: data}, nil)
+}
+
+// rename is swapped out by the tests to make a rename fail.
+var rename = os.Rename
+
+// WriteFiles writes a set of files that belong together and removes any that are no longer needed,
+// so either all of them change or none do. Every file is written to a temporary file and backed up
+// first, see WriteFile. If a file can't be removed or renamed into place, the files already changed
+// are put back from their backups.
+func WriteFiles(files map[string][]byte, remove []string) error {
+
+	temps := map[string]string{}
+	removeTemps := func() {
+		for _, tempName := range temps {
+			os.Remove(tempName)
+		}
+	}
+
+	for filename, data := range files {
+		tempName, err := writeTemp(filename, data)
+		if err != nil {
+			removeTemps()
+			return err
+		}
+		temps[filename] = tempName
+	}
+
+	// Back up every file that's about to change, nothing has changed yet if this fails.
+	existed := map[string]bool{}
+	backup := func(filename string) error {
+		_, err := os.Stat(filename)
+		existed[filename] = err == nil
+		return backupFile(filename)
+	}
+	for filename := range temps {
+		err := backup(filename)
+		if err != nil {
+			removeTemps()
+			return err
+		}
+	}
+	for _, filename := range remove {
+		err := backup(filename)
+		if err != nil {
+			removeTemps()
+			return err
+		}
+	}
+
+	// Put back the files already changed from their backups.
+	changed := []string{}
+	restore := func() {
+		for _, filename := range changed {
+			if existed[filename] {
+				copyFile(BackupName(filename, 1), filename)
+			} else {
+				os.Remove(filename)
+			}
+		}
+		removeTemps()
+	}
+
+	for _, filename := range remove {
+		err := os.Remove(filename)
+		if err != nil && !os.IsNotExist(err) {
+			restore()
+			return err
+		}
+		changed = append(changed, filename)
+	}
+
+	for filename, tempName := range temps {
+		err := rename(tempName, filename)
+		if err != nil {
+			restore()
+			return err
+		}
+		changed = append(changed, filename)
+		delete(temps, filename)
+	}
+	return nil
+}
+
+// writeTemp writes data to a temporary file alongside filename, ready to be renamed over it.
+func writeTemp(filename string, data []byte) (string, error) {
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return "", err
+	}
+	tempName := temp.Name()
+
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Sync()
+	}
+	closeErr := temp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// Temporary files are only readable by us.
+		err = os.Chmod(tempName, 0644)
+	}
+	if err != nil {
+		os.Remove(tempName)
+		return "", err
+	}
+	return tempName, nil
+}
+
+// BackupName names a backup of a file, backup 1 is the newest.
+func BackupName(filename string, backup int) string {
+	return fmt.Sprintf("%s.bak%d", filename, backup)
+}
+
+// backupFile keeps a copy of a file before it's replaced, dropping the oldest backup.
+func backupFile(filename string) error {
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for backup := NUMBER_BACKUPS; backup > 1; backup-- {
+		err := os.Rename(BackupName(filename, backup-1), BackupName(filename, backup))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.WriteFile(BackupName(filename, 1), data, 0644)
+}
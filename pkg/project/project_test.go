@@ -0,0 +1,339 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// This is the dmxlights project tests.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// inTempDir runs the test from an empty working directory.
+func inTempDir(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(pwd)
+	})
+}
+
+func writeFiles(t *testing.T, dir string, filenames ...string) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		err := os.WriteFile(filepath.Join(dir, filename), []byte(filename), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkFiles(t *testing.T, dir string, want map[string]string) {
+	for filename, contents := range want {
+		data, err := os.ReadFile(filepath.Join(dir, filename))
+		if err != nil {
+			t.Errorf("%s missing from %s", filename, dir)
+			continue
+		}
+		if string(data) != contents {
+			t.Errorf("%s holds %q, want %q", filename, string(data), contents)
+		}
+	}
+}
+
+func TestFindImportsOldProject(t *testing.T) {
+	inTempDir(t)
+
+	// Before projects were directories.
+	writeFiles(t, ".", GROUPS_FILE, SEQUENCES_FILE, PRESETS_FILE, "config2.5.json", "programmer2.5.json")
+	writeFiles(t, PROJECTS_DIR, "Default.yaml")
+
+	dir, err := Find("Default")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if dir != filepath.Join(PROJECTS_DIR, "Default") {
+		t.Errorf("Find() = %s, want projects/Default", dir)
+	}
+
+	checkFiles(t, dir, map[string]string{
+		FIXTURES_FILE:        "Default.yaml",
+		GROUPS_FILE:          GROUPS_FILE,
+		SEQUENCES_FILE:       SEQUENCES_FILE,
+		PRESETS_FILE:         PRESETS_FILE,
+		"config2.5.json":     "config2.5.json",
+		"programmer2.5.json": "programmer2.5.json",
+	})
+
+	// Found as a project the second time, without importing it again.
+	writeFiles(t, PROJECTS_DIR, "Default.yaml")
+	os.WriteFile(filepath.Join(dir, FIXTURES_FILE), []byte("edited"), 0644)
+	if _, err := Find("Default"); err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	checkFiles(t, dir, map[string]string{FIXTURES_FILE: "edited"})
+
+	if _, err := Find("Missing"); err == nil {
+		t.Errorf("Find() of a missing project want error")
+	}
+}
+
+func TestImportWithoutPresets(t *testing.T) {
+	inTempDir(t)
+
+	writeFiles(t, "current", GROUPS_FILE, SEQUENCES_FILE, PRESETS_FILE, "config1.json")
+	writeFiles(t, PROJECTS_DIR, "Demo.yaml")
+
+	err := Import(filepath.Join(PROJECTS_DIR, "Demo.yaml"), "current", false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	dir := Dir("Demo")
+	if !IsProject(dir) {
+		t.Fatalf("Import() didn't make a project in %s", dir)
+	}
+	checkFiles(t, dir, map[string]string{
+		FIXTURES_FILE:  "Demo.yaml",
+		GROUPS_FILE:    GROUPS_FILE,
+		SEQUENCES_FILE: SEQUENCES_FILE,
+	})
+	for _, filename := range []string{PRESETS_FILE, "config1.json"} {
+		if _, err := os.Stat(filepath.Join(dir, filename)); err == nil {
+			t.Errorf("Import() without presets copied %s", filename)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	inTempDir(t)
+
+	from := Dir("Show")
+	writeFiles(t, from, FIXTURES_FILE, GROUPS_FILE, SEQUENCES_FILE, SETTINGS_FILE, PRESETS_FILE, "config1.json", "programmer1.json", "submasters1.json", "notes.txt")
+
+	// Saving over another project leaves none of its presets behind.
+	to := Dir("Show2")
+	writeFiles(t, to, FIXTURES_FILE, "config9.json", "submasters9.json")
+	os.WriteFile(filepath.Join(to, PRESETS_FILE), []byte("old presets"), 0644)
+
+	err := Copy(from, to)
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	checkFiles(t, to, map[string]string{
+		GROUPS_FILE:        GROUPS_FILE,
+		SEQUENCES_FILE:     SEQUENCES_FILE,
+		SETTINGS_FILE:      SETTINGS_FILE,
+		PRESETS_FILE:       PRESETS_FILE,
+		"config1.json":     "config1.json",
+		"programmer1.json": "programmer1.json",
+		"submasters1.json": "submasters1.json",
+	})
+
+	for _, filename := range []string{"config9.json", "submasters9.json"} {
+		if _, err := os.Stat(filepath.Join(to, filename)); err == nil {
+			t.Errorf("Copy() left %s", filename)
+		}
+	}
+
+	// The fixtures are saved from the ones being edited.
+	checkFiles(t, to, map[string]string{FIXTURES_FILE: FIXTURES_FILE})
+}
+
+func TestLast(t *testing.T) {
+	inTempDir(t)
+
+	if got := Last("Default"); got != "Default" {
+		t.Errorf("Last() with nothing saved = %s, want Default", got)
+	}
+
+	writeFiles(t, Dir("Show"), FIXTURES_FILE)
+	err := SetLast(Dir("Show"))
+	if err != nil {
+		t.Fatalf("SetLast() error = %v", err)
+	}
+	if got := Last("Default"); got != "Show" {
+		t.Errorf("Last() = %s, want Show", got)
+	}
+
+	// A project that has gone isn't started with.
+	os.RemoveAll(Dir("Show"))
+	if got := Last("Default"); got != "Default" {
+		t.Errorf("Last() of a removed project = %s, want Default", got)
+	}
+}
+
+func TestFile(t *testing.T) {
+	defer SetCurrent(Current())
+
+	SetCurrent(Dir("Show"))
+	if got := File(PRESETS_FILE); got != filepath.Join(PROJECTS_DIR, "Show", PRESETS_FILE) {
+		t.Errorf("File() = %s, want projects/Show/presets.json", got)
+	}
+	if got := Name(Current()); got != "Show" {
+		t.Errorf("Name() = %s, want Show", got)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	inTempDir(t)
+
+	filename := PRESETS_FILE
+	for _, contents := range []string{"one", "two", "three", "four", "five"} {
+		err := WriteFile(filename, []byte(contents))
+		if err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	// The newest file and the three before it.
+	checkFiles(t, ".", map[string]string{
+		filename:                "five",
+		BackupName(filename, 1): "four",
+		BackupName(filename, 2): "three",
+		BackupName(filename, 3): "two",
+	})
+	if _, err := os.Stat(BackupName(filename, 4)); err == nil {
+		t.Errorf("WriteFile() kept more than %d backups", NUMBER_BACKUPS)
+	}
+
+	// No temporary files left behind.
+	matches, _ := filepath.Glob(filename + ".tmp*")
+	if len(matches) != 0 {
+		t.Errorf("WriteFile() left %v", matches)
+	}
+
+	// A failed write leaves the old file alone.
+	if err := WriteFile(filepath.Join("missing", filename), []byte("six")); err == nil {
+		t.Errorf("WriteFile() into a missing directory want error")
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	inTempDir(t)
+
+	writeFiles(t, ".", "config1.json", "programmer1.json")
+	os.WriteFile("submasters1.json", []byte("old"), 0644)
+
+	// One file can't be written so none of them change.
+	err := WriteFiles(map[string][]byte{
+		"config1.json":                        []byte("new"),
+		"submasters1.json":                    []byte("new"),
+		filepath.Join("missing", "file.json"): []byte("new"),
+	}, []string{"programmer1.json"})
+	if err == nil {
+		t.Fatalf("WriteFiles() into a missing directory want error")
+	}
+	checkFiles(t, ".", map[string]string{
+		"config1.json":     "config1.json",
+		"programmer1.json": "programmer1.json",
+		"submasters1.json": "old",
+	})
+	matches, _ := filepath.Glob("*.tmp*")
+	if len(matches) != 0 {
+		t.Errorf("WriteFiles() left %v", matches)
+	}
+
+	// All written together, and the file no longer needed removed.
+	err = WriteFiles(map[string][]byte{
+		"config1.json":     []byte("new"),
+		"submasters1.json": []byte("new"),
+	}, []string{"programmer1.json"})
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	checkFiles(t, ".", map[string]string{
+		"config1.json":                    "new",
+		"submasters1.json":                "new",
+		BackupName("submasters1.json", 1): "old",
+	})
+	if _, err := os.Stat("programmer1.json"); err == nil {
+		t.Errorf("WriteFiles() didn't remove programmer1.json")
+	}
+}
+
+func TestWriteFilesRollBack(t *testing.T) {
+	inTempDir(t)
+
+	writeFiles(t, ".", "config1.json", "programmer1.json")
+	defer func() { rename = os.Rename }()
+
+	// The second file can't be renamed into place, so the first is put back and the removed file restored.
+	renames := 0
+	rename = func(from string, to string) error {
+		renames++
+		if renames == 2 {
+			return errors.New("rename failed")
+		}
+		return os.Rename(from, to)
+	}
+	err := WriteFiles(map[string][]byte{
+		"config1.json":     []byte("new"),
+		"submasters1.json": []byte("new"),
+	}, []string{"programmer1.json"})
+	if err == nil {
+		t.Fatalf("WriteFiles() with a failed rename want error")
+	}
+	checkFiles(t, ".", map[string]string{
+		"config1.json":     "config1.json",
+		"programmer1.json": "programmer1.json",
+	})
+	if _, err := os.Stat("submasters1.json"); err == nil {
+		t.Errorf("WriteFiles() left submasters1.json")
+	}
+	matches, _ := filepath.Glob("*.tmp*")
+	if len(matches) != 0 {
+		t.Errorf("WriteFiles() left %v", matches)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	defer SetCurrent(Current())
+	SetCurrent(t.TempDir())
+
+	// No settings saved yet.
+	settings, err := LoadSettings()
+	if err != nil || settings != (Settings{}) {
+		t.Errorf("LoadSettings() with no file = %+v %v, want defaults", settings, err)
+	}
+
+	want := Settings{BlackoutFadeTime: 2 * time.Second, BlackoutReleaseTime: 500 * time.Millisecond}
+	err = SaveSettings(want)
+	if err != nil {
+		t.Fatalf("SaveSettings() error = %v", err)
+	}
+	settings, err = LoadSettings()
+	if err != nil || settings != want {
+		t.Errorf("LoadSettings() = %+v %v, want %+v", settings, err, want)
+	}
+
+	os.WriteFile(File(SETTINGS_FILE), []byte("blackoutfadetime: [not a time"), 0644)
+	if _, err := LoadSettings(); err == nil {
+		t.Errorf("LoadSettings() of a broken file want error")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2022,2023,2024 dhowlett99.
+// These are the dmxlights project settings, the choices made in the
+// settings panel which are kept with the show.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package project
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+const SETTINGS_FILE = "settings.yaml"
+
+// Settings are kept in the project, a project without a settings file uses the defaults.
+type Settings struct {
+	BlackoutFadeTime    time.Duration `yaml:"blackoutfadetime"`    // Zero is a dead blackout.
+	BlackoutReleaseTime time.Duration `yaml:"blackoutreleasetime"` // Zero comes straight back.
+}
+
+// LoadSettings loads the settings from the project being used.
+func LoadSettings() (Settings, error) {
+
+	settings := Settings{}
+	filename := File(SETTINGS_FILE)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("reading settings: %v from file:%s", err, filename)
+	}
+
+	err = yaml.Unmarshal(data, &settings)
+	if err != nil {
+		return Settings{}, fmt.Errorf("reading settings: %v from file:%s", err, filename)
+	}
+	return settings, nil
+}
+
+// SaveSettings saves the settings in the project being used.
+func SaveSettings(settings Settings) error {
+
+	filename := File(SETTINGS_FILE)
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("marshalling settings: %v", err)
+	}
+
+	err = WriteFile(filename, data)
+	if err != nil {
+		return fmt.Errorf("writing settings: %v to file:%s", err, filename)
+	}
+	return nil
+}
@@ -22,11 +22,14 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dhowlett99/dmxlights/pkg/clock"
 	"github.com/dhowlett99/dmxlights/pkg/commands"
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/dhowlett99/dmxlights/pkg/fixture"
@@ -40,6 +43,9 @@ import (
 
 const debug = false
 
+// How often a frozen sequence checks for commands.
+const FREEZE_POLL = 10 * time.Millisecond
+
 type SequencesConfig struct {
 	Sequences []SequenceConfig `yaml:"sequences"`
 }
@@ -50,6 +56,8 @@ type SequenceConfig struct {
 	Description string `yaml:"description"`
 	Type        string `yaml:"type"`
 	Group       int    `yaml:"group"`
+	PatternFit  string `yaml:"patternfit,omitempty"`
+	Priority    int    `yaml:"priority,omitempty"`
 }
 
 // LoadSequences loads sequence configuration information.
@@ -59,16 +67,17 @@ type SequenceConfig struct {
 //	description: free text describing the sequence.
 //	group: assignes to one of the top 4 rows of the launchpad. 1-4
 //	type:  rgb, scanner or switch
-func LoadSequences() (sequences *SequencesConfig, err error) {
-	filename := "sequences.yaml"
+//	patternfit: scale or tile, how rgb patterns cover more than eight fixtures. Defaults to scale.
+//	priority: merge priority where a switch shares this sequence's fixtures, highest wins. Defaults to 0.
+func LoadSequences(filename string) (sequences *SequencesConfig, err error) {
 
 	_, err = os.OpenFile(filename, os.O_RDONLY, 0644)
 	if err != nil {
-		return nil, errors.New("error: loading sequences.yaml file: " + err.Error())
+		return nil, errors.New("error: loading " + filename + " file: " + err.Error())
 	}
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, errors.New("error: reading sequences.yaml file: " + err.Error())
+		return nil, errors.New("error: reading " + filename + " file: " + err.Error())
 	}
 
 	sequences = &SequencesConfig{}
@@ -79,6 +88,45 @@ func LoadSequences() (sequences *SequencesConfig, err error) {
 	return sequences, nil
 }
 
+// CheckSequencesFitLaunchpad makes sure every sequence can be shown on the launchpad.
+// The switch sequence has a row of its own and the shutter chaser shares the scanner row.
+// The other sequences are shown a page at a time, so there can be any number of them.
+func CheckSequencesFitLaunchpad(sequencesConfig *SequencesConfig) error {
+
+	var haveScanner bool
+	var haveChaser bool
+	var switches int
+	var rowsUsed int
+
+	for _, sequence := range sequencesConfig.Sequences {
+		if sequence.Label == "chaser" {
+			haveChaser = true
+			continue
+		}
+		if sequence.Type == "scanner" {
+			haveScanner = true
+		}
+		if sequence.Label == "switch" {
+			switches++
+		}
+		rowsUsed++
+	}
+
+	if rowsUsed == 0 {
+		return fmt.Errorf("error: no sequences found in sequences.yaml")
+	}
+
+	if switches > 1 {
+		return fmt.Errorf("error: there are %d switch sequences in sequences.yaml, the launchpad only has a row for one", switches)
+	}
+
+	if haveChaser && !haveScanner {
+		return fmt.Errorf("error: the shutter chaser is shown on the scanner row but there is no scanner sequence in sequences.yaml")
+	}
+
+	return nil
+}
+
 // Before a sequence can run it needs to be created.
 // Assigns default values for all types of sequence.
 func CreateSequence(
@@ -136,6 +184,11 @@ func CreateSequence(
 		numberFixtures = commands.GetNumberOfFixtures(mySequenceNumber, fixturesConfig)
 	}
 
+	// Every fixture needs a static color, repeat the first row of defaults for large sequences.
+	for len(staticColorsButtons) < numberFixtures {
+		staticColorsButtons = append(staticColorsButtons, staticColorsButtons[len(staticColorsButtons)%common.FIXTURES_PER_PAGE])
+	}
+
 	// Enable all the defined fixtures.
 	for x := 0; x < numberFixtures; x++ {
 		newScanner := common.FixtureState{}
@@ -222,35 +275,31 @@ func PlaySequence(sequence common.Sequence,
 	var steps []common.Step
 	RGBPositions := make(map[int]common.Position)
 	scannerPositions := make(map[int]map[int]common.Position, sequence.NumberFixtures)
+	// The last step played, so random step orders don't repeat a step across cycles.
+	lastPlayedStep := -1
+	// Number of steps played this cycle, used to find every other step when swinging.
+	var numberPlayedSteps int
 
 	// Create channels used for stepping the fixture threads for this sequnece.
+	// There is always at least a launchpad row of fixture threads, switch sequences need one for every switch.
+	numberFixtureThreads := sequence.NumberFixtures
+	if numberFixtureThreads < common.FIXTURES_PER_PAGE {
+		numberFixtureThreads = common.FIXTURES_PER_PAGE
+	}
+	if len(sequence.Switches) > numberFixtureThreads {
+		numberFixtureThreads = len(sequence.Switches)
+	}
 	fixtureStepChannels := []chan common.FixtureCommand{}
-	fixtureStepChannel0 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel0)
-	fixtureStepChannel1 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel1)
-	fixtureStepChannel2 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel2)
-	fixtureStepChannel3 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel3)
-	fixtureStepChannel4 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel4)
-	fixtureStepChannel5 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel5)
-	fixtureStepChannel6 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel6)
-	fixtureStepChannel7 := make(chan common.FixtureCommand)
-	fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel7)
-
-	// Create eight fixture threads for this sequence.
-	go fixture.FixtureReceiver(0, fixtureStepChannels[0], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(1, fixtureStepChannels[1], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(2, fixtureStepChannels[2], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(3, fixtureStepChannels[3], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(4, fixtureStepChannels[4], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(5, fixtureStepChannels[5], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(6, fixtureStepChannels[6], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
-	go fixture.FixtureReceiver(7, fixtureStepChannels[7], eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
+	for fixtureNumber := 0; fixtureNumber < numberFixtureThreads; fixtureNumber++ {
+		fixtureStepChannel := make(chan common.FixtureCommand)
+		fixtureStepChannels = append(fixtureStepChannels, fixtureStepChannel)
+
+		// Create a fixture thread for this fixture.
+		go fixture.FixtureReceiver(fixtureNumber, fixtureStepChannel, eventsForLauchpad, guiButtons, switchChannels, channels.SoundTriggers, soundConfig, dmxController, fixturesConfig, dmxInterfacePresent)
+	}
+
+	// Switches stepping through their states on their own.
+	advancers := make(map[int]*switchAdvancer)
 
 	// So this is the outer loop where sequence waits for commands and processes them if we're not playing a sequence.
 	// i.e the sequence is in STOP mode and this is the way we change the RUN flag to START a sequence again.
@@ -265,6 +314,11 @@ func PlaySequence(sequence common.Sequence,
 		// Check for any waiting commands.
 		sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, 10*time.Millisecond, sequence, channels, fixturesConfig)
 
+		// Move on any switches that advance on their own.
+		if sequence.Type == "switch" && !sequence.PlaySwitchOnce {
+			sequence = advanceSwitches(sequence, advancers, soundConfig)
+		}
+
 		// Soft fade downs should be disabled for blackout.
 		if sequence.Blackout {
 			command := common.FixtureCommand{
@@ -286,6 +340,7 @@ func PlaySequence(sequence common.Sequence,
 				Type:           sequence.Type,
 				Label:          sequence.Label,
 				SequenceNumber: sequence.Number,
+				FixturePage:    sequence.FixturePage,
 				Clear:          sequence.Clear,
 			}
 
@@ -313,10 +368,10 @@ func PlaySequence(sequence common.Sequence,
 				state := switchData.States[switchData.CurrentPosition]
 
 				color, _ := common.GetRGBColorByName(state.ButtonColor)
-				common.LightLamp(common.Button{X: switchNumber, Y: mySequenceNumber}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+				common.LightLamp(common.Button{X: switchNumber, Y: common.SequenceRow(mySequenceNumber)}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 
 				// Label the switch.
-				common.LabelButton(switchNumber, mySequenceNumber, switchData.Label+"\n"+state.Label, guiButtons)
+				common.LabelButton(switchNumber, common.SequenceRow(mySequenceNumber), switchData.Label+"\n"+state.Label, guiButtons)
 
 				// Now send a message to the fixture to play all the values for this state.
 				command := common.FixtureCommand{
@@ -325,6 +380,7 @@ func PlaySequence(sequence common.Sequence,
 					Type:               sequence.Type,
 					Label:              sequence.Label,
 					SequenceNumber:     sequence.Number,
+					FixturePage:        sequence.FixturePage,
 					SwitchData:         switchData,
 					State:              state,
 					CurrentSwitchState: switchData.CurrentPosition,
@@ -352,10 +408,10 @@ func PlaySequence(sequence common.Sequence,
 
 			// Use the button color for this state to light the correct color on the launchpad.
 			color, _ := common.GetRGBColorByName(state.ButtonColor)
-			common.LightLamp(common.Button{X: sequence.CurrentSwitch, Y: mySequenceNumber}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
+			common.LightLamp(common.Button{X: sequence.CurrentSwitch, Y: common.SequenceRow(mySequenceNumber)}, color, common.MAX_DMX_BRIGHTNESS, eventsForLauchpad, guiButtons)
 
 			// Label the switch.
-			common.LabelButton(sequence.CurrentSwitch, mySequenceNumber, swiTch.Label+"\n"+state.Label, guiButtons)
+			common.LabelButton(sequence.CurrentSwitch, common.SequenceRow(mySequenceNumber), swiTch.Label+"\n"+state.Label, guiButtons)
 
 			// Now send a message to the fixture to play all the values for this state.
 			command := common.FixtureCommand{
@@ -364,6 +420,7 @@ func PlaySequence(sequence common.Sequence,
 				Type:               sequence.Type,
 				Label:              sequence.Label,
 				SequenceNumber:     sequence.Number,
+				FixturePage:        sequence.FixturePage,
 				SwitchData:         sequence.Switches[sequence.CurrentSwitch],
 				State:              sequence.Switches[sequence.CurrentSwitch].States[swiTch.CurrentPosition],
 				CurrentSwitchState: swiTch.CurrentPosition,
@@ -373,6 +430,14 @@ func PlaySequence(sequence common.Sequence,
 
 			// Send a message to the fixture to operate the switch.
 			fixtureStepChannels[sequence.CurrentSwitch] <- command
+
+			// Go round again for any switches that moved with this one.
+			if len(sequence.SwitchesToPlay) > 0 {
+				sequence.CurrentSwitch = sequence.SwitchesToPlay[0]
+				sequence.SwitchesToPlay = sequence.SwitchesToPlay[1:]
+				continue
+			}
+
 			sequence.PlaySwitchOnce = false
 			sequence.PlaySingleSwitch = false
 			sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, 1*time.Microsecond, sequence, channels, fixturesConfig)
@@ -391,6 +456,7 @@ func PlaySequence(sequence common.Sequence,
 				Type:           sequence.Type,
 				Label:          sequence.Label,
 				SequenceNumber: sequence.Number,
+				FixturePage:    sequence.FixturePage,
 				StartFlood:     sequence.StartFlood,
 				StrobeSpeed:    sequence.StrobeSpeed,
 				Strobe:         sequence.Strobe,
@@ -414,6 +480,7 @@ func PlaySequence(sequence common.Sequence,
 				Type:           sequence.Type,
 				Label:          sequence.Label,
 				SequenceNumber: sequence.Number,
+				FixturePage:    sequence.FixturePage,
 				StartFlood:     sequence.StartFlood,
 				StopFlood:      sequence.StopFlood,
 				StrobeSpeed:    sequence.StrobeSpeed,
@@ -453,6 +520,7 @@ func PlaySequence(sequence common.Sequence,
 					Type:            sequence.Type,
 					Label:           sequence.Label,
 					SequenceNumber:  sequence.Number,
+					FixturePage:     sequence.FixturePage,
 					RGBStaticFadeUp: true,
 					RGBFade:         sequence.RGBFade,
 					RGBStaticColors: sequence.StaticColors,
@@ -478,6 +546,7 @@ func PlaySequence(sequence common.Sequence,
 					Type:            sequence.Type,
 					Label:           sequence.Label,
 					SequenceNumber:  sequence.Number,
+					FixturePage:     sequence.FixturePage,
 					Hidden:          false,
 					StrobeSpeed:     sequence.StrobeSpeed,
 					Strobe:          sequence.Strobe,
@@ -508,6 +577,7 @@ func PlaySequence(sequence common.Sequence,
 				Type:            sequence.Type,
 				Label:           sequence.Label,
 				SequenceNumber:  sequence.Number,
+				FixturePage:     sequence.FixturePage,
 				Hidden:          sequence.Hidden,
 				StrobeSpeed:     sequence.StrobeSpeed,
 				Strobe:          sequence.Strobe,
@@ -558,7 +628,8 @@ func PlaySequence(sequence common.Sequence,
 
 				// Setup rgb patterns.
 				if sequence.Type == "rgb" {
-					RGBPattern := position.ApplyFixtureState(availablePatterns[sequence.SelectedPattern], sequence.FixtureState)
+					RGBPattern := pattern.FitPattern(availablePatterns[sequence.SelectedPattern], sequence.NumberFixtures, sequence.PatternFit)
+					RGBPattern = position.ApplyFixtureState(RGBPattern, sequence.FixtureState)
 					sequence.EnabledNumberFixtures = pattern.GetNumberEnabledScanners(sequence.FixtureState, sequence.NumberFixtures)
 					steps = RGBPattern.Steps
 					sequence.Pattern.Name = RGBPattern.Name
@@ -639,6 +710,8 @@ func PlaySequence(sequence common.Sequence,
 							}
 						}
 					}
+					// Put the steps in the order they are played, the same order for every scanner.
+					playSteps := orderSteps(steps, sequence.StepOrder, &lastPlayedStep)
 					for fixture := 0; fixture < sequence.NumberFixtures; fixture++ {
 						var positions map[int]common.Position
 						// We're playing out the scanner positions, so we won't need curve values.
@@ -650,9 +723,10 @@ func PlaySequence(sequence common.Sequence,
 						// Pass through the inverted / reverse flag.
 						sequence.ScannerReverse = sequence.FixtureState[fixture].ScannerPatternReversed
 						// Calulate positions for each scanner fixture.
-						fadeColors, totalNumberOfSteps := position.CalculatePositions(steps, sequence, common.IS_SCANNER)
+						fadeColors, totalNumberOfSteps := position.CalculatePositions(playSteps, sequence, common.IS_SCANNER)
 						positions, numberSteps := position.AssemblePositions(fadeColors, sequence.NumberFixtures, totalNumberOfSteps, sequence.FixtureState, sequence.Optimisation)
 						sequence.NumberSteps = numberSteps
+						numberPlayedSteps = countPlayedSteps(playSteps, sequence)
 
 						// Setup positions for each scanner. This is so we can shift the patterns on each scannner.
 						scannerPositions[fixture] = make(map[int]common.Position, 9)
@@ -724,9 +798,11 @@ func PlaySequence(sequence common.Sequence,
 					// Calulate positions for each RGB fixture.
 					sequence.Optimisation = true
 					var numberSteps int
-					fadeColors, totalNumberOfSteps := position.CalculatePositions(steps, sequence, common.IS_RGB)
+					playSteps := orderSteps(steps, sequence.StepOrder, &lastPlayedStep)
+					fadeColors, totalNumberOfSteps := position.CalculatePositions(playSteps, sequence, common.IS_RGB)
 					RGBPositions, numberSteps = position.AssemblePositions(fadeColors, sequence.NumberFixtures, totalNumberOfSteps, sequence.FixtureState, sequence.Optimisation)
 					sequence.NumberSteps = numberSteps
+					numberPlayedSteps = countPlayedSteps(playSteps, sequence)
 				}
 
 				// If we are setting the pattern automatically for rgb fixtures.
@@ -763,6 +839,11 @@ func PlaySequence(sequence common.Sequence,
 				// This is the inner loop where the sequence runs.
 				// Run through the steps in the sequence.
 				// Remember every step contains infomation for all the fixtures in this group.
+				// Each played step is made up of a number of fade steps.
+				fadeStepsPerStep := 1
+				if numberPlayedSteps > 0 && sequence.NumberSteps >= numberPlayedSteps {
+					fadeStepsPerStep = sequence.NumberSteps / numberPlayedSteps
+				}
 				for step := 0; step < sequence.NumberSteps; step++ {
 
 					// This is were we set the speed of the sequence to current speed.
@@ -770,7 +851,24 @@ func PlaySequence(sequence common.Sequence,
 					if sequence.Type == "scanner" {
 						speed = sequence.CurrentSpeed / 5 // Slow the scanners down.
 					}
-					sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, speed, sequence, channels, fixturesConfig)
+					// Swing every other played step.
+					speed = common.SwingSpeed(speed, sequence.Swing, step/fadeStepsPerStep)
+					if sequence.ClockSync && step%fadeStepsPerStep == 0 {
+						// Step with the master clock so we land on the beat with the other sequences.
+						sequence = commands.ListenCommandChannelAndWaitForClock(mySequenceNumber, sequence, channels, fixturesConfig)
+					} else if sequence.ClockSync {
+						// Fit the fade steps in before the clock's next step.
+						speed = clock.StepDuration(sequence.ClockMultiplier, sequence.ClockDivider) / time.Duration(fadeStepsPerStep)
+						sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, speed, sequence, channels, fixturesConfig)
+					} else {
+						sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, speed, sequence, channels, fixturesConfig)
+					}
+
+					// Hold this step while frozen, still taking commands so the next look can be set up.
+					for common.IsFrozen() {
+						sequence = commands.ListenCommandChannelAndWait(mySequenceNumber, FREEZE_POLL, sequence, channels, fixturesConfig)
+					}
+
 					if !sequence.Run || sequence.Clear || sequence.StartFlood || sequence.StopFlood || sequence.Static || sequence.UpdatePattern || sequence.UpdateShift || sequence.UpdateSize {
 						break
 					}
@@ -792,6 +890,7 @@ func PlaySequence(sequence common.Sequence,
 							Type:                     sequence.Type,
 							Label:                    sequence.Label,
 							SequenceNumber:           sequence.Number,
+							FixturePage:              sequence.FixturePage,
 							Step:                     step,
 							NumberSteps:              sequence.NumberSteps,
 							Rotate:                   sequence.Rotate,
@@ -824,6 +923,28 @@ func PlaySequence(sequence common.Sequence,
 	}
 }
 
+// orderSteps puts the pattern steps in the order they are played this cycle.
+// lastStep remembers the last step played so random orders don't repeat it next cycle.
+func orderSteps(steps []common.Step, order int, lastStep *int) []common.Step {
+	playOrder := common.StepPlayOrder(len(steps), order, *lastStep, rand.Intn)
+	playSteps := make([]common.Step, 0, len(playOrder))
+	for _, step := range playOrder {
+		playSteps = append(playSteps, steps[step])
+	}
+	if len(playOrder) > 0 {
+		*lastStep = playOrder[len(playOrder)-1]
+	}
+	return playSteps
+}
+
+// countPlayedSteps returns the number of steps played in a cycle, bounced steps are played twice.
+func countPlayedSteps(playSteps []common.Step, sequence common.Sequence) int {
+	if sequence.Bounce || sequence.ScannerReverse {
+		return len(playSteps) * 2
+	}
+	return len(playSteps)
+}
+
 // Send a command to all the fixtures.
 func sendToAllFixtures(fixtureChannels []chan common.FixtureCommand, command common.FixtureCommand) {
 	for _, fixture := range fixtureChannels {
@@ -887,21 +1008,21 @@ func getAvailableScannerColors(fixtures *fixture.Fixtures) (map[int][]common.Sta
 	scannerColors := make(map[int]int)
 
 	availableScannerColors := make(map[int][]common.StaticColorButton)
-	for _, fixture := range fixtures.Fixtures {
-		if fixture.Type == "scanner" {
-			for _, channel := range fixture.Channels {
+	for _, f := range fixtures.Fixtures {
+		if f.Type == "scanner" {
+			for _, channel := range f.Channels {
 				if strings.Contains(channel.Name, "Color") {
 					for _, setting := range channel.Settings {
 						newStaticColorButton := common.StaticColorButton{}
 						newStaticColorButton.SelectedColor = setting.Number
-						settingColor, err := common.GetRGBColorByName(setting.Name)
+						settingColor, err := fixture.GetWheelSettingColor(setting)
 						if err != nil {
 							fmt.Printf("error: %s\n", err)
 							continue
 						}
 						newStaticColorButton.Color = settingColor
-						availableScannerColors[fixture.Number] = append(availableScannerColors[fixture.Number], newStaticColorButton)
-						scannerColors[fixture.Number-1] = 0
+						availableScannerColors[f.Number] = append(availableScannerColors[f.Number], newStaticColorButton)
+						scannerColors[f.Number-1] = 0
 					}
 				}
 			}
@@ -1007,3 +1128,141 @@ func getAvailableScannerPattens(sequence common.Sequence) map[int]common.Pattern
 	return scannerPattens
 
 }
+
+// switchAdvancer keeps track of a switch stepping through its states on its own.
+type switchAdvancer struct {
+	step     int
+	position int           // The position we last saw, a button press moves the switch too.
+	since    time.Time     // When the switch got to this step.
+	frozen   time.Duration // Time spent frozen by then, so a freeze doesn't count towards the hold.
+	beats    int           // Beats heard since then.
+}
+
+// advanceSwitches moves on the first switch that has been on its step long enough,
+// any others due get their turn next time round the sequence loop.
+func advanceSwitches(sequence common.Sequence, advancers map[int]*switchAdvancer, soundConfig *sound.SoundConfig) common.Sequence {
+
+	// Switches hold their state while frozen, and beats heard while frozen don't count.
+	if common.IsFrozen() {
+		for _, swiTch := range sequence.Switches {
+			if swiTch.Advance != nil && swiTch.Advance.Mode == common.SWITCH_ADVANCE_BEAT {
+				countBeats(swiTch, soundConfig)
+			}
+		}
+		return sequence
+	}
+
+	for switchNumber := 0; switchNumber < len(sequence.Switches); switchNumber++ {
+
+		swiTch := sequence.Switches[switchNumber]
+		advance := swiTch.Advance
+		if advance == nil {
+			delete(advancers, switchNumber)
+			continue
+		}
+
+		// Start timing again if the switch has been moved by its button or is new.
+		advancer, found := advancers[switchNumber]
+		if !found || advancer.position != swiTch.CurrentPosition {
+			step := 0
+			if found {
+				step = advancer.step
+			}
+			advancer = &switchAdvancer{
+				step:     advance.StepOf(swiTch.CurrentPosition, step),
+				position: swiTch.CurrentPosition,
+				since:    time.Now(),
+				frozen:   common.FrozenTime(),
+			}
+			advancers[switchNumber] = advancer
+		}
+
+		if advance.Mode == common.SWITCH_ADVANCE_BEAT {
+			advancer.beats += countBeats(swiTch, soundConfig)
+		}
+
+		held := time.Since(advancer.since) - (common.FrozenTime() - advancer.frozen)
+		if !advance.Due(advancer.step, held, advancer.beats) {
+			continue
+		}
+
+		advancer.step = advance.Next(advancer.step)
+		advancer.position = advance.Steps[advancer.step].Position
+		advancer.since = time.Now()
+		advancer.frozen = common.FrozenTime()
+		advancer.beats = 0
+
+		if debug {
+			fmt.Printf("sequence %d switch %d advance to position %d\n", sequence.Number, switchNumber, advancer.position)
+		}
+
+		// Play the switch's new state, along with any switches it moves. A state that needs
+		// another switch set is skipped over until it is.
+		moved, err := common.MoveSwitch(sequence.Switches, switchNumber, advancer.position)
+		if err != nil {
+			if debug {
+				fmt.Printf("sequence %d switch %d can't advance: %s\n", sequence.Number, switchNumber, err.Error())
+			}
+			advancer.position = swiTch.CurrentPosition
+			continue
+		}
+		sequence.CurrentSwitch = moved[0]
+		sequence.SwitchesToPlay = moved[1:]
+		sequence.PlaySwitchOnce = true
+		sequence.PlaySingleSwitch = true
+		return sequence
+	}
+
+	return sequence
+}
+
+// How often a missing beat trigger is reported, the switch sequence asks for beats every few milliseconds.
+const BEAT_ERROR_INTERVAL = 10 * time.Second
+
+// When each missing beat trigger was last reported.
+var beatErrorsLock sync.Mutex
+var beatErrors = map[string]time.Time{}
+
+// countBeats takes the beats waiting on a switch's advance trigger. The advance trigger is the switch's own,
+// so a state with a music action still gets every beat on the switch trigger. It's kept enabled as
+// clearing the sound triggers can turn it off.
+func countBeats(swiTch common.Switch, soundConfig *sound.SoundConfig) int {
+
+	triggerName := sound.AdvanceTriggerName(swiTch.Number)
+
+	if !soundConfig.GetSoundTriggerState(triggerName) {
+		err := soundConfig.EnableSoundTrigger(triggerName)
+		if err != nil {
+			reportBeatError(triggerName, err)
+			return 0
+		}
+	}
+
+	beatChannel, err := soundConfig.GetSoundTriggerChannel(triggerName)
+	if err != nil {
+		reportBeatError(triggerName, err)
+		return 0
+	}
+
+	beats := 0
+	for {
+		select {
+		case <-beatChannel:
+			beats++
+		default:
+			return beats
+		}
+	}
+}
+
+// reportBeatError prints a beat trigger error, only now and again for the same trigger.
+func reportBeatError(triggerName string, err error) {
+	beatErrorsLock.Lock()
+	defer beatErrorsLock.Unlock()
+
+	if last, reported := beatErrors[triggerName]; reported && time.Since(last) < BEAT_ERROR_INTERVAL {
+		return
+	}
+	beatErrors[triggerName] = time.Now()
+	fmt.Printf("error: %s\n", err.Error())
+}
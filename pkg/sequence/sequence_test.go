@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/dhowlett99/dmxlights/pkg/common"
+	"github.com/dhowlett99/dmxlights/pkg/sound"
 )
 
 func Test_replaceRGBcolorsInSteps(t *testing.T) {
@@ -168,3 +169,138 @@ func Test_replaceRGBcolorsInSteps(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckSequencesFitLaunchpad(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		sequences []SequenceConfig
+		wantErr   bool
+	}{
+		{
+			name: "default five sequences with chaser last",
+			sequences: []SequenceConfig{
+				{Name: "sequence0", Label: "foh", Type: "rgb"},
+				{Name: "sequence1", Label: "uplighters", Type: "rgb"},
+				{Name: "sequence2", Label: "scanners", Type: "scanner"},
+				{Name: "sequence3", Label: "switch", Type: "switch"},
+				{Name: "sequence4", Label: "chaser", Type: "rgb"},
+			},
+		},
+		{
+			name: "two sequences",
+			sequences: []SequenceConfig{
+				{Name: "sequence0", Label: "foh", Type: "rgb"},
+				{Name: "sequence1", Label: "switch", Type: "switch"},
+			},
+		},
+		{
+			name: "seven groups are shown a page at a time",
+			sequences: []SequenceConfig{
+				{Name: "sequence0", Label: "foh", Type: "rgb"},
+				{Name: "sequence1", Label: "uplighters", Type: "rgb"},
+				{Name: "sequence2", Label: "backlights", Type: "rgb"},
+				{Name: "sequence3", Label: "truss", Type: "rgb"},
+				{Name: "sequence4", Label: "bar", Type: "rgb"},
+				{Name: "sequence5", Label: "stage", Type: "rgb"},
+				{Name: "sequence6", Label: "scanners", Type: "scanner"},
+				{Name: "sequence7", Label: "switch", Type: "switch"},
+				{Name: "sequence8", Label: "chaser", Type: "rgb"},
+			},
+		},
+		{
+			name: "two switch sequences",
+			sequences: []SequenceConfig{
+				{Name: "sequence0", Label: "foh", Type: "rgb"},
+				{Name: "sequence1", Label: "switch", Type: "switch"},
+				{Name: "sequence2", Label: "switch", Type: "switch"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "chaser without a scanner",
+			sequences: []SequenceConfig{
+				{Name: "sequence0", Label: "foh", Type: "rgb"},
+				{Name: "sequence1", Label: "chaser", Type: "rgb"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "no sequences",
+			sequences: []SequenceConfig{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSequencesFitLaunchpad(&SequencesConfig{Sequences: tt.sequences})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSequencesFitLaunchpad() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_countBeats(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		beats       int
+		advance     bool // Is there an advance trigger for the switch.
+		want        int
+		wantShared  int // Beats left for the states playing to the music.
+		wantEnabled bool
+	}{
+		{
+			name:        "beats waiting",
+			beats:       3,
+			advance:     true,
+			want:        3,
+			wantShared:  3,
+			wantEnabled: true,
+		},
+		{
+			name:        "no beats",
+			beats:       0,
+			advance:     true,
+			want:        0,
+			wantShared:  0,
+			wantEnabled: true,
+		},
+		{
+			name:       "no advance trigger",
+			beats:      2,
+			advance:    false,
+			want:       0,
+			wantShared: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			shared := &common.Trigger{Name: "switch1", Channel: make(chan common.Command, 10)}
+			advance := &common.Trigger{Name: sound.AdvanceTriggerName(1), Channel: make(chan common.Command, 10)}
+
+			soundConfig := &sound.SoundConfig{SoundTriggers: []*common.Trigger{shared}}
+			if tt.advance {
+				soundConfig.SoundTriggers = append(soundConfig.SoundTriggers, advance)
+			}
+
+			// The sound package sends every beat to each enabled trigger.
+			for beat := 0; beat < tt.beats; beat++ {
+				shared.Channel <- common.Command{}
+				advance.Channel <- common.Command{}
+			}
+
+			if got := countBeats(common.Switch{Number: 1}, soundConfig); got != tt.want {
+				t.Errorf("countBeats() = %v, want %v", got, tt.want)
+			}
+			if got := len(shared.Channel); got != tt.wantShared {
+				t.Errorf("countBeats() left %v beats on the switch trigger, want %v", got, tt.wantShared)
+			}
+			if advance.State != tt.wantEnabled {
+				t.Errorf("countBeats() advance trigger enabled = %v, want %v", advance.State, tt.wantEnabled)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dhowlett99/dmxlights/pkg/clock"
 	"github.com/dhowlett99/dmxlights/pkg/common"
 	"github.com/gordonklaus/portaudio"
 )
@@ -154,6 +155,9 @@ func (soundConfig *SoundConfig) StartSoundConfig(deviceName string, guiButtons c
 
 				if out[i] > actualGain {
 
+					// Let the master clock follow the beat.
+					clock.Beat(time.Now())
+
 					cmd := common.Command{}
 
 					for triggerNumber, trigger := range soundConfig.SoundTriggers {
@@ -182,6 +186,12 @@ func (soundConfig *SoundConfig) StartSoundConfig(deviceName string, guiButtons c
 	}()
 }
 
+// AdvanceTriggerName names the trigger a switch counts beats on when it moves to the next state on the beat.
+// It's separate from the switch's own trigger so the states playing to the music still get every beat.
+func AdvanceTriggerName(switchNumber int) string {
+	return fmt.Sprintf("switch%d.advance", switchNumber)
+}
+
 func (soundConfig *SoundConfig) GetDeviceName() string {
 	return soundConfig.deviceName
 }
@@ -226,6 +236,18 @@ func (soundConfig *SoundConfig) GetSoundTriggerState(name string) bool {
 	return false
 }
 
+// GetSoundTriggerChannel  - Find the channel this trigger sends beats on.
+func (soundConfig *SoundConfig) GetSoundTriggerChannel(name string) (chan common.Command, error) {
+
+	// Step through the existing sound triggers and find the one we want.
+	for _, trigger := range soundConfig.SoundTriggers {
+		if trigger.Name == name {
+			return trigger.Channel, nil
+		}
+	}
+	return nil, fmt.Errorf("sound trigger %s not found", name)
+}
+
 func (soundConfig *SoundConfig) getAvailableInputs() {
 	// Fire up the audio subsystem just to find the number of audio inputs.
 	err := portaudio.Initialize()